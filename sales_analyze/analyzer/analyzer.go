@@ -0,0 +1,147 @@
+// Package analyzer 分析的注册表。summary 等命令按名称启用的每一项分析都是一个 Analyzer，
+// 结果是与输出格式无关的 report.Section，因此自定义分析和内置分析一样可以输出为
+// 终端表格、JSON、HTML、Excel 等所有格式。
+//
+// 分析有两种来源:
+//
+//   - 编译进程序: 实现 Analyzer (或用 Func 包装一个函数)，在 init 中调用 Register；
+//   - 外部程序: 配置项 plugins 中列出的可执行文件，通过标准输入输出交换JSON，见 External。
+//
+// 内置的 overall、products、regions、dates 也通过 Register 注册，见 builtin.go。
+package analyzer
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"sales-analyzer/config"
+	"sales-analyzer/i18n"
+	"sales-analyzer/report"
+	"sales-analyzer/sales"
+)
+
+// Input 一次分析的输入，由调用方准备，所有启用的分析共用
+type Input struct {
+	Records []sales.Record // 清洗、筛选后的记录
+	Result  *sales.Result  // Records 并行汇总的结果
+	Config  *config.Config // 生效的配置，分析从中读取阈值等设置
+}
+
+// Analyzer 一项分析
+type Analyzer interface {
+	// Name 启用分析时使用的名称，也是章节的 ID
+	Name() string
+	// Analyze 生成报表章节
+	Analyze(in *Input) (report.Section, error)
+}
+
+// Func 把函数包装为名为 name 的 Analyzer
+func Func(name string, analyze func(in *Input) (report.Section, error)) Analyzer {
+	return funcAnalyzer{name, analyze}
+}
+
+type funcAnalyzer struct {
+	name    string
+	analyze func(in *Input) (report.Section, error)
+}
+
+func (f funcAnalyzer) Name() string { return f.name }
+
+func (f funcAnalyzer) Analyze(in *Input) (report.Section, error) {
+	return f.analyze(in)
+}
+
+var (
+	mu       sync.RWMutex
+	registry []Analyzer // 按注册顺序，报表中的章节也按这个顺序排列
+)
+
+// Register 注册一项分析。名称为空或与已注册的分析重名时 panic，通常在 init 中调用。
+func Register(a Analyzer) {
+	if err := register(a); err != nil {
+		panic(err)
+	}
+}
+
+func register(a Analyzer) error {
+	mu.Lock()
+	defer mu.Unlock()
+	name := a.Name()
+	if name == "" {
+		return i18n.Errorf("分析名称不能为空")
+	}
+	for _, registered := range registry {
+		if registered.Name() == name {
+			return i18n.Errorf("分析 %q 已经注册", name)
+		}
+	}
+	registry = append(registry, a)
+	return nil
+}
+
+// Lookup 按名称查找已注册的分析
+func Lookup(name string) (Analyzer, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, a := range registry {
+		if a.Name() == name {
+			return a, true
+		}
+	}
+	return nil, false
+}
+
+// Names 已注册的全部分析名称，按注册顺序
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, len(registry))
+	for i, a := range registry {
+		names[i] = a.Name()
+	}
+	return names
+}
+
+// Select 返回 names 中启用的分析，按注册顺序排列。有未注册的名称时返回错误。
+func Select(names []string) ([]Analyzer, error) {
+	enabled := make(map[string]bool, len(names))
+	for _, name := range names {
+		if _, ok := Lookup(name); !ok {
+			return nil, i18n.Errorf("reports: 未知的分析 %q (可选 %s)", name, strings.Join(Names(), "/"))
+		}
+		enabled[name] = true
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+	var selected []Analyzer
+	for _, a := range registry {
+		if enabled[a.Name()] {
+			selected = append(selected, a)
+		}
+	}
+	return selected, nil
+}
+
+// Run 依次运行 analyzers，返回各自的章节。任一分析失败时返回带分析名称的错误。
+// 每项分析的用时以 debug 级别写入日志。
+func Run(analyzers []Analyzer, in *Input) ([]report.Section, error) {
+	sections := make([]report.Section, 0, len(analyzers))
+	for _, a := range analyzers {
+		start := time.Now()
+		section, err := a.Analyze(in)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", a.Name(), err)
+		}
+		elapsed := time.Since(start)
+		slog.Debug(i18n.Sprintf("🧮 分析 %s 完成，用时 %s", a.Name(), elapsed.Round(time.Microsecond)), "analyzer", a.Name(), "duration", elapsed)
+		if section.ID == "" {
+			section.ID = a.Name()
+		}
+		sections = append(sections, section)
+	}
+	return sections, nil
+}
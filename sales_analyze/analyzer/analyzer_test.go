@@ -0,0 +1,134 @@
+package analyzer
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"sales-analyzer/report"
+)
+
+// isolate 测试结束后恢复注册表，测试中注册的分析不影响其他测试
+func isolate(t *testing.T) {
+	t.Helper()
+	mu.Lock()
+	saved := slices.Clone(registry)
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		registry = saved
+		mu.Unlock()
+	})
+}
+
+// stub 返回标题为 title 的章节的分析
+func stub(name, title string) Analyzer {
+	return Func(name, func(in *Input) (report.Section, error) {
+		return report.Section{Title: title}, nil
+	})
+}
+
+func names(analyzers []Analyzer) []string {
+	result := make([]string, len(analyzers))
+	for i, a := range analyzers {
+		result[i] = a.Name()
+	}
+	return result
+}
+
+func TestRegister(t *testing.T) {
+	isolate(t)
+
+	if err := register(stub("custom", "")); err != nil {
+		t.Fatalf("register(custom): %v", err)
+	}
+	if _, ok := Lookup("custom"); !ok {
+		t.Error("注册后 Lookup(custom) 找不到")
+	}
+	if err := register(stub("custom", "")); err == nil {
+		t.Error("重名的分析应返回错误")
+	}
+	if err := register(stub("overall", "")); err == nil {
+		t.Error("与内置分析重名应返回错误")
+	}
+	if err := register(stub("", "")); err == nil {
+		t.Error("名称为空应返回错误")
+	}
+
+	want := []string{"overall", "products", "regions", "dates", "custom"}
+	if got := Names(); !slices.Equal(got, want) {
+		t.Errorf("Names() = %v，应为 %v", got, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Register 重名时应 panic")
+		}
+	}()
+	Register(stub("custom", ""))
+}
+
+func TestSelect(t *testing.T) {
+	isolate(t)
+	Register(stub("custom", ""))
+
+	tests := []struct {
+		name    string
+		names   []string
+		want    []string
+		wantErr bool
+	}{
+		{"按注册顺序", []string{"dates", "custom", "overall"}, []string{"overall", "dates", "custom"}, false},
+		{"重复的名称", []string{"regions", "regions"}, []string{"regions"}, false},
+		{"不启用", nil, nil, false},
+		{"未知的分析", []string{"overall", "weekdays"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := Select(tt.names)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Select(%v) 错误 = %v，应为错误 %v", tt.names, err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "weekdays") {
+				t.Errorf("错误中应有未知的名称: %v", err)
+			}
+			if got := names(selected); !slices.Equal(got, tt.want) {
+				t.Errorf("Select(%v) = %v，应为 %v", tt.names, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	failure := errors.New("数据不足")
+	analyzers := []Analyzer{
+		stub("first", "第一"),
+		Func("second", func(in *Input) (report.Section, error) {
+			return report.Section{ID: "custom-id", Title: "第二"}, nil
+		}),
+	}
+
+	sections, err := Run(analyzers, &Input{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, section := range sections {
+		ids = append(ids, section.ID)
+	}
+	if want := []string{"first", "custom-id"}; !slices.Equal(ids, want) {
+		t.Errorf("章节ID = %v，应为 %v", ids, want)
+	}
+
+	analyzers = append(analyzers, Func("broken", func(in *Input) (report.Section, error) {
+		return report.Section{}, failure
+	}))
+	sections, err = Run(analyzers, &Input{})
+	if !errors.Is(err, failure) || !strings.HasPrefix(err.Error(), "broken: ") {
+		t.Errorf("Run() 错误 = %v，应为带分析名称的 %v", err, failure)
+	}
+	if sections != nil {
+		t.Errorf("失败时不应返回章节: %+v", sections)
+	}
+}
@@ -0,0 +1,25 @@
+package analyzer
+
+import (
+	"sales-analyzer/report"
+	"sales-analyzer/sales"
+)
+
+// 内置分析，名称与 config.Reports 一致
+func init() {
+	Register(Func("overall", func(in *Input) (report.Section, error) {
+		return report.OverallSection(sales.AnalyzeOverall(in.Result)), nil
+	}))
+	Register(Func("products", func(in *Input) (report.Section, error) {
+		return report.ProductSection(sales.AnalyzeByProduct(in.Result)), nil
+	}))
+	Register(Func("regions", func(in *Input) (report.Section, error) {
+		return report.RegionSection(sales.AnalyzeByRegion(in.Result)), nil
+	}))
+	Register(Func("dates", func(in *Input) (report.Section, error) {
+		trend := sales.AnalyzeByDate(in.Result)
+		section := report.DateSection(trend)
+		section.Notes = append(section.Notes, report.DropNotes(trend, in.Config.Thresholds.DailyDrop)...)
+		return section, nil
+	}))
+}
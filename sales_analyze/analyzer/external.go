@@ -0,0 +1,269 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"sales-analyzer/i18n"
+	"sales-analyzer/report"
+	"sales-analyzer/sales"
+)
+
+// External 外部分析程序，可以用任何语言编写。每次分析启动一次程序:
+// 标准输入写入一个JSON请求，程序从标准输出写回一个JSON章节后以退出码0结束。
+// 程序在标准错误输出的每一行作为警告写入日志，可以用来输出诊断信息；
+// 程序失败 (退出码非0或超时) 时标准错误的内容附在返回的错误中。
+//
+// 请求:
+//
+//	{"name": "weekdays", "locale": "zh-CN",
+//	 "records": [{"date": "2024-01-01", "product": "手机", "quantity": 2, "amount": 5998, "region": "华东"}, ...],
+//	 "overall": {"total_amount": ..., "total_quantity": ..., "avg_amount": ..., "orders": ...}}
+//
+// 响应 (除 title 外都可以省略):
+//
+//	{"title": "星期分析",
+//	 "intro": [{"level": "info", "text": "..."}],
+//	 "kpis": [{"label": "周末占比", "value": 28.5, "kind": "percent"}],
+//	 "charts": [{"kind": "bar", "title": "...", "labels": ["周一", ...], "values": [1200, ...], "value_kind": "money"}],
+//	 "tables": [{"title": "...", "columns": ["星期", "销售额"], "kinds": ["text", "money"], "rows": [["周一", 1200], ...]}],
+//	 "notes": [{"level": "warning", "text": "..."}],
+//	 "data": {...}}
+//
+// kind 为 text/int/money/percent/change/money_change/int_change，与 report.Kind 对应；
+// 金额和数字按当前语言区域格式化，外部程序只需要给出原始数值。level 为 info/success/warning。
+// data 是任意JSON，JSON输出时原样作为章节的结构化结果。
+type External struct {
+	name    string
+	Path    string
+	Timeout time.Duration // 每次运行的超时时间，超时后终止程序；0 表示不限制
+}
+
+// NewExternal 外部分析程序 path。分析名称为去掉扩展名和 "sales-" 前缀的文件名，
+// 如 plugins/sales-weekdays → weekdays。
+func NewExternal(path string) *External {
+	name := filepath.Base(path)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	return &External{name: strings.TrimPrefix(name, "sales-"), Path: path}
+}
+
+// LoadPlugins 检查并注册外部分析程序，每次运行的超时时间为 timeout，见 config.Config.Plugins
+func LoadPlugins(paths []string, timeout time.Duration) error {
+	for _, path := range paths {
+		if _, err := exec.LookPath(path); err != nil {
+			return fmt.Errorf("plugins: %w", err)
+		}
+		e := NewExternal(path)
+		e.Timeout = timeout
+		if err := register(e); err != nil {
+			return fmt.Errorf("plugins: %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Name 实现 Analyzer
+func (e *External) Name() string { return e.name }
+
+// request 写入外部程序标准输入的JSON
+type request struct {
+	Name    string         `json:"name"`
+	Locale  string         `json:"locale"`
+	Records []sales.Record `json:"records"`
+	Overall sales.Overall  `json:"overall"`
+}
+
+// response 外部程序在标准输出写回的JSON
+type response struct {
+	Title  string          `json:"title"`
+	Intro  []report.Note   `json:"intro"`
+	KPIs   []kpi           `json:"kpis"`
+	Charts []chart         `json:"charts"`
+	Tables []table         `json:"tables"`
+	Notes  []report.Note   `json:"notes"`
+	Data   json.RawMessage `json:"data"`
+}
+
+type kpi struct {
+	Label string `json:"label"`
+	Value any    `json:"value"`
+	Kind  string `json:"kind"`
+	Unit  string `json:"unit"`
+}
+
+type chart struct {
+	Kind      report.ChartKind `json:"kind"`
+	Title     string           `json:"title"`
+	Labels    []string         `json:"labels"`
+	Values    []float64        `json:"values"`
+	ValueKind string           `json:"value_kind"`
+}
+
+type table struct {
+	Title   string   `json:"title"`
+	Columns []string `json:"columns"`
+	Kinds   []string `json:"kinds"` // 各列的数值类型，省略时为 text
+	Rows    [][]any  `json:"rows"`
+}
+
+// kinds 响应中数值类型的名称
+var kinds = map[string]report.Kind{
+	"":             report.KindText,
+	"text":         report.KindText,
+	"int":          report.KindInt,
+	"money":        report.KindMoney,
+	"percent":      report.KindPercent,
+	"change":       report.KindChange,
+	"money_change": report.KindMoneyChange,
+	"int_change":   report.KindIntChange,
+}
+
+// Analyze 实现 Analyzer: 运行外部程序并把响应转换为章节
+func (e *External) Analyze(in *Input) (report.Section, error) {
+	var stdin, stdout, stderr bytes.Buffer
+	err := json.NewEncoder(&stdin).Encode(request{
+		Name:    e.name,
+		Locale:  i18n.Current().Tag,
+		Records: in.Records,
+		Overall: sales.AnalyzeOverall(in.Result),
+	})
+	if err != nil {
+		return report.Section{}, err
+	}
+
+	ctx := context.Background()
+	if e.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.Timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, e.Path)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = &stdin, &stdout, &stderr
+	// 程序被终止后，它启动的子进程可能仍占用输出管道，最多再等待一秒
+	cmd.WaitDelay = time.Second
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = i18n.Errorf("超过 %s 未完成", e.Timeout)
+		}
+		if output := strings.TrimSpace(stderr.String()); output != "" {
+			return report.Section{}, i18n.Errorf("外部分析程序 %s 运行失败: %w\n%s", e.Path, err, output)
+		}
+		return report.Section{}, i18n.Errorf("外部分析程序 %s 运行失败: %w", e.Path, err)
+	}
+	for line := range strings.Lines(stderr.String()) {
+		if line = strings.TrimSpace(line); line != "" {
+			slog.Warn(e.name+": "+line, "analyzer", e.name, "output", line)
+		}
+	}
+
+	var resp response
+	decoder := json.NewDecoder(&stdout)
+	// 拼错的字段直接报错，而不是被静默忽略
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&resp); err != nil {
+		return report.Section{}, i18n.Errorf("外部分析程序 %s 的输出格式错误: %w", e.Path, err)
+	}
+	section, err := resp.section(e.name)
+	if err != nil {
+		return report.Section{}, i18n.Errorf("外部分析程序 %s 的输出格式错误: %w", e.Path, err)
+	}
+	return section, nil
+}
+
+// section 检查响应并转换为章节
+func (r *response) section(id string) (report.Section, error) {
+	if r.Title == "" {
+		return report.Section{}, i18n.Errorf("缺少 title")
+	}
+	section := report.Section{ID: id, Title: r.Title}
+
+	var err error
+	if section.Intro, err = notes(r.Intro); err != nil {
+		return report.Section{}, err
+	}
+	if section.Notes, err = notes(r.Notes); err != nil {
+		return report.Section{}, err
+	}
+	for _, k := range r.KPIs {
+		kind, ok := kinds[k.Kind]
+		if !ok {
+			return report.Section{}, i18n.Errorf("kpis: 未知的 kind %q", k.Kind)
+		}
+		section.KPIs = append(section.KPIs, report.KPI{Label: k.Label, Value: report.Cell{Value: k.Value, Kind: kind, Unit: k.Unit}})
+	}
+	for _, c := range r.Charts {
+		kind, ok := kinds[c.ValueKind]
+		if !ok {
+			return report.Section{}, i18n.Errorf("charts: 未知的 value_kind %q", c.ValueKind)
+		}
+		if c.Kind != report.ChartBar && c.Kind != report.ChartLine {
+			return report.Section{}, i18n.Errorf("charts: 未知的图表类型 %q (可选 bar/line)", c.Kind)
+		}
+		if len(c.Labels) != len(c.Values) {
+			return report.Section{}, i18n.Errorf("charts: labels 与 values 的数量不一致")
+		}
+		section.Charts = append(section.Charts, report.Chart{
+			Kind: c.Kind, Title: c.Title, Labels: c.Labels, Values: c.Values, ValueKind: kind,
+		})
+	}
+	for _, t := range r.Tables {
+		converted, err := t.table()
+		if err != nil {
+			return report.Section{}, err
+		}
+		section.Tables = append(section.Tables, converted)
+	}
+	if len(r.Data) > 0 && string(r.Data) != "null" {
+		section.Data = r.Data
+	}
+	return section, nil
+}
+
+func (t table) table() (report.Table, error) {
+	if len(t.Kinds) > len(t.Columns) {
+		return report.Table{}, i18n.Errorf("tables: kinds 多于 columns")
+	}
+	columnKinds := make([]report.Kind, len(t.Columns))
+	for i, name := range t.Kinds {
+		kind, ok := kinds[name]
+		if !ok {
+			return report.Table{}, i18n.Errorf("tables: 未知的 kind %q", name)
+		}
+		columnKinds[i] = kind
+	}
+
+	converted := report.Table{Title: t.Title, Columns: t.Columns}
+	for i, row := range t.Rows {
+		if len(row) != len(t.Columns) {
+			return report.Table{}, i18n.Errorf("tables: 第 %d 行有 %d 列，表头有 %d 列", i+1, len(row), len(t.Columns))
+		}
+		cells := make([]report.Cell, len(row))
+		for j, value := range row {
+			cells[j] = report.Cell{Value: value, Kind: columnKinds[j]}
+		}
+		converted.Rows = append(converted.Rows, cells)
+	}
+	return converted, nil
+}
+
+func notes(in []report.Note) ([]report.Note, error) {
+	var out []report.Note
+	for _, note := range in {
+		switch note.Level {
+		case "":
+			note.Level = report.LevelInfo
+		case report.LevelInfo, report.LevelSuccess, report.LevelWarning:
+		default:
+			return nil, i18n.Errorf("未知的 level %q (可选 info/success/warning)", note.Level)
+		}
+		out = append(out, note)
+	}
+	return out, nil
+}
@@ -0,0 +1,208 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"sales-analyzer/report"
+	"sales-analyzer/sales"
+)
+
+// pluginEnv 设置后测试程序本身作为外部分析程序运行，值为行为:
+//
+//	reply  把 pluginReplyEnv 的内容原样写到标准输出
+//	echo   读取请求，返回记录数和名称
+//	fail   在标准错误输出后以退出码 3 结束
+//	sleep  一直不结束，用于测试超时
+const (
+	pluginEnv      = "SALES_TEST_PLUGIN"
+	pluginReplyEnv = "SALES_TEST_PLUGIN_REPLY"
+)
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(pluginEnv); mode != "" {
+		os.Exit(plugin(mode))
+	}
+	os.Exit(m.Run())
+}
+
+func plugin(mode string) int {
+	switch mode {
+	case "reply":
+		fmt.Print(os.Getenv(pluginReplyEnv))
+	case "echo":
+		var req request
+		if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Fprintln(os.Stderr, "诊断信息")
+		json.NewEncoder(os.Stdout).Encode(map[string]any{
+			"title": req.Name,
+			"kpis":  []map[string]any{{"label": "记录数", "value": len(req.Records), "kind": "int"}},
+			"data":  map[string]any{"orders": req.Overall.Orders},
+		})
+	case "fail":
+		fmt.Fprintln(os.Stderr, "数据不足")
+		return 3
+	case "sleep":
+		time.Sleep(time.Minute)
+	}
+	return 0
+}
+
+// testPlugin 以 mode 运行测试程序本身的外部分析
+func testPlugin(t *testing.T, mode string) *External {
+	t.Helper()
+	t.Setenv(pluginEnv, mode)
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewExternal(executable)
+	e.name = "weekdays"
+	e.Timeout = 10 * time.Second
+	return e
+}
+
+func testInput() *Input {
+	records := []sales.Record{
+		{Date: "2025-01-01", Product: "手机", Quantity: 2, Amount: 5998, Region: "华东"},
+		{Date: "2025-01-02", Product: "电脑", Quantity: 1, Amount: 6999, Region: "华南"},
+	}
+	return &Input{Records: records, Result: sales.Aggregate(records, sales.Options{})}
+}
+
+func TestNewExternal(t *testing.T) {
+	tests := []struct{ path, want string }{
+		{"plugins/sales-weekdays", "weekdays"},
+		{"plugins/sales-weekdays.exe", "weekdays"},
+		{"/usr/local/bin/weekdays.py", "weekdays"},
+		{"sales-", ""},
+	}
+	for _, tt := range tests {
+		if got := NewExternal(tt.path).Name(); got != tt.want {
+			t.Errorf("NewExternal(%q).Name() = %q，应为 %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestExternal(t *testing.T) {
+	t.Run("请求与响应", func(t *testing.T) {
+		section, err := testPlugin(t, "echo").Analyze(testInput())
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := report.Section{
+			ID:    "weekdays",
+			Title: "weekdays",
+			KPIs:  []report.KPI{{Label: "记录数", Value: report.Cell{Value: float64(2), Kind: report.KindInt}}},
+			Data:  json.RawMessage(`{"orders":2}`),
+		}
+		if !reflect.DeepEqual(section, want) {
+			t.Errorf("章节 = %+v，应为 %+v", section, want)
+		}
+	})
+
+	t.Run("退出码非0", func(t *testing.T) {
+		_, err := testPlugin(t, "fail").Analyze(testInput())
+		if err == nil {
+			t.Fatal("程序失败时应返回错误")
+		}
+		if !strings.Contains(err.Error(), "exit status 3") || !strings.Contains(err.Error(), "数据不足") {
+			t.Errorf("错误中应有退出码和标准错误的内容: %v", err)
+		}
+	})
+
+	t.Run("超时", func(t *testing.T) {
+		e := testPlugin(t, "sleep")
+		e.Timeout = 100 * time.Millisecond
+		start := time.Now()
+		_, err := e.Analyze(testInput())
+		if err == nil {
+			t.Fatal("超时应返回错误")
+		}
+		if !strings.Contains(err.Error(), "100ms") {
+			t.Errorf("错误中应有超时时间: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("超时后 %s 才返回", elapsed)
+		}
+	})
+
+	for _, tt := range []struct{ name, reply string }{
+		{"不是JSON", "not json"},
+		{"空输出", ""},
+		{"未知的字段", `{"title": "星期分析", "chart": []}`},
+		{"缺少标题", `{"kpis": []}`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			e := testPlugin(t, "reply")
+			t.Setenv(pluginReplyEnv, tt.reply)
+			if _, err := e.Analyze(testInput()); err == nil {
+				t.Errorf("输出 %q 应返回错误", tt.reply)
+			}
+		})
+	}
+}
+
+func TestResponseSection(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    report.Section
+		wantErr bool
+	}{
+		{"只有标题", `{"title": "星期分析"}`, report.Section{ID: "weekdays", Title: "星期分析"}, false},
+		{"完整", `{
+			"title": "星期分析",
+			"intro": [{"text": "说明"}],
+			"kpis": [{"label": "周末占比", "value": 28.5, "kind": "percent"}],
+			"charts": [{"kind": "bar", "title": "销售额", "labels": ["周一", "周二"], "values": [1200, 800], "value_kind": "money"}],
+			"tables": [{"title": "明细", "columns": ["星期", "销售额", "备注"], "kinds": ["text", "money"], "rows": [["周一", 1200, null]]}],
+			"notes": [{"level": "warning", "text": "周二下降"}],
+			"data": null}`,
+			report.Section{
+				ID:    "weekdays",
+				Title: "星期分析",
+				Intro: []report.Note{{Level: report.LevelInfo, Text: "说明"}},
+				KPIs:  []report.KPI{{Label: "周末占比", Value: report.Cell{Value: 28.5, Kind: report.KindPercent}}},
+				Charts: []report.Chart{{Kind: report.ChartBar, Title: "销售额", Labels: []string{"周一", "周二"},
+					Values: []float64{1200, 800}, ValueKind: report.KindMoney}},
+				Tables: []report.Table{{Title: "明细", Columns: []string{"星期", "销售额", "备注"}, Rows: [][]report.Cell{{
+					{Value: "周一", Kind: report.KindText}, {Value: float64(1200), Kind: report.KindMoney}, {Kind: report.KindText},
+				}}}},
+				Notes: []report.Note{{Level: report.LevelWarning, Text: "周二下降"}},
+			}, false},
+		{"缺少标题", `{"kpis": [{"label": "a", "value": 1}]}`, report.Section{}, true},
+		{"未知的KPI类型", `{"title": "t", "kpis": [{"label": "a", "value": 1, "kind": "ratio"}]}`, report.Section{}, true},
+		{"未知的图表类型", `{"title": "t", "charts": [{"kind": "pie", "labels": [], "values": []}]}`, report.Section{}, true},
+		{"未知的图表数值类型", `{"title": "t", "charts": [{"kind": "bar", "value_kind": "ratio"}]}`, report.Section{}, true},
+		{"标签与数值数量不一致", `{"title": "t", "charts": [{"kind": "line", "labels": ["a", "b"], "values": [1]}]}`, report.Section{}, true},
+		{"未知的列类型", `{"title": "t", "tables": [{"columns": ["a"], "kinds": ["ratio"]}]}`, report.Section{}, true},
+		{"列类型多于列", `{"title": "t", "tables": [{"columns": ["a"], "kinds": ["text", "int"]}]}`, report.Section{}, true},
+		{"行的列数不一致", `{"title": "t", "tables": [{"columns": ["a", "b"], "rows": [["x", 1], ["y"]]}]}`, report.Section{}, true},
+		{"未知的说明级别", `{"title": "t", "notes": [{"level": "error", "text": "x"}]}`, report.Section{}, true},
+		{"未知的简介级别", `{"title": "t", "intro": [{"level": "debug", "text": "x"}]}`, report.Section{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp response
+			if err := json.Unmarshal([]byte(tt.json), &resp); err != nil {
+				t.Fatal(err)
+			}
+			got, err := resp.section("weekdays")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("section() 错误 = %v，应为错误 %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("section() = %+v，应为 %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,465 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"sales-analyzer/analyzer"
+	"sales-analyzer/config"
+	"sales-analyzer/diff"
+	"sales-analyzer/i18n"
+	"sales-analyzer/progress"
+	"sales-analyzer/report"
+	"sales-analyzer/sales"
+	"sales-analyzer/scenario"
+	"sales-analyzer/server"
+	"sales-analyzer/target"
+	"sales-analyzer/tui"
+	"sales-analyzer/watch"
+)
+
+// runSummary 完整分析报告
+func runSummary(args []string) error {
+	o := newOptions("summary")
+	out := o.outputFlags()
+	out.watchFlags()
+	o.summaryFlags()
+	scenarioFile := o.flags.String("scenario", "", i18n.T("what-if情景文件 (JSON)，与基准对比展示"))
+	if err := o.parse(args); err != nil {
+		return err
+	}
+	cfg := o.cfg
+	analyzers, err := o.analyzers()
+	if err != nil {
+		return err
+	}
+
+	reporter, err := out.reporter()
+	if err != nil {
+		return err
+	}
+
+	return out.generate(reporter, func(records []sales.Record) (*report.Report, error) {
+		// 并行汇总一次，供所有分析共用
+		aggOpts := sales.Options{
+			Workers: cfg.Workers,
+			Sketches: sales.SketchOptions{
+				DistinctError: cfg.Thresholds.DistinctError,
+				QuantileError: cfg.Thresholds.QuantileError,
+			},
+		}
+		p := o.newProgress()
+		bar := p.AddRows(i18n.T("汇总"), len(records))
+		opts := aggOpts
+		opts.Progress = bar.AddRows
+		result := sales.Aggregate(records, opts)
+		bar.Done()
+		p.Stop()
+
+		rep := newReport(o, records)
+		sections, err := analyzer.Run(analyzers, &analyzer.Input{Records: records, Result: result, Config: &cfg.Config})
+		if err != nil {
+			return nil, fail("❌ 分析失败: %v\n", err)
+		}
+		rep.Sections = append(rep.Sections, sections...)
+
+		if result.Sketches != nil {
+			rep.Sections = append(rep.Sections, report.SketchSection(result.Sketches.Summarize()))
+		}
+
+		if cfg.Targets != "" {
+			targets, err := target.Load(cfg.Targets)
+			if err != nil {
+				return nil, fail("❌ 读取目标失败: %v\n", err)
+			}
+			analysis, err := target.Analyze(targets, result)
+			if err != nil {
+				return nil, fail("❌ 目标分析失败: %v\n", err)
+			}
+			rep.Sections = append(rep.Sections, report.TargetSection(analysis))
+		}
+
+		if *scenarioFile != "" {
+			sc, err := scenario.Load(*scenarioFile)
+			if err != nil {
+				return nil, fail("❌ 读取情景失败: %v\n", err)
+			}
+			simulated, affected := sc.Apply(records)
+			rep.Sections = append(rep.Sections, report.ScenarioSection(report.ScenarioResult{
+				Scenario:   sc,
+				Affected:   affected,
+				Comparison: sales.Compare(result, sales.Aggregate(simulated, aggOpts)),
+			}))
+		}
+		return rep, nil
+	})
+}
+
+// summaryFlags 注册 summary 命令对应配置项的参数
+func (o *options) summaryFlags() {
+	o.configFlag("reports", "reports", i18n.Sprintf("输出的分析，逗号分隔: %s 或 -plugins 注册的外部分析", strings.Join(config.Reports, "/")))
+	o.configFlag("plugins", "plugins", i18n.T("外部分析程序 (通过标准输入输出交换JSON，见 analyzer 包)，可以重复指定或用逗号分隔；分析名称为去掉 sales- 前缀的文件名"))
+	o.configFlag("plugin-timeout", "plugin_timeout", i18n.T("每次运行外部分析程序的超时秒数 (0 表示不限制)"))
+	o.configFlag("workers", "workers", i18n.T("并行汇总的工作协程数 (0 表示CPU核数)"))
+	o.configFlag("targets", "targets", i18n.T("月度销售目标CSV文件 (月份,地区,产品,目标销售额)"))
+	o.configFlag("daily-drop", "thresholds.daily_drop", i18n.T("日销售额较前一日下降超过该百分比时提示，如 20 (0 表示不提示)"))
+	o.configFlag("distinct-error", "thresholds.distinct_error", i18n.T("近似去重计数的相对误差，如 0.01 (0 表示不启用)"))
+	o.configFlag("quantile-error", "thresholds.quantile_error", i18n.T("近似分位数的秩误差，如 0.01 (0 表示不启用)"))
+}
+
+// sectionCommand 只输出一个分析章节的命令
+func sectionCommand(id string) func(args []string) error {
+	return func(args []string) error {
+		o := newOptions(id)
+		out := o.outputFlags()
+		out.watchFlags()
+		if id == "dates" {
+			o.configFlag("daily-drop", "thresholds.daily_drop", i18n.T("日销售额较前一日下降超过该百分比时提示，如 20 (0 表示不提示)"))
+		}
+		if err := o.parse(args); err != nil {
+			return err
+		}
+		// 与 summary 一样注册外部分析程序并检查 reports，同一份配置在两种命令中的行为一致
+		if _, err := o.analyzers(); err != nil {
+			return err
+		}
+
+		reporter, err := out.reporter()
+		if err != nil {
+			return err
+		}
+		return out.generate(reporter, func(records []sales.Record) (*report.Report, error) {
+			a, _ := analyzer.Lookup(id)
+			in := &analyzer.Input{Records: records, Result: sales.Aggregate(records, sales.Options{}), Config: &o.cfg.Config}
+			sections, err := analyzer.Run([]analyzer.Analyzer{a}, in)
+			if err != nil {
+				return nil, fail("❌ 分析失败: %v\n", err)
+			}
+			rep := newReport(o, records)
+			rep.Sections = append(rep.Sections, sections...)
+			return rep, nil
+		})
+	}
+}
+
+// exportFormats export 命令支持的格式
+var exportFormats = []string{"csv", "json", "xlsx"}
+
+// runExport 导出清洗、筛选后的记录。CSV使用与输入文件相同的表头，可以再次作为输入。
+func runExport(args []string) error {
+	o := newOptions("export")
+	format := o.flags.String("format", "csv", i18n.Sprintf("导出格式: %s", strings.Join(exportFormats, "/")))
+	path := o.flags.String("o", "", i18n.T("输出文件，默认为标准输出 (xlsx 必须指定)"))
+	if err := o.parse(args); err != nil {
+		return err
+	}
+
+	var write func(w io.Writer, records []sales.Record) error
+	switch strings.ToLower(*format) {
+	case "csv":
+		write = sales.WriteCSV
+	case "json":
+		write = writeJSON
+	case "xlsx", "excel":
+		if *path == "" {
+			return usagef("❌ %s 格式需要用 -o 指定输出文件\n", *format)
+		}
+		write = func(w io.Writer, records []sales.Record) error {
+			return (&report.XLSXReporter{Records: records}).Render(w, &report.Report{})
+		}
+	default:
+		return usagef("❌ %v\n", i18n.Errorf("不支持的导出格式: %q (可选 %s)", *format, strings.Join(exportFormats, "/")))
+	}
+
+	records, err := o.load()
+	if err != nil {
+		return err
+	}
+	return writeOutput(*path, func(w io.Writer) error {
+		return write(w, records)
+	})
+}
+
+func writeJSON(w io.Writer, records []sales.Record) error {
+	if records == nil {
+		records = []sales.Record{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+// runValidate 检查所有输入文件: 除了读取时会跳过的行，还检查日期格式、
+// 负数和空字段。发现问题时逐行列出，并以 exitInvalid 退出。
+func runValidate(args []string) error {
+	o := newOptions("validate")
+	if err := o.parse(args); err != nil {
+		return err
+	}
+
+	inputs, err := (&watch.Inputs{Paths: o.cfg.Inputs}).Files()
+	if err != nil {
+		return fail("❌ 读取数据失败: %v\n", err)
+	}
+
+	problems, total, err := validateFiles(o, inputs)
+	if err != nil {
+		return fail("❌ 读取数据失败: %v\n", err)
+	}
+	if len(problems) == 0 {
+		fmt.Println(i18n.Sprintf("✅ 共 %d 行数据，没有发现问题", total))
+		return nil
+	}
+	for _, p := range problems {
+		slog.Warn(i18n.Sprintf("⚠️  %s", p), "file", p.File, "line", p.Line, "problem", p.Message)
+	}
+	slog.Error(i18n.Sprintf("❌ 共 %d 行数据，发现 %d 个问题", total, len(problems)), "rows", total, "problems", len(problems))
+	return exitWith(exitInvalid)
+}
+
+// validateFiles 逐个检查输入文件，返回按文件和行号排列的问题以及总行数。
+// 返回时进度显示已经清除，之后的输出不会与进度条混在一起。
+func validateFiles(o *options, inputs []string) (problems []sales.RowError, total int, err error) {
+	p := o.newProgress()
+	defer p.Stop()
+	for _, input := range inputs {
+		var found []sales.RowError
+		skipped, err := scanFile(o.cfg.Schema, input, p, func(line int, record sales.Record) {
+			total++
+			for _, message := range sales.Validate(record) {
+				found = append(found, sales.RowError{File: input, Line: line, Message: message})
+			}
+		})
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", input, err)
+		}
+		total += len(skipped)
+		// 按行号列出
+		found = append(found, skipped...)
+		sort.SliceStable(found, func(i, j int) bool { return found[i].Line < found[j].Line })
+		problems = append(problems, found...)
+	}
+	return problems, total, nil
+}
+
+// scanFile 与 Schema.ScanFile 相同，读取进度显示在 p 中
+func scanFile(schema sales.Schema, name string, p *progress.Progress, visit func(line int, record sales.Record)) ([]sales.RowError, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, i18n.Errorf("无法打开文件: %w", err)
+	}
+	defer file.Close()
+	var size int64
+	if info, err := file.Stat(); err == nil {
+		size = info.Size()
+	}
+
+	bar := p.Add(filepath.Base(name), size)
+	defer bar.Done()
+	rowErrs, err := schema.Scan(bar.Reader(file), func(line int, record sales.Record) {
+		bar.AddRows(1)
+		visit(line, record)
+	})
+	for i := range rowErrs {
+		rowErrs[i].File = name
+	}
+	return rowErrs, err
+}
+
+// runDiff 对比两份数据或同一数据的两个时间段
+func runDiff(args []string) error {
+	o := newOptions("diff")
+	out := o.outputFlags()
+	base := o.flags.String("base", "", i18n.T("旧版数据文件，与输入数据对比，列出新增、删除和修改的记录"))
+	periods := o.flags.String("periods", "", i18n.T("对比输入数据的两个时间段，如 2025-01-01..2025-01-02,2025-01-03..2025-01-04"))
+	keySpec := o.flags.String("key", "", i18n.T("对比时匹配记录的键 (date/product/region，逗号分隔)"))
+	if err := o.parse(args); err != nil {
+		return err
+	}
+	if (*base == "") == (*periods == "") {
+		return usagef("❌ %v\n", i18n.Errorf("需要 -base 或 -periods 之一"))
+	}
+
+	reporter, err := out.reporter()
+	if err != nil {
+		return err
+	}
+	records, err := o.load()
+	if err != nil {
+		return err
+	}
+
+	section, err := diffSection(o, records, *base, *periods, *keySpec)
+	if err != nil {
+		return fail("❌ 数据对比失败: %v\n", err)
+	}
+	rep := newReport(o, records)
+	rep.Sections = append(rep.Sections, section)
+	return out.render(reporter, rep)
+}
+
+// diffSection 准备对比的两侧数据，生成差异报告章节。
+// 指定 basePath 时对比旧文件与当前数据；指定 periods 时对比当前数据的两个时间段。
+func diffSection(o *options, records []sales.Record, basePath, periods, keySpec string) (report.Section, error) {
+	var oldRecords, newRecords []sales.Record
+	var oldLabel, newLabel string
+	var key []string
+
+	if basePath != "" {
+		key = diff.DefaultKey
+		if keySpec != "" {
+			var err error
+			if key, err = diff.ParseKey(keySpec); err != nil {
+				return report.Section{}, err
+			}
+		}
+		base, rowErrs, err := o.cfg.Schema.LoadFile(basePath)
+		if err != nil {
+			return report.Section{}, err
+		}
+		printSkipped(rowErrs)
+		oldRecords, newRecords = o.cfg.Filter.Apply(base), records
+		oldLabel, newLabel = basePath, o.source()
+	} else {
+		specs := strings.Split(periods, ",")
+		if len(specs) != 2 {
+			return report.Section{}, i18n.Errorf("需要两个时间段，用逗号分隔: %q", periods)
+		}
+		from, err := diff.ParsePeriod(specs[0])
+		if err != nil {
+			return report.Section{}, err
+		}
+		to, err := diff.ParsePeriod(specs[1])
+		if err != nil {
+			return report.Section{}, err
+		}
+
+		// 时间段对比默认按 产品+地区 匹配，每段内先合并为一行
+		key = []string{diff.FieldProduct, diff.FieldRegion}
+		if keySpec != "" {
+			if key, err = diff.ParseKey(keySpec); err != nil {
+				return report.Section{}, err
+			}
+		}
+		oldRecords = diff.Rollup(from.Filter(records), key)
+		newRecords = diff.Rollup(to.Filter(records), key)
+		oldLabel, newLabel = from.String(), to.String()
+	}
+
+	return report.DiffSection(report.DiffResult{
+		Old:  oldLabel,
+		New:  newLabel,
+		Diff: diff.Compare(oldRecords, newRecords, key),
+		Effect: sales.Compare(
+			sales.Aggregate(oldRecords, sales.Options{}),
+			sales.Aggregate(newRecords, sales.Options{}),
+		),
+	}), nil
+}
+
+// runConfig 输出合成后生效的配置，非默认值注明来自配置文件、环境变量还是参数
+func runConfig(args []string) error {
+	o := newOptions("config")
+	o.outputFlags()
+	o.summaryFlags()
+	if err := o.parse(args); err != nil {
+		return err
+	}
+	if err := o.cfg.WriteYAML(os.Stdout); err != nil {
+		return fail("❌ %v\n", err)
+	}
+	return nil
+}
+
+// runTUI 全屏交互界面
+func runTUI(args []string) error {
+	o := newOptions("tui")
+	if err := o.parse(args); err != nil {
+		return err
+	}
+	records, err := o.load()
+	if err != nil {
+		return err
+	}
+	if err := tui.Run(records, o.source()); err != nil {
+		return fail("❌ %v\n", err)
+	}
+	return nil
+}
+
+// runServe 以HTTP JSON接口提供分析结果，直到收到中断信号。
+// 输入可以是目录，目录中的 *.csv 全部读取；文件变化后下一次请求时重新读取。
+// 指定 -uploads 时接受上传，通过检查的数据保存在该目录中，与输入文件一起分析。
+func runServe(args []string) error {
+	o := newOptions("serve")
+	addr := o.flags.String("addr", "localhost:8080", i18n.T("监听地址"))
+	reload := o.flags.Duration("reload", 2*time.Second, i18n.T("两次检查输入文件是否变化的最短间隔"))
+	uploads := o.flags.String("uploads", "", i18n.T("保存上传数据的目录，设置后接受 POST /api/uploads 上传CSV或xlsx"))
+	o.configFlag("workers", "workers", i18n.T("并行汇总的工作协程数 (0 表示CPU核数)"))
+	if err := o.parse(args); err != nil {
+		return err
+	}
+	if *uploads != "" {
+		if err := os.MkdirAll(*uploads, 0o755); err != nil {
+			return fail("❌ %v\n", err)
+		}
+	}
+
+	data := &server.Dataset{
+		Paths:         o.cfg.Inputs,
+		Schema:        o.cfg.Schema,
+		Filter:        o.cfg.Filter,
+		UploadDir:     *uploads,
+		CheckInterval: *reload,
+		OnReload: func(snap *server.Snapshot, err error) {
+			if err != nil {
+				slog.Warn(i18n.Sprintf("⚠️  重新读取数据失败，继续使用之前的数据: %v", err), "error", err)
+				return
+			}
+			printSkipped(snap.Skipped)
+			slog.Info(i18n.Sprintf("🔄 数据已重新读取: %d 条记录", len(snap.Records)), "records", len(snap.Records), "version", snap.Version)
+		},
+	}
+	snap, err := data.Current()
+	if err != nil {
+		return fail("❌ 读取数据失败: %v\n", err)
+	}
+	printSkipped(snap.Skipped)
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return fail("❌ 无法监听 %s: %v\n", *addr, err)
+	}
+	handler := server.New(data)
+	handler.Workers = o.cfg.Workers
+	srv := &http.Server{Handler: handler}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	slog.Info(i18n.Sprintf("🌐 分析服务已启动，在浏览器中打开 http://%s/ 查看看板 (%d 条记录，Ctrl+C 退出)", listener.Addr(), len(snap.Records)),
+		"addr", listener.Addr().String(), "records", len(snap.Records))
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fail("❌ %v\n", err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+// Command sales 销售数据分析命令行工具。
+//
+// 用法:
+//
+//	sales <命令> [参数] [输入文件...]
+//
+// 命令:
+//
+//	summary   完整分析报告: 总体、产品、地区、日期，可附目标达成、情景模拟和近似统计
+//	products  产品销售分析
+//	regions   地区销售分析
+//	dates     日期销售分析
+//	export    导出清洗、筛选后的记录 (csv/json/xlsx)
+//	validate  检查输入文件，列出所有有问题的数据行
+//	diff      对比两份数据或两个时间段
+//	tui       全屏交互界面
+//	serve     以网页看板和HTTP JSON接口提供分析结果 (见 server 包)
+//	config    输出生效的配置
+//
+// 所有命令都可以用 -i 指定一个或多个输入文件或目录 (默认 sales_data.csv)，
+// 用 -product、-region、-from、-to 筛选记录。`sales <命令> -h` 查看命令的全部参数。
+// 生成报表的命令加上 -watch 后持续监视输入，文件变化后重新分析并原地重绘。
+//
+// 每天相同的设置可以写在配置文件 sales.yaml 中 (见 config 包)，
+// 优先级为 默认值 < 配置文件 < 环境变量 < 参数，`sales config` 输出最终生效的配置。
+//
+// 报表写到标准输出；提示、警告和错误写入日志 (默认为标准错误，见 logging 包)，
+// -log-level、-log-format、-log-file 设置级别、格式和日志文件。
+//
+// 退出码: 0 成功；1 读取数据、分析或输出失败；2 命令或参数错误；3 validate 发现数据问题。
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"sales-analyzer/i18n"
+	"sales-analyzer/logging"
+)
+
+// 退出码
+const (
+	exitOK      = 0
+	exitFailure = 1 // 读取数据、分析或输出失败
+	exitUsage   = 2 // 命令或参数错误
+	exitInvalid = 3 // validate 发现数据问题
+)
+
+// command 一个子命令
+type command struct {
+	name  string
+	usage string // 命令说明，按当前语言翻译后显示
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{"summary", "完整分析报告: 总体、产品、地区、日期，可附目标达成、情景模拟和近似统计", runSummary},
+	{"products", "产品销售分析", sectionCommand("products")},
+	{"regions", "地区销售分析", sectionCommand("regions")},
+	{"dates", "日期销售分析", sectionCommand("dates")},
+	{"export", "导出清洗、筛选后的记录 (csv/json/xlsx)", runExport},
+	{"validate", "检查输入文件，列出所有有问题的数据行", runValidate},
+	{"diff", "对比两份数据或两个时间段", runDiff},
+	{"tui", "全屏交互界面", runTUI},
+	{"serve", "以网页看板和HTTP JSON接口提供分析结果，输入文件变化后自动重新读取", runServe},
+	{"config", "输出合成后生效的配置: 默认值 < 配置文件 < 环境变量 < 参数", runConfig},
+}
+
+// cliError 以指定退出码结束的错误。format 按当前语言翻译后输出到标准错误，
+// 为空表示信息已经输出过 (如 flag 包输出的参数错误)。
+type cliError struct {
+	code   int
+	format string
+	args   []any
+}
+
+func (e *cliError) Error() string {
+	return fmt.Sprintf(e.format, e.args...)
+}
+
+// fail 返回读取数据、分析或输出失败的错误
+func fail(format string, args ...any) error {
+	return &cliError{exitFailure, format, args}
+}
+
+// usagef 返回命令或参数错误
+func usagef(format string, args ...any) error {
+	return &cliError{exitUsage, format, args}
+}
+
+// exitWith 直接以 code 退出，不再输出信息
+func exitWith(code int) error {
+	return &cliError{code: code}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run 执行命令并返回退出码
+func run(args []string) int {
+	// 读取配置之前的错误也以相同的格式输出，parse 按配置重新设置日志
+	slog.SetDefault(slog.New(logging.NewPrettyHandler(os.Stderr, nil)))
+	presetLocale(args)
+	if len(args) == 0 {
+		printUsage()
+		return exitUsage
+	}
+
+	name := args[0]
+	switch name {
+	case "help", "-h", "-help", "--help":
+		if len(args) > 1 {
+			// sales help <命令> 等同于 sales <命令> -h
+			return run([]string{args[1], "-h"})
+		}
+		printUsage()
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		return exitCode(cmd.run(args[1:]))
+	}
+
+	slog.Error(i18n.Sprintf("❌ 未知命令: %q", name), "command", name)
+	printUsage()
+	return exitUsage
+}
+
+// exitCode 把错误信息写入日志 (如果还没有输出) 并返回对应的退出码
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	var cliErr *cliError
+	if !errors.As(err, &cliErr) {
+		cliErr = &cliError{exitFailure, "❌ %v\n", []any{err}}
+	}
+	if cliErr.format != "" {
+		msg := strings.TrimSuffix(i18n.Sprintf(cliErr.format, cliErr.args...), "\n")
+		slog.Error(msg, "exit_code", cliErr.code)
+	}
+	return cliErr.code
+}
+
+// printUsage 按当前语言输出命令列表
+func printUsage() {
+	var b strings.Builder
+	b.WriteString(i18n.T("用法: sales <命令> [参数] [输入文件...]\n\n命令:\n"))
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "  %-9s %s\n", cmd.name, i18n.T(cmd.usage))
+	}
+	b.WriteString(i18n.T("\n使用 \"sales <命令> -h\" 查看命令的参数。\n"))
+	fmt.Fprint(os.Stderr, b.String())
+}
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"sales-analyzer/analyzer"
+	"sales-analyzer/config"
+	"sales-analyzer/i18n"
+	"sales-analyzer/logging"
+	"sales-analyzer/progress"
+	"sales-analyzer/report"
+	"sales-analyzer/sales"
+	"sales-analyzer/style"
+	"sales-analyzer/table"
+	"sales-analyzer/watch"
+)
+
+// configValue 对应配置项的参数，只有在命令行中指定时才覆盖配置。
+// 列表配置项可以重复指定，每次的值也可以用逗号分隔；其他配置项以最后一次为准。
+type configValue struct {
+	key    string
+	values []string
+}
+
+func (v *configValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return strings.Join(v.values, ",")
+}
+
+func (v *configValue) Set(value string) error {
+	if !config.IsList(v.key) {
+		v.values = v.values[:0]
+	}
+	v.values = append(v.values, value)
+	return nil
+}
+
+// options 所有命令共用的参数: 输入文件、筛选条件、语言和配色。
+// 这些参数和配置文件、环境变量逐层合成为 cfg，命令从 cfg 中读取设置。
+type options struct {
+	flags      *flag.FlagSet
+	configPath string
+	files      []string // 位置参数中的输入文件
+	cfg        *config.Layers
+
+	inputs *watch.Inputs   // 按文件缓存读取结果，监视模式下只重新读取变化的文件
+	loaded *watch.Snapshot // 最近一次 load 的结果
+}
+
+// newOptions 创建命令的参数集并注册共用参数
+func newOptions(name string) *options {
+	o := &options{flags: flag.NewFlagSet(name, flag.ContinueOnError)}
+	f := o.flags
+	f.StringVar(&o.configPath, "config", "", i18n.Sprintf("配置文件 (YAML)，默认读取 $SALES_CONFIG 或当前目录下的 %s", config.DefaultFile))
+	o.configFlag("i", "inputs", i18n.T("输入CSV文件或目录 (读取其中的 *.csv)，可以重复指定或用逗号分隔，多个文件的记录合并分析"))
+	o.configFlag("product", "filter.products", i18n.T("只分析这些产品，逗号分隔"))
+	o.configFlag("region", "filter.regions", i18n.T("只分析这些地区，逗号分隔"))
+	o.configFlag("from", "filter.from", i18n.Sprintf("起始日期 (含)，格式 %s", sales.DateLayout))
+	o.configFlag("to", "filter.to", i18n.Sprintf("结束日期 (含)，格式 %s", sales.DateLayout))
+	o.configFlag("lang", "locale", i18n.Sprintf("报表语言: %s，默认读取 LC_ALL/LC_MESSAGES/LANG 环境变量", strings.Join(i18n.Tags(), "/")))
+	o.configFlag("currency", "currency", i18n.T("金额格式，%s 为金额数字，如 \"US$%s\"，默认由语言决定"))
+	o.configFlag("theme", "theme", i18n.Sprintf("配色主题: %s", strings.Join(style.ThemeNames(), "/")))
+	o.configFlag("color", "color", i18n.Sprintf("颜色输出: %s", strings.Join(style.Modes, "/")))
+	o.configFlag("log-level", "log.level", i18n.Sprintf("日志级别: %s", strings.Join(logging.Levels, "/")))
+	o.configFlag("log-format", "log.format", i18n.Sprintf("日志格式: %s；日志写到标准错误，报表写到标准输出", strings.Join(logging.Formats, "/")))
+	o.configFlag("log-file", "log.file", i18n.T("日志文件 (追加写入)，默认写到标准错误"))
+	f.Usage = func() {
+		fmt.Fprint(f.Output(), i18n.Sprintf("用法: sales %s [参数] [输入文件...]\n\n参数:\n", name))
+		f.PrintDefaults()
+		fmt.Fprint(f.Output(), i18n.Sprintf("\n参数优先于环境变量 (%s*)，环境变量优先于配置文件。\n", config.EnvPrefix))
+	}
+	return o
+}
+
+// presetLocale 在注册参数之前按 -lang 参数、环境变量和配置文件设置语言，
+// 参数说明和用法在解析参数之前就会输出，需要提前确定语言。
+// 这里忽略所有错误，不支持的语言、有问题的配置文件由 parse 报告。
+func presetLocale(args []string) {
+	tag := flagValue(args, "lang")
+	if tag == "" {
+		if cfg, err := config.Load(flagValue(args, "config"), os.Getenv); err == nil {
+			tag = cfg.Locale
+		}
+	}
+	if err := i18n.Set(i18n.Detect(tag)); err != nil {
+		i18n.Set(i18n.Detect(""))
+	}
+}
+
+// flagValue 在解析参数之前找出参数 name 的值，支持 -name value、-name=value 和两个短横线的写法。
+// 没有指定时返回空字符串。
+func flagValue(args []string, name string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		key, value, hasValue := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-"), "=")
+		if key != name {
+			continue
+		}
+		if hasValue {
+			return value
+		}
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// configFlag 注册对应配置项 key 的参数，帮助中显示内置的默认值
+func (o *options) configFlag(name, key, usage string) {
+	o.flags.Var(&configValue{key: key}, name, usage)
+	o.flags.Lookup(name).DefValue = config.Default().Get(key)
+}
+
+// parse 解析参数并合成配置，设置语言和配色，检查筛选条件。
+// 位置参数视为输入文件，与 -i 指定的文件合并；参数可以写在文件之后。
+func (o *options) parse(args []string) error {
+	for {
+		if err := o.flags.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return exitWith(exitOK)
+			}
+			// flag 包已经输出了错误和用法
+			return exitWith(exitUsage)
+		}
+		rest := o.flags.Args()
+		if len(rest) == 0 {
+			break
+		}
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			// "--" 之后全部是文件
+			o.files = append(o.files, rest...)
+			break
+		}
+		o.files = append(o.files, rest[0])
+		args = rest[1:]
+	}
+
+	cfg, err := config.Load(o.configPath, os.Getenv)
+	if err != nil {
+		return usagef("❌ %v\n", err)
+	}
+	o.flags.Visit(func(f *flag.Flag) {
+		v, ok := f.Value.(*configValue)
+		if ok && err == nil {
+			err = cfg.Set(v.key, v.String(), f.Name)
+		}
+	})
+	if err != nil {
+		return usagef("❌ %v\n", err)
+	}
+	if len(o.files) > 0 {
+		inputs := o.files
+		if o.isSet("i") {
+			inputs = append(cfg.Inputs, inputs...)
+		}
+		cfg.Inputs = nil
+		if err := cfg.Set("inputs", strings.Join(inputs, ","), "i"); err != nil {
+			return usagef("❌ %v\n", err)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return usagef("❌ %v\n", err)
+	}
+	o.cfg = cfg
+
+	if err := i18n.Set(i18n.Detect(cfg.Locale)); err != nil {
+		return usagef("❌ %v\n", err)
+	}
+	if cfg.Currency != "" {
+		if err := i18n.SetCurrency(cfg.Currency); err != nil {
+			return usagef("❌ %v\n", err)
+		}
+	}
+	theme, err := style.ThemeByName(cfg.Theme)
+	if err != nil {
+		return usagef("❌ %v\n", err)
+	}
+	mode, err := style.ParseMode(cfg.Color)
+	if err != nil {
+		return usagef("❌ %v\n", err)
+	}
+	style.Configure(theme, mode)
+	if err := setupLogging(cfg.Log); err != nil {
+		return usagef("❌ %v\n", err)
+	}
+	if cfg.File != "" {
+		slog.Debug(i18n.Sprintf("📄 读取配置文件 %s", cfg.File), "config", cfg.File)
+	}
+
+	from, to := cfg.Filter.From, cfg.Filter.To
+	for _, date := range []string{from, to} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(sales.DateLayout, date); err != nil {
+			return usagef("❌ 日期格式错误: %q\n", date)
+		}
+	}
+	if from != "" && to != "" && from > to {
+		return usagef("❌ 起始日期晚于结束日期: %s > %s\n", from, to)
+	}
+	return nil
+}
+
+// analyzers 注册配置中的外部分析程序，返回 reports 启用的分析。
+// 只有运行分析的命令调用，其他命令不检查 plugins 和 reports。
+func (o *options) analyzers() ([]analyzer.Analyzer, error) {
+	timeout := time.Duration(o.cfg.PluginTimeout) * time.Second
+	if err := analyzer.LoadPlugins(o.cfg.Plugins, timeout); err != nil {
+		return nil, usagef("❌ %v\n", err)
+	}
+	analyzers, err := analyzer.Select(o.cfg.Reports)
+	if err != nil {
+		return nil, usagef("❌ %v\n", err)
+	}
+	return analyzers, nil
+}
+
+// isSet 参数是否在命令行中指定
+func (o *options) isSet(name string) bool {
+	set := false
+	o.flags.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+	return set
+}
+
+// source 数据来源的显示文本
+func (o *options) source() string {
+	return strings.Join(o.cfg.Inputs, ", ")
+}
+
+// load 读取所有输入文件 (目录中的 *.csv 全部读取) 并按筛选条件过滤，有问题的数据行输出警告后跳过。
+// 再次调用时只重新读取变化了的文件。
+func (o *options) load() ([]sales.Record, error) {
+	if o.inputs == nil {
+		o.inputs = &watch.Inputs{Paths: o.cfg.Inputs, Schema: o.cfg.Schema}
+	}
+	start := time.Now()
+	p := o.newProgress()
+	o.inputs.Progress = p
+	snap, err := o.inputs.Load()
+	p.Stop()
+	if err != nil {
+		return nil, fail("❌ 读取数据失败: %v\n", err)
+	}
+	printSkipped(snap.Skipped)
+	elapsed := time.Since(start)
+	slog.Debug(i18n.Sprintf("📂 已读取 %d 个文件 (其中 %d 个重新读取)，共 %d 条记录，用时 %s", len(snap.Files), len(snap.Reloaded), len(snap.Records), elapsed.Round(time.Microsecond)),
+		"files", len(snap.Files), "reloaded", len(snap.Reloaded), "records", len(snap.Records), "duration", elapsed)
+	o.loaded = snap
+	return o.cfg.Filter.Apply(snap.Records), nil
+}
+
+// output 生成报表的命令额外使用的参数。格式、模板和字体对应配置项，输出文件只能由参数指定。
+type output struct {
+	opts *options
+	path string
+
+	// 监视模式，见 watchFlags
+	watch    bool
+	interval time.Duration
+	debounce time.Duration
+}
+
+func (o *options) outputFlags() *output {
+	out := &output{opts: o}
+	o.configFlag("format", "format", i18n.Sprintf("输出格式: %s", strings.Join(report.Formats, "/")))
+	o.flags.StringVar(&out.path, "o", "", i18n.T("报表输出文件，默认为标准输出"))
+	o.configFlag("template", "template", i18n.T("按模板文件输出报表 (.html/.htm 用 html/template，其他用 text/template)，忽略 -format"))
+	o.configFlag("pdf-font", "pdf_font", i18n.T("PDF报表使用的中文TrueType字体文件 (.ttf)，默认自动查找"))
+	o.configFlag("table-style", "table_style", i18n.Sprintf("终端报表的表格边框样式: %s", strings.Join(table.StyleNames(), "/")))
+	return out
+}
+
+// reporter 按配置创建 Reporter。在读取数据之前调用，参数错误时尽早退出。
+func (out *output) reporter() (report.Reporter, error) {
+	cfg := out.opts.cfg
+	if cfg.Template != "" {
+		r, err := report.NewTemplate(cfg.Template)
+		if err != nil {
+			return nil, usagef("❌ %v\n", err)
+		}
+		return r, nil
+	}
+
+	reporter, err := report.New(cfg.Format)
+	if err != nil {
+		return nil, usagef("❌ %v\n", err)
+	}
+	switch r := reporter.(type) {
+	case *report.TextReporter:
+		if r.TableStyle, err = table.StyleByName(cfg.TableStyle); err != nil {
+			return nil, usagef("❌ table_style: %v\n", err)
+		}
+	case *report.XLSXReporter, *report.PDFReporter:
+		// 二进制格式不写到终端
+		if out.path == "" {
+			return nil, usagef("❌ %s 格式需要用 -o 指定输出文件\n", cfg.Format)
+		}
+		if pdf, ok := r.(*report.PDFReporter); ok {
+			pdf.FontPath = cfg.PDFFont
+			if pdf.FontPath == "" {
+				if pdf.FontPath, err = report.FindFont(); err != nil {
+					return nil, fail("❌ %v\n", err)
+				}
+			}
+		}
+	}
+	return reporter, nil
+}
+
+// render 将报表写到 -o 指定的文件，未指定时写到标准输出
+func (out *output) render(reporter report.Reporter, rep *report.Report) error {
+	return writeOutput(out.path, func(w io.Writer) error {
+		return reporter.Render(w, rep)
+	})
+}
+
+// writeOutput 调用 write 写到文件 path，path 为空时写到标准输出。
+// 写入或关闭文件出错时删除不完整的输出文件。
+func writeOutput(path string, write func(w io.Writer) error) error {
+	if path == "" {
+		if err := write(os.Stdout); err != nil {
+			return fail("❌ 输出报表失败: %v\n", err)
+		}
+		return nil
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fail("❌ 无法创建输出文件: %v\n", err)
+	}
+	err = write(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// 只删除普通文件，-o /dev/stdout 等设备文件保持不变
+		if info, statErr := os.Stat(path); statErr == nil && info.Mode().IsRegular() {
+			os.Remove(path)
+		}
+		return fail("❌ 输出报表失败: %v\n", err)
+	}
+	return nil
+}
+
+// newReport 创建报表，标题下方注明读取的记录数
+func newReport(o *options, records []sales.Record) *report.Report {
+	return &report.Report{
+		Title:       i18n.T("📊 高级销售数据分析系统"),
+		Source:      o.source(),
+		GeneratedAt: time.Now(),
+		Notes:       []report.Note{report.Success("✅ 成功读取 %d 条销售记录", len(records))},
+	}
+}
+
+// printSkipped 以警告记录读取时跳过的数据行
+func printSkipped(skipped []sales.RowError) {
+	for _, e := range skipped {
+		slog.Warn(i18n.Sprintf("⚠️  %s，跳过", e), "file", e.File, "line", e.Line, "problem", e.Message)
+	}
+}
+
+// newProgress 创建进度显示。日志以 pretty 格式写到终端时原地绘制进度条，
+// 否则 (日志文件、text/json 格式或标准错误被重定向) 定期写进度日志，不与日志行混在一起。
+func (o *options) newProgress() *progress.Progress {
+	var w io.Writer
+	if format := strings.ToLower(o.cfg.Log.Format); o.cfg.Log.File == "" && (format == "pretty" || format == "") {
+		w = os.Stderr
+	}
+	return progress.New(w)
+}
+
+// setupLogging 按配置创建日志并设为 slog 的默认日志。
+// 提示和错误都写入日志，不混入标准输出的报表；日志文件在进程结束时关闭。
+func setupLogging(cfg config.Log) error {
+	level, err := logging.ParseLevel(cfg.Level)
+	if err != nil {
+		return fmt.Errorf("log.level: %w", err)
+	}
+	w := io.Writer(os.Stderr)
+	if cfg.File != "" {
+		file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return i18n.Errorf("log.file: 无法打开日志文件: %w", err)
+		}
+		w = file
+	}
+	logger, err := logging.New(w, cfg.Format, level)
+	if err != nil {
+		return fmt.Errorf("log.format: %w", err)
+	}
+	slog.SetDefault(logger)
+	return nil
+}
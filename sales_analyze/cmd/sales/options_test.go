@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestFlagValue(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"summary", "-lang", "en-US"}, "en-US"},
+		{[]string{"summary", "--lang", "en-US"}, "en-US"},
+		{[]string{"summary", "-lang=en-US", "-h"}, "en-US"},
+		{[]string{"summary", "--lang=en-US"}, "en-US"},
+		{[]string{"summary", "-language", "en-US"}, ""},
+		{[]string{"summary", "lang", "en-US"}, ""},
+		{[]string{"summary", "--", "-lang", "en-US"}, ""},
+		{[]string{"summary", "-lang"}, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := flagValue(tt.args, "lang"); got != tt.want {
+			t.Errorf("flagValue(%q) = %q，应为 %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"sales-analyzer/i18n"
+	"sales-analyzer/report"
+	"sales-analyzer/sales"
+	"sales-analyzer/style"
+)
+
+// clearScreen 光标移到左上角并清除屏幕和回滚区，重绘的报表始终从第一行开始
+const clearScreen = "\x1b[H\x1b[2J\x1b[3J"
+
+// watchFlags 注册监视模式的参数
+func (out *output) watchFlags() {
+	f := out.opts.flags
+	f.BoolVar(&out.watch, "watch", false, i18n.T("监视输入文件或目录，变化后重新读取并原地重绘报表，Ctrl+C 退出"))
+	f.DurationVar(&out.interval, "watch-interval", time.Second, i18n.T("监视模式下检查输入文件的间隔"))
+	f.DurationVar(&out.debounce, "debounce", 300*time.Millisecond, i18n.T("监视模式下文件停止变化多久后再重新读取"))
+}
+
+// generate 读取数据，由 build 生成报表并输出。
+// 监视模式下输出后继续监视输入，文件变化稳定后只重新读取变化的文件并重绘；
+// 输出到终端时清屏后原地重绘，输出到文件时覆盖文件。
+func (out *output) generate(reporter report.Reporter, build func(records []sales.Record) (*report.Report, error)) error {
+	o := out.opts
+	once := func() error {
+		records, err := o.load()
+		if err != nil {
+			return err
+		}
+		// Excel工作簿额外包含清洗后的原始数据
+		if xlsx, ok := reporter.(*report.XLSXReporter); ok {
+			xlsx.Records = records
+		}
+		rep, err := build(records)
+		if err != nil {
+			return err
+		}
+		return out.render(reporter, rep)
+	}
+	if !out.watch {
+		return once()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	redraw := func() {
+		if out.path == "" && style.IsTerminal(os.Stdout) {
+			fmt.Print(clearScreen)
+		}
+		// 出错时保留错误信息继续监视，文件修好后自动恢复
+		if exitCode(once()) != exitOK {
+			slog.Info(i18n.Sprintf("👀 %s 正在监视输入文件，修正后自动重新读取 (Ctrl+C 退出)", time.Now().Format("15:04:05")))
+			return
+		}
+		now, files := time.Now().Format("15:04:05"), len(o.loaded.Files)
+		if len(o.loaded.Reloaded) == 0 {
+			// 只是删除了文件，其余文件都使用缓存
+			slog.Info(i18n.Sprintf("👀 %s 已更新，正在监视 %d 个文件的变化 (Ctrl+C 退出)", now, files), "files", files)
+			return
+		}
+		reloaded := make([]string, len(o.loaded.Reloaded))
+		for i, file := range o.loaded.Reloaded {
+			reloaded[i] = filepath.Base(file)
+		}
+		slog.Info(i18n.Sprintf("👀 %s 已读取 %s，正在监视 %d 个文件的变化 (Ctrl+C 退出)", now, strings.Join(reloaded, ", "), files),
+			"files", files, "reloaded", reloaded)
+	}
+
+	redraw()
+	o.inputs.Watch(ctx, out.interval, out.debounce, redraw)
+	return nil
+}
@@ -0,0 +1,391 @@
+// Package config 读取分析工具的配置，按 默认值 < 配置文件 < 环境变量 < 命令行参数 的顺序逐层覆盖。
+//
+// 配置文件为YAML，每天用相同参数运行的分析可以写在文件里:
+//
+//	inputs: [sales_data.csv]
+//	schema:              # CSV表头中各字段的列名
+//	  amount: 销售额
+//	locale: en-US
+//	currency: "US$%s"    # %s 为带千位分隔的金额
+//	reports: [overall, products, weekdays]
+//	plugins: [bin/sales-weekdays]  # 外部分析程序，名称为去掉 sales- 前缀的文件名
+//	plugin_timeout: 30  # 外部分析程序运行超过30秒时终止
+//	thresholds:
+//	  daily_drop: 20     # 日销售额较前一日下降超过20%时提示
+//	log:
+//	  level: debug
+//	  format: json
+//	  file: sales.log
+//
+// 每个配置项都可以用环境变量覆盖，变量名为 SALES_ 加上大写的配置路径，
+// 如 SALES_THEME、SALES_SCHEMA_AMOUNT、SALES_LOG_LEVEL；列表用逗号分隔。
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"sales-analyzer/i18n"
+	"sales-analyzer/sales"
+)
+
+// DefaultFile 没有指定配置文件时，在当前目录查找的文件
+const DefaultFile = "sales.yaml"
+
+// EnvPrefix 环境变量名的前缀
+const EnvPrefix = "SALES_"
+
+// Reports 内置的分析，也是 reports 的默认值。外部分析程序 (plugins) 注册后同样可以启用，见 analyzer 包。
+var Reports = []string{"overall", "products", "regions", "dates"}
+
+// Config 分析工具的全部配置
+type Config struct {
+	Inputs []string     `yaml:"inputs"` // 输入CSV文件或目录，多个文件的记录合并分析
+	Schema sales.Schema `yaml:"schema"` // CSV表头中各字段的列名
+	Filter sales.Filter `yaml:"filter"` // 只分析满足条件的记录
+
+	Locale     string `yaml:"locale"`      // 报表语言，为空时读取 LC_ALL/LC_MESSAGES/LANG
+	Currency   string `yaml:"currency"`    // 金额格式，为空时使用语言区域的默认格式
+	Theme      string `yaml:"theme"`       // 配色主题
+	Color      string `yaml:"color"`       // 颜色输出: auto/never/always
+	TableStyle string `yaml:"table_style"` // 终端报表的表格边框样式，见 table.Styles
+
+	Format   string `yaml:"format"`   // 报表输出格式
+	Template string `yaml:"template"` // 报表模板文件，设置后忽略 Format
+	PDFFont  string `yaml:"pdf_font"` // PDF报表使用的中文字体，为空时自动查找
+
+	Reports       []string   `yaml:"reports"`        // summary 输出的分析，见 Reports
+	Plugins       []string   `yaml:"plugins"`        // 外部分析程序，运行分析的命令启动时注册为同名分析
+	PluginTimeout int        `yaml:"plugin_timeout"` // 每次运行外部分析程序的超时秒数，0 表示不限制
+	Targets       string     `yaml:"targets"`        // 月度销售目标文件
+	Workers       int        `yaml:"workers"`        // 并行汇总的工作协程数，0 表示CPU核数
+	Thresholds    Thresholds `yaml:"thresholds"`     // 提示和近似统计的阈值
+	Log           Log        `yaml:"log"`            // 运行日志
+}
+
+// Thresholds 提示和近似统计的阈值，为0表示不启用
+type Thresholds struct {
+	DailyDrop     float64 `yaml:"daily_drop"`     // 日销售额较前一日下降超过该百分比时提示
+	DistinctError float64 `yaml:"distinct_error"` // 近似去重计数的相对误差
+	QuantileError float64 `yaml:"quantile_error"` // 近似分位数的秩误差
+}
+
+// Log 运行日志的设置，见 logging 包
+type Log struct {
+	Level  string `yaml:"level"`  // 日志级别: debug/info/warn/error
+	Format string `yaml:"format"` // 日志格式: pretty/text/json
+	File   string `yaml:"file"`   // 日志文件 (追加写入)，为空时写到标准错误
+}
+
+// Default 默认配置
+func Default() *Config {
+	return &Config{
+		Inputs:        []string{"sales_data.csv"},
+		Schema:        sales.DefaultSchema,
+		Theme:         "default",
+		Color:         "auto",
+		TableStyle:    "ascii",
+		Format:        "table",
+		Reports:       slices.Clone(Reports),
+		PluginTimeout: 60,
+		Log:           Log{Level: "info", Format: "pretty"},
+	}
+}
+
+// source 配置项的来源，format 按当前语言翻译后显示
+type source struct {
+	format string
+	name   string
+}
+
+// Layers 逐层合成的配置，记录每一项最后由哪一层设置
+type Layers struct {
+	Config
+	File    string // 读取的配置文件，没有时为空
+	sources map[string]source
+}
+
+// Load 合成 默认值、配置文件和环境变量 三层配置，命令行参数由调用方用 Set 覆盖。
+// path 为空时依次使用 SALES_CONFIG 环境变量和当前目录下的 DefaultFile，都不存在时跳过配置文件。
+func Load(path string, getenv func(string) string) (*Layers, error) {
+	l := &Layers{Config: *Default(), sources: make(map[string]source)}
+
+	explicit := path != ""
+	if !explicit {
+		path = getenv(EnvPrefix + "CONFIG")
+		explicit = path != ""
+	}
+	if !explicit {
+		path = DefaultFile
+	}
+	if err := l.loadFile(path); err != nil {
+		if explicit || !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	for _, key := range Keys() {
+		name := EnvName(key)
+		if value := getenv(name); value != "" {
+			if err := l.set(key, value, source{"环境变量 %s", name}); err != nil {
+				return nil, i18n.Errorf("环境变量 %s: %w", name, err)
+			}
+		}
+	}
+	return l, nil
+}
+
+// loadFile 用配置文件覆盖当前配置，文件中未出现的配置项保持不变
+func (l *Layers) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return i18n.Errorf("无法读取配置文件: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	// 拼错的配置项直接报错，而不是被静默忽略
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&l.Config); err != nil && !errors.Is(err, io.EOF) {
+		return i18n.Errorf("配置文件 %s 格式错误: %w", path, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return i18n.Errorf("配置文件 %s 格式错误: %w", path, err)
+	}
+	if len(doc.Content) > 0 {
+		for _, key := range fileKeys(doc.Content[0], "") {
+			l.sources[key] = source{"配置文件 %s", path}
+		}
+	}
+	l.File = path
+	return nil
+}
+
+// fileKeys 返回YAML映射中出现的叶子配置项
+func fileKeys(node *yaml.Node, prefix string) []string {
+	if node.Kind != yaml.MappingNode {
+		return []string{prefix}
+	}
+	var keys []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keys = append(keys, fileKeys(node.Content[i+1], prefix+node.Content[i].Value+".")...)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	for i := range keys {
+		keys[i] = strings.TrimSuffix(keys[i], ".")
+	}
+	return keys
+}
+
+// Set 用命令行参数 flag 的值覆盖配置项 key。列表配置项的值用逗号分隔。
+func (l *Layers) Set(key, value, flag string) error {
+	return l.set(key, value, source{"参数 -%s", flag})
+}
+
+func (l *Layers) set(key, value string, from source) error {
+	field, ok := l.Config.field(key)
+	if !ok {
+		return i18n.Errorf("未知的配置项: %s", key)
+	}
+	if err := setValue(field, value); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	l.sources[key] = from
+	return nil
+}
+
+// Get 返回配置项的当前值，列表用逗号连接
+func (c *Config) Get(key string) string {
+	field, ok := c.field(key)
+	if !ok {
+		return ""
+	}
+	return formatValue(field)
+}
+
+// Source 配置项的来源，如 "环境变量 SALES_THEME"；使用默认值时返回空字符串
+func (l *Layers) Source(key string) string {
+	from, ok := l.sources[key]
+	if !ok {
+		return ""
+	}
+	return i18n.Sprintf(from.format, from.name)
+}
+
+// Validate 检查各配置项的取值。语言、主题、分析名称等由使用它们的包检查。
+func (c *Config) Validate() error {
+	if len(c.Inputs) == 0 {
+		return i18n.Errorf("inputs: 至少需要一个输入文件")
+	}
+	if c.Workers < 0 {
+		return i18n.Errorf("workers: 不能为负数")
+	}
+	if c.PluginTimeout < 0 {
+		return i18n.Errorf("plugin_timeout: 不能为负数")
+	}
+	for _, t := range []struct {
+		key   string
+		value float64
+	}{
+		{"thresholds.daily_drop", c.Thresholds.DailyDrop},
+		{"thresholds.distinct_error", c.Thresholds.DistinctError},
+		{"thresholds.quantile_error", c.Thresholds.QuantileError},
+	} {
+		if t.value < 0 {
+			return i18n.Errorf("%s: 不能为负数", t.key)
+		}
+	}
+	return nil
+}
+
+// Enabled 是否启用了名为 name 的分析
+func (c *Config) Enabled(name string) bool {
+	return slices.Contains(c.Reports, name)
+}
+
+// WriteYAML 以YAML输出生效的配置，非默认值的配置项在行尾注明来源
+func (l *Layers) WriteYAML(w io.Writer) error {
+	var doc yaml.Node
+	if err := doc.Encode(&l.Config); err != nil {
+		return err
+	}
+	l.annotate(&doc, "")
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func (l *Layers) annotate(node *yaml.Node, prefix string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		path, value := prefix+node.Content[i].Value, node.Content[i+1]
+		if value.Kind == yaml.MappingNode {
+			l.annotate(value, path+".")
+			continue
+		}
+		if value.Kind == yaml.SequenceNode {
+			// 短列表写在一行，便于与注释对照
+			value.Style = yaml.FlowStyle
+		}
+		value.LineComment = l.Source(path)
+	}
+}
+
+// Keys 所有叶子配置项，按结构体中的顺序，如 "schema.date"、"thresholds.daily_drop"
+func Keys() []string {
+	return keys(reflect.TypeOf(Config{}), "")
+}
+
+func keys(t reflect.Type, prefix string) []string {
+	var result []string
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := prefix + yamlName(f)
+		if f.Type.Kind() == reflect.Struct {
+			result = append(result, keys(f.Type, name+".")...)
+			continue
+		}
+		result = append(result, name)
+	}
+	return result
+}
+
+// EnvName 配置项对应的环境变量名，如 "thresholds.daily_drop" → "SALES_THRESHOLDS_DAILY_DROP"
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// yamlName 与 yaml 包相同的字段命名: 优先使用标签，否则为小写的字段名
+func yamlName(f reflect.StructField) string {
+	if name, _, _ := strings.Cut(f.Tag.Get("yaml"), ","); name != "" {
+		return name
+	}
+	return strings.ToLower(f.Name)
+}
+
+// IsList 配置项是否为列表
+func IsList(key string) bool {
+	field, ok := Default().field(key)
+	return ok && field.Kind() == reflect.Slice
+}
+
+// field 按配置路径找到字段
+func (c *Config) field(key string) (reflect.Value, bool) {
+	v := reflect.ValueOf(c).Elem()
+	for _, part := range strings.Split(key, ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+		found := false
+		for i := range v.NumField() {
+			if f := v.Type().Field(i); f.IsExported() && yamlName(f) == part {
+				v, found = v.Field(i), true
+				break
+			}
+		}
+		if !found {
+			return reflect.Value{}, false
+		}
+	}
+	return v, v.Kind() != reflect.Struct
+}
+
+func setValue(v reflect.Value, s string) error {
+	s = strings.TrimSpace(s)
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return i18n.Errorf("需要整数: %q", s)
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return i18n.Errorf("需要数字: %q", s)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return i18n.Errorf("不支持的类型 %s", v.Type())
+	}
+	return nil
+}
+
+func formatValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Slice:
+		return strings.Join(v.Interface().([]string), ",")
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
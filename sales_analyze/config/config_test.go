@@ -0,0 +1,106 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadPrecedence 每个配置项取最后一层的值: 默认值 < 配置文件 < 环境变量 < 命令行参数
+func TestLoadPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sales.yaml")
+	file := `theme: file
+workers: 2
+reports: [overall, products]
+thresholds:
+  daily_drop: 20
+log:
+  level: debug
+`
+	if err := os.WriteFile(path, []byte(file), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		"SALES_THEME":                 "env",
+		"SALES_WORKERS":               "4",
+		"SALES_THRESHOLDS_DAILY_DROP": "30",
+		"SALES_COLOR":                 "never",
+	}
+	flags := []struct{ key, value, flag string }{
+		{"theme", "flag", "theme"},
+		{"reports", "regions,dates", "reports"},
+	}
+
+	l, err := Load(path, func(name string) string { return env[name] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range flags {
+		if err := l.Set(f.key, f.value, f.flag); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		key    string
+		want   string
+		source string
+	}{
+		{"theme", "flag", "参数 -theme"},                                      // 三层都设置
+		{"reports", "regions,dates", "参数 -reports"},                         // 配置文件和参数
+		{"workers", "4", "环境变量 SALES_WORKERS"},                              // 配置文件和环境变量
+		{"thresholds.daily_drop", "30", "环境变量 SALES_THRESHOLDS_DAILY_DROP"}, // 嵌套的配置项
+		{"color", "never", "环境变量 SALES_COLOR"},                              // 只有环境变量
+		{"log.level", "debug", "配置文件 " + path},                              // 只有配置文件
+		{"log.format", "pretty", ""},                                        // 默认值
+	}
+	for _, tt := range tests {
+		if got := l.Get(tt.key); got != tt.want {
+			t.Errorf("Get(%q) = %q，应为 %q", tt.key, got, tt.want)
+		}
+		if got := l.Source(tt.key); got != tt.source {
+			t.Errorf("Source(%q) = %q，应为 %q", tt.key, got, tt.source)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	none := func(string) string { return "" }
+
+	tests := []struct {
+		name   string
+		path   string
+		getenv func(string) string
+	}{
+		{"指定的配置文件不存在", filepath.Join(dir, "missing.yaml"), none},
+		{"未知的配置项", write("unknown.yaml", "themes: dark\n"), none},
+		{"类型错误", write("type.yaml", "workers: many\n"), none},
+		{"环境变量的值无效", "", func(name string) string {
+			if name == "SALES_WORKERS" {
+				return "many"
+			}
+			return ""
+		}},
+		{"SALES_CONFIG 指向的文件不存在", "", func(name string) string {
+			if name == "SALES_CONFIG" {
+				return filepath.Join(dir, "missing.yaml")
+			}
+			return ""
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(tt.path, tt.getenv); err == nil {
+				t.Errorf("Load(%q) 应返回错误", tt.path)
+			}
+		})
+	}
+}
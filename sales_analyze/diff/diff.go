@@ -0,0 +1,200 @@
+// Package diff 对比两份销售数据 (两个文件或同一文件的两个时间段)，
+// 按键匹配记录，找出新增、删除和修改的行以及具体变化的字段。
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"sales-analyzer/i18n"
+	"sales-analyzer/sales"
+)
+
+// 记录的字段名，其中 date/product/region 可用作匹配键
+const (
+	FieldDate     = "date"
+	FieldProduct  = "product"
+	FieldRegion   = "region"
+	FieldQuantity = "quantity"
+	FieldAmount   = "amount"
+)
+
+// DefaultKey 默认匹配键: 日期+产品+地区
+var DefaultKey = []string{FieldDate, FieldProduct, FieldRegion}
+
+// FieldChange 一个字段的变化
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// Modification 键相同但内容不同的一对记录
+type Modification struct {
+	Key     string        `json:"key"`
+	Old     sales.Record  `json:"old"`
+	New     sales.Record  `json:"new"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// Result 两份数据的差异
+type Result struct {
+	Key       []string       `json:"key"`
+	Added     []sales.Record `json:"added"`
+	Removed   []sales.Record `json:"removed"`
+	Modified  []Modification `json:"modified"`
+	Unchanged int            `json:"unchanged"`
+}
+
+// Changed 是否存在任何差异
+func (r *Result) Changed() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0 || len(r.Modified) > 0
+}
+
+// ParseKey 解析逗号分隔的键字段，例如 "date,product,region"
+func ParseKey(spec string) ([]string, error) {
+	var key []string
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		switch field {
+		case FieldDate, FieldProduct, FieldRegion:
+			key = append(key, field)
+		case "":
+		default:
+			return nil, i18n.Errorf("无效的键字段: %q (可选 date/product/region)", field)
+		}
+	}
+	if len(key) == 0 {
+		return nil, i18n.Errorf("匹配键不能为空")
+	}
+	return key, nil
+}
+
+// Compare 按 key 匹配新旧记录。
+// 键重复的记录按出现顺序一一配对，多出的部分视为新增或删除。
+func Compare(old, new []sales.Record, key []string) *Result {
+	result := &Result{Key: key}
+
+	oldGroups := groupByKey(old, key)
+	newGroups := groupByKey(new, key)
+
+	var keys []string
+	for k := range oldGroups {
+		keys = append(keys, k)
+	}
+	for k := range newGroups {
+		if _, exists := oldGroups[k]; !exists {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		olds, news := oldGroups[k], newGroups[k]
+		paired := min(len(olds), len(news))
+
+		for i := 0; i < paired; i++ {
+			changes := compareFields(olds[i], news[i], key)
+			if len(changes) == 0 {
+				result.Unchanged++
+				continue
+			}
+			result.Modified = append(result.Modified, Modification{
+				Key:     k,
+				Old:     olds[i],
+				New:     news[i],
+				Changes: changes,
+			})
+		}
+		result.Removed = append(result.Removed, olds[paired:]...)
+		result.Added = append(result.Added, news[paired:]...)
+	}
+
+	return result
+}
+
+// Rollup 按 key 汇总记录，键以外的维度字段置空。
+// 用于按时间段对比时先把每段内同一产品、地区的多天数据合并为一行。
+func Rollup(records []sales.Record, key []string) []sales.Record {
+	groups := make(map[string]*sales.Record)
+	var keys []string
+
+	for _, record := range records {
+		k := KeyOf(record, key)
+		if summary, exists := groups[k]; exists {
+			summary.Quantity += record.Quantity
+			summary.Amount += record.Amount
+			continue
+		}
+
+		summary := sales.Record{Quantity: record.Quantity, Amount: record.Amount}
+		for _, field := range key {
+			switch field {
+			case FieldDate:
+				summary.Date = record.Date
+			case FieldProduct:
+				summary.Product = record.Product
+			case FieldRegion:
+				summary.Region = record.Region
+			}
+		}
+		groups[k] = &summary
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	result := make([]sales.Record, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, *groups[k])
+	}
+	return result
+}
+
+// KeyOf 返回记录在 key 下的键，字段之间用 "/" 连接
+func KeyOf(record sales.Record, key []string) string {
+	parts := make([]string, len(key))
+	for i, field := range key {
+		switch field {
+		case FieldDate:
+			parts[i] = record.Date
+		case FieldProduct:
+			parts[i] = record.Product
+		case FieldRegion:
+			parts[i] = record.Region
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+func groupByKey(records []sales.Record, key []string) map[string][]sales.Record {
+	groups := make(map[string][]sales.Record)
+	for _, record := range records {
+		k := KeyOf(record, key)
+		groups[k] = append(groups[k], record)
+	}
+	return groups
+}
+
+// compareFields 逐字段比较，键字段必然相同因此跳过
+func compareFields(old, new sales.Record, key []string) []FieldChange {
+	inKey := make(map[string]bool)
+	for _, field := range key {
+		inKey[field] = true
+	}
+
+	var changes []FieldChange
+	check := func(field, oldValue, newValue string) {
+		if !inKey[field] && oldValue != newValue {
+			changes = append(changes, FieldChange{field, oldValue, newValue})
+		}
+	}
+
+	check(FieldDate, old.Date, new.Date)
+	check(FieldProduct, old.Product, new.Product)
+	check(FieldQuantity, fmt.Sprintf("%d", old.Quantity), fmt.Sprintf("%d", new.Quantity))
+	check(FieldAmount, fmt.Sprintf("%.2f", old.Amount), fmt.Sprintf("%.2f", new.Amount))
+	check(FieldRegion, old.Region, new.Region)
+
+	return changes
+}
@@ -0,0 +1,220 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	"sales-analyzer/sales"
+)
+
+func rec(date, product string, quantity int, amount float64, region string) sales.Record {
+	return sales.Record{Date: date, Product: product, Quantity: quantity, Amount: amount, Region: region}
+}
+
+func TestCompare(t *testing.T) {
+	phone := rec("2025-01-01", "手机", 2, 5998, "华东")
+	laptop := rec("2025-01-01", "电脑", 1, 6999, "华南")
+	tablet := rec("2025-01-02", "平板", 1, 3299, "华北")
+
+	tests := []struct {
+		name      string
+		old, new  []sales.Record
+		key       []string
+		added     []sales.Record
+		removed   []sales.Record
+		modified  []Modification
+		unchanged int
+	}{
+		{
+			name:      "相同",
+			old:       []sales.Record{phone, laptop},
+			new:       []sales.Record{laptop, phone},
+			key:       DefaultKey,
+			unchanged: 2,
+		},
+		{
+			name:      "新增和删除",
+			old:       []sales.Record{phone, laptop},
+			new:       []sales.Record{phone, tablet},
+			key:       DefaultKey,
+			added:     []sales.Record{tablet},
+			removed:   []sales.Record{laptop},
+			unchanged: 1,
+		},
+		{
+			name: "修改销量和销售额",
+			old:  []sales.Record{phone},
+			new:  []sales.Record{rec("2025-01-01", "手机", 3, 8997, "华东")},
+			key:  DefaultKey,
+			modified: []Modification{{
+				Key: "2025-01-01/手机/华东",
+				Old: phone,
+				New: rec("2025-01-01", "手机", 3, 8997, "华东"),
+				Changes: []FieldChange{
+					{FieldQuantity, "2", "3"},
+					{FieldAmount, "5998.00", "8997.00"},
+				},
+			}},
+		},
+		{
+			name:      "金额在两位小数内相同",
+			old:       []sales.Record{phone},
+			new:       []sales.Record{rec("2025-01-01", "手机", 2, 5998.001, "华东")},
+			key:       DefaultKey,
+			unchanged: 1,
+		},
+		{
+			// 键只有日期+产品时，地区不同视为修改
+			name: "键以外的维度字段",
+			old:  []sales.Record{phone},
+			new:  []sales.Record{rec("2025-01-01", "手机", 2, 5998, "华西")},
+			key:  []string{FieldDate, FieldProduct},
+			modified: []Modification{{
+				Key:     "2025-01-01/手机",
+				Old:     phone,
+				New:     rec("2025-01-01", "手机", 2, 5998, "华西"),
+				Changes: []FieldChange{{FieldRegion, "华东", "华西"}},
+			}},
+		},
+		{
+			name: "按产品匹配时日期变化",
+			old:  []sales.Record{phone},
+			new:  []sales.Record{rec("2025-01-03", "手机", 2, 5998, "华东")},
+			key:  []string{FieldProduct},
+			modified: []Modification{{
+				Key:     "手机",
+				Old:     phone,
+				New:     rec("2025-01-03", "手机", 2, 5998, "华东"),
+				Changes: []FieldChange{{FieldDate, "2025-01-01", "2025-01-03"}},
+			}},
+		},
+		{
+			// 键重复的记录按出现顺序配对: 第1条相同，第2条修改，新数据多出的第3条为新增
+			name: "重复键按顺序配对",
+			old:  []sales.Record{phone, rec("2025-01-01", "手机", 1, 2999, "华东")},
+			new: []sales.Record{
+				phone,
+				rec("2025-01-01", "手机", 1, 2899, "华东"),
+				rec("2025-01-01", "手机", 5, 14995, "华东"),
+			},
+			key:   DefaultKey,
+			added: []sales.Record{rec("2025-01-01", "手机", 5, 14995, "华东")},
+			modified: []Modification{{
+				Key:     "2025-01-01/手机/华东",
+				Old:     rec("2025-01-01", "手机", 1, 2999, "华东"),
+				New:     rec("2025-01-01", "手机", 1, 2899, "华东"),
+				Changes: []FieldChange{{FieldAmount, "2999.00", "2899.00"}},
+			}},
+			unchanged: 1,
+		},
+		{
+			name:    "重复键多出的旧记录为删除",
+			old:     []sales.Record{phone, phone},
+			new:     []sales.Record{phone},
+			key:     DefaultKey,
+			removed: []sales.Record{phone},
+
+			unchanged: 1,
+		},
+		{
+			name:  "旧数据为空",
+			new:   []sales.Record{phone, laptop},
+			key:   DefaultKey,
+			added: []sales.Record{phone, laptop}, // 按键排序
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(tt.old, tt.new, tt.key)
+			want := &Result{Key: tt.key, Added: tt.added, Removed: tt.removed, Modified: tt.modified, Unchanged: tt.unchanged}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Compare() =\n%+v\n应为\n%+v", got, want)
+			}
+			if changed := len(tt.added)+len(tt.removed)+len(tt.modified) > 0; got.Changed() != changed {
+				t.Errorf("Changed() = %v，应为 %v", got.Changed(), changed)
+			}
+		})
+	}
+}
+
+func TestRollup(t *testing.T) {
+	records := []sales.Record{
+		rec("2025-01-02", "手机", 1, 2999, "华东"),
+		rec("2025-01-01", "手机", 2, 5998, "华东"),
+		rec("2025-01-01", "电脑", 1, 6999, "华东"),
+		rec("2025-01-01", "手机", 1, 2999, "华南"),
+	}
+	tests := []struct {
+		name string
+		key  []string
+		want []sales.Record
+	}{
+		{
+			name: "产品+地区",
+			key:  []string{FieldProduct, FieldRegion},
+			want: []sales.Record{
+				rec("", "手机", 3, 8997, "华东"),
+				rec("", "手机", 1, 2999, "华南"),
+				rec("", "电脑", 1, 6999, "华东"),
+			},
+		},
+		{
+			name: "产品",
+			key:  []string{FieldProduct},
+			want: []sales.Record{
+				rec("", "手机", 4, 11996, ""),
+				rec("", "电脑", 1, 6999, ""),
+			},
+		},
+		{
+			name: "日期",
+			key:  []string{FieldDate},
+			want: []sales.Record{
+				rec("2025-01-01", "", 4, 15996, ""),
+				rec("2025-01-02", "", 1, 2999, ""),
+			},
+		},
+		{
+			name: "完整的键不合并",
+			key:  DefaultKey,
+			want: []sales.Record{
+				rec("2025-01-01", "手机", 2, 5998, "华东"),
+				rec("2025-01-01", "手机", 1, 2999, "华南"),
+				rec("2025-01-01", "电脑", 1, 6999, "华东"),
+				rec("2025-01-02", "手机", 1, 2999, "华东"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Rollup(records, tt.key); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Rollup() =\n%+v\n应为\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []string
+		err  bool
+	}{
+		{"date,product,region", DefaultKey, false},
+		{" product , region ", []string{FieldProduct, FieldRegion}, false},
+		{"product,", []string{FieldProduct}, false},
+		{"amount", nil, true},
+		{"", nil, true},
+		{",", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseKey(tt.spec)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseKey(%q) 错误 = %v", tt.spec, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseKey(%q) = %v，应为 %v", tt.spec, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package diff
+
+import (
+	"strings"
+	"time"
+
+	"sales-analyzer/i18n"
+	"sales-analyzer/sales"
+)
+
+// Period 日期区间，首尾都包含在内
+type Period struct {
+	From string
+	To   string
+}
+
+// ParsePeriod 解析 "2025-01-01..2025-01-02" 形式的区间，单个日期表示当天
+func ParsePeriod(spec string) (Period, error) {
+	from, to, found := strings.Cut(strings.TrimSpace(spec), "..")
+	if !found {
+		to = from
+	}
+
+	p := Period{From: strings.TrimSpace(from), To: strings.TrimSpace(to)}
+	for _, date := range []string{p.From, p.To} {
+		if _, err := time.Parse(sales.DateLayout, date); err != nil {
+			return Period{}, i18n.Errorf("日期格式错误: %q", date)
+		}
+	}
+	if p.From > p.To {
+		return Period{}, i18n.Errorf("起始日期晚于结束日期: %s", spec)
+	}
+	return p, nil
+}
+
+// String 返回区间的文本形式
+func (p Period) String() string {
+	if p.From == p.To {
+		return p.From
+	}
+	return p.From + ".." + p.To
+}
+
+// Filter 返回日期落在区间内的记录
+func (p Period) Filter(records []sales.Record) []sales.Record {
+	var result []sales.Record
+	for _, record := range records {
+		if record.Date >= p.From && record.Date <= p.To {
+			result = append(result, record)
+		}
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	"sales-analyzer/sales"
+)
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		spec string
+		want Period
+		err  bool
+	}{
+		{"2025-01-01..2025-01-07", Period{"2025-01-01", "2025-01-07"}, false},
+		{" 2025-01-01 .. 2025-01-07 ", Period{"2025-01-01", "2025-01-07"}, false},
+		{"2025-01-01", Period{"2025-01-01", "2025-01-01"}, false},
+		{"2024-12-25..2025-01-05", Period{"2024-12-25", "2025-01-05"}, false}, // 跨年
+		{"2024-02-29", Period{"2024-02-29", "2024-02-29"}, false},             // 闰日
+		{"2025-02-29", Period{}, true},                                        // 非闰年
+		{"2025-01-07..2025-01-01", Period{}, true},                            // 起止颠倒
+		{"2025-01-01..2024-12-31", Period{}, true},                            // 跨年颠倒
+		{"2025/01/01..2025/01/07", Period{}, true},
+		{"2025-01-01..", Period{}, true},
+		{"..2025-01-01", Period{}, true},
+		{"", Period{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePeriod(tt.spec)
+		if (err != nil) != tt.err {
+			t.Errorf("ParsePeriod(%q) 错误 = %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParsePeriod(%q) = %+v，应为 %+v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestPeriodFilter(t *testing.T) {
+	records := []sales.Record{
+		{Date: "2024-12-31", Product: "手机"},
+		{Date: "2025-01-01", Product: "电脑"},
+		{Date: "2025-01-07", Product: "平板"},
+		{Date: "2025-01-08", Product: "耳机"},
+	}
+	p := Period{"2025-01-01", "2025-01-07"}
+	want := []sales.Record{records[1], records[2]}
+	if got := p.Filter(records); !reflect.DeepEqual(got, want) {
+		t.Errorf("Filter() = %+v，应为 %+v", got, want)
+	}
+	if got := p.String(); got != "2025-01-01..2025-01-07" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := (Period{"2025-01-01", "2025-01-01"}).String(); got != "2025-01-01" {
+		t.Errorf("单日区间 String() = %q", got)
+	}
+}
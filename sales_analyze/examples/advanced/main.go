@@ -4,13 +4,12 @@ import (
 	"fmt"
 	"os"
 	"sort"
-	"strconv"
 	"strings"
-	"time"
 
-	"github.com/fatih/color"
 	"github.com/gocarina/gocsv"
-	"github.com/olekukonko/tablewriter"
+
+	"sales-analyzer/style"
+	"sales-analyzer/table"
 )
 
 // SalesRecord 销售记录结构体，使用CSV标签映射
@@ -39,21 +38,21 @@ type RegionSummary struct {
 	RecordCount int
 }
 
+// out 标准输出的样式，输出不是终端时不带颜色
+var out = style.For(os.Stdout)
+
 func main() {
-	// 设置颜色输出
-	color.Set(color.FgCyan, color.Bold)
-	fmt.Println("📊 高级销售数据分析系统")
-	color.Unset()
+	fmt.Println(out.Paint(style.Title, "📊 高级销售数据分析系统"))
 	fmt.Println(strings.Repeat("=", 50))
 
 	// 读取CSV文件
 	records, err := loadSalesData("sales_data.csv")
 	if err != nil {
-		color.Red("❌ 读取数据失败: %v", err)
+		fmt.Println(out.Sprintf(style.Error, "❌ 读取数据失败: %v", err))
 		return
 	}
 
-	color.Green("✅ 成功读取 %d 条销售记录", len(records))
+	fmt.Println(out.Sprintf(style.Success, "✅ 成功读取 %d 条销售记录", len(records)))
 	fmt.Println()
 
 	// 执行各种分析
@@ -84,9 +83,7 @@ func loadSalesData(filename string) ([]SalesRecord, error) {
 
 // analyzeOverall 总体分析
 func analyzeOverall(records []SalesRecord) {
-	color.Set(color.FgYellow, color.Bold)
-	fmt.Println("📈 总体销售分析")
-	color.Unset()
+	fmt.Println(out.Paint(style.Header, "📈 总体销售分析"))
 
 	var totalAmount float64
 	var totalQuantity int
@@ -98,24 +95,19 @@ func analyzeOverall(records []SalesRecord) {
 
 	avgAmount := totalAmount / float64(len(records))
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"指标", "数值"})
-	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
-	table.SetCenterSeparator("|")
+	t := newTable([]string{"指标", "数值"}, 1)
 
-	table.Append([]string{"总销售额", fmt.Sprintf("¥ %.2f", totalAmount)})
-	table.Append([]string{"总销量", fmt.Sprintf("%d 件", totalQuantity)})
-	table.Append([]string{"平均订单金额", fmt.Sprintf("¥ %.2f", avgAmount)})
-	table.Append([]string{"订单数量", fmt.Sprintf("%d 笔", len(records))})
+	t.Append("总销售额", fmt.Sprintf("¥ %.2f", totalAmount))
+	t.Append("总销量", fmt.Sprintf("%d 件", totalQuantity))
+	t.Append("平均订单金额", fmt.Sprintf("¥ %.2f", avgAmount))
+	t.Append("订单数量", fmt.Sprintf("%d 笔", len(records)))
 
-	table.Render()
+	t.Render(os.Stdout)
 }
 
 // analyzeByProduct 按产品分析
 func analyzeByProduct(records []SalesRecord) {
-	color.Set(color.FgMagenta, color.Bold)
-	fmt.Println("🛍️  产品销售分析")
-	color.Unset()
+	fmt.Println(out.Paint(style.Header, "🛍️  产品销售分析"))
 
 	productMap := make(map[string]*ProductSummary)
 
@@ -149,29 +141,24 @@ func analyzeByProduct(records []SalesRecord) {
 		return products[i].TotalAmount > products[j].TotalAmount
 	})
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"产品", "销量", "销售额", "平均订单", "订单数"})
-	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
-	table.SetCenterSeparator("|")
+	t := newTable([]string{"产品", "销量", "销售额", "平均订单", "订单数"}, 1, 2, 3, 4)
 
 	for _, product := range products {
-		table.Append([]string{
+		t.Append(
 			product.Product,
 			fmt.Sprintf("%d", product.TotalQty),
 			fmt.Sprintf("¥ %.2f", product.TotalAmount),
 			fmt.Sprintf("¥ %.2f", product.AvgAmount),
 			fmt.Sprintf("%d", product.RecordCount),
-		})
+		)
 	}
 
-	table.Render()
+	t.Render(os.Stdout)
 }
 
 // analyzeByRegion 按地区分析
 func analyzeByRegion(records []SalesRecord) {
-	color.Set(color.FgBlue, color.Bold)
-	fmt.Println("🗺️  地区销售分析")
-	color.Unset()
+	fmt.Println(out.Paint(style.Header, "🗺️  地区销售分析"))
 
 	regionMap := make(map[string]*RegionSummary)
 
@@ -200,10 +187,7 @@ func analyzeByRegion(records []SalesRecord) {
 		return regions[i].TotalAmount > regions[j].TotalAmount
 	})
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"地区", "销量", "销售额", "订单数", "市场占比"})
-	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
-	table.SetCenterSeparator("|")
+	t := newTable([]string{"地区", "销量", "销售额", "订单数", "市场占比"}, 1, 2, 3, 4)
 
 	// 计算总销售额用于计算占比
 	var totalAmount float64
@@ -213,23 +197,21 @@ func analyzeByRegion(records []SalesRecord) {
 
 	for _, region := range regions {
 		marketShare := (region.TotalAmount / totalAmount) * 100
-		table.Append([]string{
+		t.Append(
 			region.Region,
 			fmt.Sprintf("%d", region.TotalQty),
 			fmt.Sprintf("¥ %.2f", region.TotalAmount),
 			fmt.Sprintf("%d", region.RecordCount),
 			fmt.Sprintf("%.1f%%", marketShare),
-		})
+		)
 	}
 
-	table.Render()
+	t.Render(os.Stdout)
 }
 
 // analyzeByDate 按日期分析
 func analyzeByDate(records []SalesRecord) {
-	color.Set(color.FgGreen, color.Bold)
-	fmt.Println("📅 日期销售分析")
-	color.Unset()
+	fmt.Println(out.Paint(style.Header, "📅 日期销售分析"))
 
 	dateMap := make(map[string]float64)
 	dateQtyMap := make(map[string]int)
@@ -250,10 +232,7 @@ func analyzeByDate(records []SalesRecord) {
 		return dates[i] < dates[j]
 	})
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"日期", "销量", "销售额", "日增长率"})
-	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
-	table.SetCenterSeparator("|")
+	t := newTable([]string{"日期", "销量", "销售额", "日增长率"}, 1, 2, 3)
 
 	var prevAmount float64
 	for i, date := range dates {
@@ -267,28 +246,26 @@ func analyzeByDate(records []SalesRecord) {
 		} else {
 			rate := ((amount - prevAmount) / prevAmount) * 100
 			if rate > 0 {
-				growthRate = color.GreenString("+%.1f%%", rate)
+				growthRate = out.Sprintf(style.Success, "+%.1f%%", rate)
 			} else {
-				growthRate = color.RedString("%.1f%%", rate)
+				growthRate = out.Sprintf(style.Error, "%.1f%%", rate)
 			}
 			prevAmount = amount
 		}
 
-		table.Append([]string{
+		t.Append(
 			date,
 			fmt.Sprintf("%d", qty),
 			fmt.Sprintf("¥ %.2f", amount),
 			growthRate,
-		})
+		)
 	}
 
-	table.Render()
+	t.Render(os.Stdout)
 
 	// 显示趋势分析
 	fmt.Println()
-	color.Set(color.FgCyan)
-	fmt.Println("📊 趋势分析:")
-	color.Unset()
+	fmt.Println(out.Paint(style.Info, "📊 趋势分析:"))
 	
 	if len(dates) >= 2 {
 		firstDay := dateMap[dates[0]]
@@ -296,9 +273,9 @@ func analyzeByDate(records []SalesRecord) {
 		totalGrowth := ((lastDay - firstDay) / firstDay) * 100
 		
 		if totalGrowth > 0 {
-			color.Green("📈 整体增长: +%.1f%%", totalGrowth)
+			fmt.Println(out.Sprintf(style.Success, "📈 整体增长: +%.1f%%", totalGrowth))
 		} else {
-			color.Red("📉 整体下降: %.1f%%", totalGrowth)
+			fmt.Println(out.Sprintf(style.Error, "📉 整体下降: %.1f%%", totalGrowth))
 		}
 	}
 
@@ -325,6 +302,23 @@ func analyzeByDate(records []SalesRecord) {
 		}
 	}
 	
-	color.Green("🏆 最佳销售日: %s (¥ %.2f)", bestDay, maxAmount)
-	color.Red("📉 最低销售日: %s (¥ %.2f)", worstDay, minAmount)
-}
\ No newline at end of file
+	fmt.Println(out.Sprintf(style.Success, "🏆 最佳销售日: %s (¥ %.2f)", bestDay, maxAmount))
+	fmt.Println(out.Sprintf(style.Error, "📉 最低销售日: %s (¥ %.2f)", worstDay, minAmount))
+}
+
+// tableStyle 只有左右边框和表头分隔线的表格样式
+var tableStyle = table.Style{
+	Header: table.Line{Left: "|", Fill: "-", Cross: "|", Right: "|"},
+	Left:   "|", Separator: "|", Right: "|",
+}
+
+// newTable 创建适应终端宽度的表格，numeric 中的列右对齐
+func newTable(header []string, numeric ...int) *table.Table {
+	t := table.New(header...)
+	t.Style = &tableStyle
+	t.MaxWidth = table.TerminalWidth(os.Stdout)
+	for _, col := range numeric {
+		t.SetAlign(col, table.AlignRight)
+	}
+	return t
+}
@@ -2,22 +2,15 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"strings"
-)
 
-// ANSI 颜色代码常量
-const (
-	ColorReset  = "\033[0m"    // 重置颜色
-	ColorRed    = "\033[31m"   // 红色
-	ColorGreen  = "\033[32m"   // 绿色
-	ColorYellow = "\033[33m"   // 黄色
-	ColorBlue   = "\033[34m"   // 蓝色
-	ColorPurple = "\033[35m"   // 紫色
-	ColorCyan   = "\033[36m"   // 青色
-	ColorWhite  = "\033[37m"   // 白色
-	ColorBold   = "\033[1m"    // 粗体
+	"sales-analyzer/style"
 )
 
+// out 标准输出的样式，输出不是终端或设置了 NO_COLOR 时不带颜色
+var out = style.For(os.Stdout)
+
 func main() {
 	fmt.Println("🎨 图标和颜色演示程序")
 	fmt.Println(strings.Repeat("=", 30))
@@ -29,21 +22,25 @@ func main() {
 	fmt.Println("🏆 奖杯  ✅ 成功  ❌ 错误  ⚠️ 警告")
 	fmt.Println("🎯 目标  🚀 火箭  💡 灯泡  🔥 火焰")
 	
-	// 2. ANSI 颜色代码演示
-	fmt.Println("\n🌈 ANSI 颜色代码演示:")
-	fmt.Printf("%s红色文本%s\n", ColorRed, ColorReset)
-	fmt.Printf("%s绿色文本%s\n", ColorGreen, ColorReset)
-	fmt.Printf("%s黄色文本%s\n", ColorYellow, ColorReset)
-	fmt.Printf("%s蓝色文本%s\n", ColorBlue, ColorReset)
-	fmt.Printf("%s紫色文本%s\n", ColorPurple, ColorReset)
-	fmt.Printf("%s青色文本%s\n", ColorCyan, ColorReset)
-	fmt.Printf("%s%s粗体文本%s\n", ColorBold, ColorYellow, ColorReset)
-	
+	// 2. 主题颜色演示
+	fmt.Printf("\n🌈 主题颜色演示 (颜色能力: %s):\n", out.Profile())
+	fmt.Println(out.Paint(style.Title, "标题文本"))
+	fmt.Println(out.Paint(style.Header, "章节标题"))
+	fmt.Println(out.Paint(style.Success, "成功文本"))
+	fmt.Println(out.Paint(style.Warning, "警告文本"))
+	fmt.Println(out.Paint(style.Error, "错误文本"))
+	fmt.Println(out.Paint(style.Info, "提示文本"))
+	fmt.Println(out.Paint(style.Muted, "次要文本"))
+	for i := 0; i < 6; i++ {
+		fmt.Print(out.Series(i, "██"), " ")
+	}
+	fmt.Println()
+
 	// 3. 组合使用
 	fmt.Println("\n🎭 组合使用演示:")
-	fmt.Printf("%s%s🎉 彩色图标组合 🎊%s\n", ColorBold, ColorGreen, ColorReset)
-	fmt.Printf("%s⚡ %s高性能%s%s ⚡%s\n", ColorYellow, ColorBold, ColorReset, ColorYellow, ColorReset)
-	
+	fmt.Println(out.Paint(style.Success, "🎉 彩色图标组合 🎊"))
+	fmt.Println(out.Paint(style.Warning, "⚡ ") + out.Paint(style.Title, "高性能") + out.Paint(style.Warning, " ⚡"))
+
 	// 4. 实际应用场景
 	fmt.Println("\n💼 实际应用场景:")
 	printStatus("success", "✅ 数据加载成功")
@@ -54,34 +51,23 @@ func main() {
 	// 5. 进度条模拟
 	fmt.Println("\n📈 进度条演示:")
 	for i := 0; i <= 100; i += 20 {
-		fmt.Printf("\r%s处理进度: ", ColorCyan)
-		for j := 0; j < i/5; j++ {
-			fmt.Print("█")
-		}
-		for j := i/5; j < 20; j++ {
-			fmt.Print("░")
-		}
-		fmt.Printf(" %d%%%s", i, ColorReset)
+		bar := strings.Repeat("█", i/5) + strings.Repeat("░", 20-i/5)
+		fmt.Print("\r", out.Sprintf(style.Info, "处理进度: %s %d%%", bar, i))
 		if i == 100 {
-			fmt.Printf(" %s🎉 完成!%s\n", ColorGreen, ColorReset)
+			fmt.Println(" " + out.Paint(style.Success, "🎉 完成!"))
 		}
 	}
 }
 
 // 状态打印函数
 func printStatus(level, message string) {
-	switch level {
-	case "success":
-		fmt.Printf("%s%s%s\n", ColorGreen, message, ColorReset)
-	case "error":
-		fmt.Printf("%s%s%s\n", ColorRed, message, ColorReset)
-	case "warning":
-		fmt.Printf("%s%s%s\n", ColorYellow, message, ColorReset)
-	case "info":
-		fmt.Printf("%s%s%s\n", ColorBlue, message, ColorReset)
-	default:
-		fmt.Println(message)
+	roles := map[string]style.Role{
+		"success": style.Success,
+		"error":   style.Error,
+		"warning": style.Warning,
+		"info":    style.Info,
 	}
+	fmt.Println(out.Paint(roles[level], message))
 }
 
 // 可用的常见 Unicode Emoji (按分类整理):
@@ -0,0 +1,133 @@
+// 外部分析程序示例: 按星期统计销售额。
+//
+// 编译为 sales-weekdays 后用 -plugins 注册，分析名称为 weekdays:
+//
+//	go build -o bin/sales-weekdays ./examples/plugin-weekdays
+//	bin/sales summary -plugins bin/sales-weekdays -reports overall,weekdays
+//
+// 程序从标准输入读取请求，向标准输出写回章节，协议见 sales-analyzer/analyzer 包的 External。
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// request 只解码用到的字段
+type request struct {
+	Locale  string `json:"locale"`
+	Records []struct {
+		Date   string  `json:"date"`
+		Amount float64 `json:"amount"`
+	} `json:"records"`
+}
+
+type note struct {
+	Level string `json:"level"`
+	Text  string `json:"text"`
+}
+
+type kpi struct {
+	Label string  `json:"label"`
+	Value float64 `json:"value"`
+	Kind  string  `json:"kind"`
+}
+
+type chart struct {
+	Kind      string    `json:"kind"`
+	Title     string    `json:"title"`
+	Labels    []string  `json:"labels"`
+	Values    []float64 `json:"values"`
+	ValueKind string    `json:"value_kind"`
+}
+
+type table struct {
+	Columns []string `json:"columns"`
+	Kinds   []string `json:"kinds"`
+	Rows    [][]any  `json:"rows"`
+}
+
+type response struct {
+	Title  string  `json:"title"`
+	KPIs   []kpi   `json:"kpis"`
+	Charts []chart `json:"charts"`
+	Tables []table `json:"tables"`
+	Notes  []note  `json:"notes,omitempty"`
+	Data   any     `json:"data"`
+}
+
+// text 示例只区分中文和英文
+type text struct {
+	title, chart, weekday, amount, orders, share, weekend, best string
+	days                                                        [7]string
+}
+
+var texts = map[bool]text{
+	true: {"📆 星期销售分析", "各星期销售额", "星期", "销售额", "订单数", "占比", "周末销售额占比", "🏆 销售额最高的是%s",
+		[7]string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"}},
+	false: {"📆 Sales by Weekday", "Sales by weekday", "Weekday", "Sales", "Orders", "Share", "Weekend share of sales", "🏆 Best weekday: %s",
+		[7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}},
+}
+
+func main() {
+	var req request
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, "sales-weekdays:", err)
+		os.Exit(1)
+	}
+	t := texts[req.Locale == "" || strings.HasPrefix(req.Locale, "zh")]
+
+	var amounts [7]float64
+	var orders [7]int
+	var total float64
+	for _, r := range req.Records {
+		date, err := time.Parse("2006-01-02", r.Date)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "sales-weekdays:", err)
+			os.Exit(1)
+		}
+		amounts[date.Weekday()] += r.Amount
+		orders[date.Weekday()]++
+		total += r.Amount
+	}
+
+	resp := response{
+		Title:  t.title,
+		Charts: []chart{{Kind: "bar", Title: t.chart, ValueKind: "money"}},
+		Tables: []table{{
+			Columns: []string{t.weekday, t.amount, t.orders, t.share},
+			Kinds:   []string{"text", "money", "int", "percent"},
+		}},
+	}
+	data := make(map[string]float64)
+	best := -1
+	// 周一在前
+	for _, day := range []time.Weekday{1, 2, 3, 4, 5, 6, 0} {
+		share := 0.0
+		if total > 0 {
+			share = amounts[day] / total * 100
+		}
+		resp.Charts[0].Labels = append(resp.Charts[0].Labels, t.days[day])
+		resp.Charts[0].Values = append(resp.Charts[0].Values, amounts[day])
+		resp.Tables[0].Rows = append(resp.Tables[0].Rows, []any{t.days[day], amounts[day], orders[day], share})
+		data[day.String()] = amounts[day]
+		if orders[day] > 0 && (best < 0 || amounts[day] > amounts[best]) {
+			best = int(day)
+		}
+	}
+	if total > 0 {
+		resp.KPIs = []kpi{{t.weekend, (amounts[0] + amounts[6]) / total * 100, "percent"}}
+	}
+	if best >= 0 {
+		resp.Notes = []note{{"success", fmt.Sprintf(t.best, t.days[best])}}
+	}
+	resp.Data = data
+
+	if err := json.NewEncoder(os.Stdout).Encode(resp); err != nil {
+		fmt.Fprintln(os.Stderr, "sales-weekdays:", err)
+		os.Exit(1)
+	}
+}
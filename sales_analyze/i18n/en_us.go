@@ -0,0 +1,352 @@
+package i18n
+
+func init() {
+	register(&Locale{
+		Tag:           "en-US",
+		Decimal:       ".",
+		Group:         ",",
+		Currency:      "CN¥%s",
+		ExcelCurrency: `"CN¥"#,##0.00`,
+		Messages:      enUS,
+	})
+}
+
+// enUS 英文消息目录，键为源代码中的中文原文
+var enUS = map[string]string{
+	// 报表标题和通用字段
+	"📊 高级销售数据分析系统":           "📊 Advanced Sales Analysis",
+	"✅ 成功读取 %d 条销售记录":        "✅ Loaded %d sales records",
+	"数据来源: ":                 "Source: ",
+	"生成时间: ":                 "Generated: ",
+	"第 %d / {nb} 页":          "Page %d of {nb}",
+	" (续)":                   " (cont.)",
+	"原始数据":                   "Raw data",
+	"日期":                     "Date",
+	"产品":                     "Product",
+	"地区":                     "Region",
+	"销量":                     "Quantity",
+	"销售额":                    "Sales",
+	"指标":                     "Metric",
+	"数值":                     "Value",
+	"件":                      "units",
+	"笔":                      "orders",
+	"总体":                     "Overall",
+	"最高 %s (%s)  最低 %s (%s)": "High %s (%s)  Low %s (%s)",
+
+	// 总体、产品、地区、日期分析
+	"📈 总体销售分析":          "📈 Sales Overview",
+	"总销售额":              "Total sales",
+	"总销量":               "Total quantity",
+	"平均订单金额":            "Average order value",
+	"订单数量":              "Orders",
+	"🛍️  产品销售分析":        "🛍️  Sales by Product",
+	"各产品销售额":            "Sales by product",
+	"平均订单":              "Avg. order",
+	"订单数":               "Orders",
+	"🏆 最佳销售产品: %s (%s)": "🏆 Top product: %s (%s)",
+	"🗺️  地区销售分析":        "🗺️  Sales by Region",
+	"各地区销售额":            "Sales by region",
+	"市场占比":              "Market share",
+	"🏆 最佳销售地区: %s (%s)": "🏆 Top region: %s (%s)",
+	"📅 日期销售分析":          "📅 Sales by Date",
+	"每日销售额":             "Daily sales",
+	"日增长率":              "Daily growth",
+	"📊 趋势分析:":           "📊 Trend:",
+	"📈 整体增长: %s":        "📈 Overall growth: %s",
+	"📉 整体变化: %s":        "📉 Overall change: %s",
+	"🏆 最佳销售日: %s (%s)":  "🏆 Best day: %s (%s)",
+	"📉 最低销售日: %s (%s)":  "📉 Worst day: %s (%s)",
+	"⚠️  %s 销售额较前一日下降 %s，超过 %s 的提示阈值": "⚠️  %s sales fell %s from the previous day, above the %s alert threshold",
+
+	// 近似统计
+	"🔬 近似统计分析": "🔬 Approximate Statistics",
+	"维度":       "Dimension",
+	"近似去重数":    "Approx. distinct",
+	"相对误差":     "Relative error",
+	"订单金额分布":   "Order value distribution",
+	"秩误差":      "Rank error",
+	"销售日":      "Sales days",
+	"%s 在售产品":  "Products sold in %s",
+	"全部订单":     "All orders",
+	"产品 %s":    "Product %s",
+	"地区 %s":    "Region %s",
+	"📦 订单销量中位数: %.0f 件 (P90: %.0f 件)": "📦 Median order quantity: %.0f units (P90: %.0f units)",
+
+	// 目标达成
+	"🎯 目标达成分析":     "🎯 Target Attainment",
+	"截止日期: %s":     "As of: %s",
+	"月份":           "Month",
+	"地区/产品":        "Region/Product",
+	"目标":           "Target",
+	"实际":           "Actual",
+	"达成率":          "Attainment",
+	"差距":           "Gap",
+	"预计完成":         "Projected",
+	"预计达成率":        "Projected attainment",
+	"状态":           "Status",
+	"✅ 正常":         "✅ On track",
+	"⚠️ 落后":        "⚠️ Behind",
+	"✅ 所有地区均按计划推进": "✅ All regions are on track",
+	"⚠️  %s %s 进度落后: 预计完成 %s，距目标还差 %s": "⚠️  %s %s is behind pace: projected %s, %s short of target",
+
+	// 情景模拟
+	"🔮 情景模拟: %s":         "🔮 Scenario: %s",
+	"共 %d 项调整，影响 %d 条记录": "%d adjustments affecting %d records",
+	"基准":                 "Baseline",
+	"情景":                 "Scenario",
+	"变化":                 "Change",
+	"变化率":                "Change %",
+	"基准销量":               "Baseline qty",
+	"情景销量":               "Scenario qty",
+	"基准销售额":              "Baseline sales",
+	"情景销售额":              "Scenario sales",
+	"📈 情景下总销售额变化: %s":    "📈 Total sales change under scenario: %s",
+	"📉 情景下总销售额变化: %s":    "📉 Total sales change under scenario: %s",
+
+	// 数据差异
+	"🔍 数据差异分析":            "🔍 Dataset Diff",
+	"原数据: %s  →  新数据: %s": "Old: %s  →  New: %s",
+	"匹配键: %s":             "Match key: %s",
+	"类型":                  "Type",
+	"记录数":                 "Records",
+	"➕ 新增":                "➕ Added",
+	"➖ 删除":                "➖ Removed",
+	"✏️ 修改":               "✏️ Modified",
+	"未变化":                 "Unchanged",
+	"✅ 两份数据没有差异":          "✅ The two datasets are identical",
+	"➕ 新增记录":              "➕ Added records",
+	"➖ 删除记录":              "➖ Removed records",
+	"✏️  修改记录":            "✏️  Modified records",
+	"键":                   "Key",
+	"字段":                  "Field",
+	"原值":                  "Old value",
+	"新值":                  "New value",
+	"📊 对汇总的影响":            "📊 Effect on totals",
+	"范围":                  "Scope",
+	"原销量":                 "Old qty",
+	"新销量":                 "New qty",
+	"原销售额":                "Old sales",
+	"新销售额":                "New sales",
+
+	// 交互界面
+	"📊 销售数据浏览":       "📊 Sales Explorer",
+	"总览":             "Overview",
+	"日期 %s":          "Date %s",
+	"筛选: ":           "Filter: ",
+	"显示 %d / %d 条记录": "Showing %d of %d records",
+	"按 / 输入条件，如 product:手机 region:华东 date:2025-01-01..2025-01-02": "Press / to filter, e.g. product:手机 region:华东 date:2025-01-01..2025-01-02",
+	"回车 确定  Esc 取消  Ctrl+U 清空":                                    "Enter apply  Esc cancel  Ctrl+U clear",
+	"←/→ 切换  ↑/↓ 选择  s 排序  r 反向  / 筛选  Esc 返回  q 退出":              "←/→ tabs  ↑/↓ move  s sort  r reverse  / filter  Esc back  q quit",
+	"←/→ 切换  ↑/↓ 选择  s 排序  r 反向  / 筛选  回车 明细  q 退出":               "←/→ tabs  ↑/↓ move  s sort  r reverse  / filter  Enter details  q quit",
+
+	// 示例模板
+	"最佳销售日": "Best day",
+
+	// 命令行提示
+	"❌ 读取数据失败: %v\n":          "❌ Failed to read data: %v\n",
+	"❌ 数据对比失败: %v\n":          "❌ Diff failed: %v\n",
+	"❌ 读取目标失败: %v\n":          "❌ Failed to read targets: %v\n",
+	"❌ 目标分析失败: %v\n":          "❌ Target analysis failed: %v\n",
+	"❌ 分析失败: %v\n":            "❌ Analysis failed: %v\n",
+	"❌ 读取情景失败: %v\n":          "❌ Failed to read scenario: %v\n",
+	"❌ 无法创建输出文件: %v\n":        "❌ Cannot create output file: %v\n",
+	"❌ 输出报表失败: %v\n":          "❌ Failed to write report: %v\n",
+	"❌ %s 格式需要用 -o 指定输出文件\n":  "❌ The %s format requires an output file (-o)\n",
+	"❌ 未知命令: %q":              "❌ Unknown command: %q",
+	"❌ 日期格式错误: %q\n":          "❌ Invalid date: %q\n",
+	"❌ 起始日期晚于结束日期: %s > %s\n": "❌ Start date is after end date: %s > %s\n",
+	"⚠️  %s，跳过":               "⚠️  %s, skipped",
+	"✅ 共 %d 行数据，没有发现问题":       "✅ %d rows checked, no problems found",
+	"❌ 共 %d 行数据，发现 %d 个问题":    "❌ %d rows checked, %d problems found",
+
+	// 分析服务
+	"❌ 无法监听 %s: %v\n": "❌ Cannot listen on %s: %v\n",
+	"🌐 分析服务已启动，在浏览器中打开 http://%s/ 查看看板 (%d 条记录，Ctrl+C 退出)": "🌐 Server started, open http://%s/ for the dashboard (%d records, Ctrl+C to quit)",
+
+	// 运行日志 (debug 级别)
+	"📄 读取配置文件 %s": "📄 Using config file %s",
+	"📂 已读取 %d 个文件 (其中 %d 个重新读取)，共 %d 条记录，用时 %s": "📂 Read %d files (%d reloaded), %d records in %s",
+	"🧮 分析 %s 完成，用时 %s": "🧮 Analysis %s finished in %s",
+
+	// 进度
+	"汇总":     "Aggregating",
+	"%s 行":   "%s rows",
+	"%s 行/秒": "%s rows/s",
+	"用时 %s":  "took %s",
+	"剩余 %s":  "ETA %s",
+
+	// 监视模式
+	"👀 %s 已读取 %s，正在监视 %d 个文件的变化 (Ctrl+C 退出)": "👀 %s read %s, watching %d files for changes (Ctrl+C to quit)",
+	"👀 %s 已更新，正在监视 %d 个文件的变化 (Ctrl+C 退出)":    "👀 %s updated, watching %d files for changes (Ctrl+C to quit)",
+	"👀 %s 正在监视输入文件，修正后自动重新读取 (Ctrl+C 退出)":    "👀 %s watching inputs, will reload once fixed (Ctrl+C to quit)",
+
+	// 网页看板
+	"筛选":                "Filters",
+	"起始日期":              "From",
+	"结束日期":              "To",
+	"日期分组":              "Group dates",
+	"按日":                "Daily",
+	"按周":                "Weekly",
+	"按月":                "Monthly",
+	"重置":                "Reset",
+	"销售额趋势":             "Sales trend",
+	"没有符合条件的数据":         "No data matches the filters",
+	"共 %d 条记录 · 更新于 %s": "%d records · updated %s",
+	"🔄 数据已重新读取: %d 条记录":          "🔄 Data reloaded: %d records",
+	"⚠️  重新读取数据失败，继续使用之前的数据: %v": "⚠️  Reload failed, keeping previous data: %v",
+
+	// 配置来源
+	"配置文件 %s": "config file %s",
+	"环境变量 %s": "environment variable %s",
+	"参数 -%s":  "flag -%s",
+
+	// 数据检查
+	"数据格式错误: 需要%d列，实际%d列": "malformed row: expected %d columns, got %d",
+	"销量数据错误: %v":          "invalid quantity: %v",
+	"销售额数据错误: %v":         "invalid amount: %v",
+	"日期格式错误: %q":          "invalid date: %q",
+	"产品为空":                "empty product",
+	"地区为空":                "empty region",
+	"销量为负数: %d":           "negative quantity: %d",
+	"销售额为负数: %v":          "negative amount: %v",
+	"销售额不是有效的数值: %v":      "amount is not a finite number: %v",
+
+	// 命令行帮助
+	"用法: sales <命令> [参数] [输入文件...]\n\n命令:\n": "Usage: sales <command> [flags] [input files...]\n\nCommands:\n",
+	"\n使用 \"sales <命令> -h\" 查看命令的参数。\n":      "\nRun \"sales <command> -h\" for the flags of a command.\n",
+	"用法: sales %s [参数] [输入文件...]\n\n参数:\n":   "Usage: sales %s [flags] [input files...]\n\nFlags:\n",
+	"\n参数优先于环境变量 (%s*)，环境变量优先于配置文件。\n":       "\nFlags override environment variables (%s*), which override the config file.\n",
+	"完整分析报告: 总体、产品、地区、日期，可附目标达成、情景模拟和近似统计":   "full report: overall, products, regions and dates, optionally with targets, scenarios and approximate statistics",
+	"产品销售分析": "sales by product",
+	"地区销售分析": "sales by region",
+	"日期销售分析": "sales by date",
+	"导出清洗、筛选后的记录 (csv/json/xlsx)":                       "export cleaned and filtered records (csv/json/xlsx)",
+	"检查输入文件，列出所有有问题的数据行":                                "check the input files and list every problematic row",
+	"对比两份数据或两个时间段":                                      "compare two datasets or two periods",
+	"全屏交互界面":                                            "full-screen interactive interface",
+	"以网页看板和HTTP JSON接口提供分析结果，输入文件变化后自动重新读取":             "serve a web dashboard and HTTP JSON API, reloading when input files change",
+	"输出合成后生效的配置: 默认值 < 配置文件 < 环境变量 < 参数":                "print the effective configuration: defaults < config file < environment < flags",
+	"配置文件 (YAML)，默认读取 $SALES_CONFIG 或当前目录下的 %s":         "config file (YAML), defaults to $SALES_CONFIG or %s in the current directory",
+	"输入CSV文件或目录 (读取其中的 *.csv)，可以重复指定或用逗号分隔，多个文件的记录合并分析": "input CSV file or directory (reads its *.csv), repeatable or comma-separated; records of all files are analysed together",
+	"只分析这些产品，逗号分隔":                                      "only analyse these products, comma-separated",
+	"只分析这些地区，逗号分隔":                                      "only analyse these regions, comma-separated",
+	"起始日期 (含)，格式 %s":                                    "start date (inclusive), format %s",
+	"结束日期 (含)，格式 %s":                                    "end date (inclusive), format %s",
+	"报表语言: %s，默认读取 LC_ALL/LC_MESSAGES/LANG 环境变量":        "report language: %s, defaults to the LC_ALL/LC_MESSAGES/LANG environment variables",
+	"金额格式，%s 为金额数字，如 \"US$%s\"，默认由语言决定":                 "money format, %s is the amount, e.g. \"US$%s\"; defaults to the language's format",
+	"配色主题: %s": "color theme: %s",
+	"颜色输出: %s": "color output: %s",
+	"日志级别: %s": "log level: %s",
+	"日志格式: %s；日志写到标准错误，报表写到标准输出": "log format: %s; logs go to stderr, reports to stdout",
+	"日志文件 (追加写入)，默认写到标准错误":       "log file (appended), defaults to stderr",
+	"输出格式: %s":       "output format: %s",
+	"报表输出文件，默认为标准输出": "report output file, defaults to stdout",
+	"按模板文件输出报表 (.html/.htm 用 html/template，其他用 text/template)，忽略 -format": "render the report with a template file (html/template for .html/.htm, text/template otherwise); overrides -format",
+	"PDF报表使用的中文TrueType字体文件 (.ttf)，默认自动查找":                                "Chinese TrueType font (.ttf) for PDF reports, found automatically by default",
+	"终端报表的表格边框样式: %s":                                                     "table border style for terminal reports: %s",
+	"监视输入文件或目录，变化后重新读取并原地重绘报表，Ctrl+C 退出":                                  "watch the inputs and redraw the report in place when they change; Ctrl+C to quit",
+	"监视模式下检查输入文件的间隔":                                                      "how often to check the inputs in watch mode",
+	"监视模式下文件停止变化多久后再重新读取":                                                 "in watch mode, how long files must stay unchanged before reloading",
+	"what-if情景文件 (JSON)，与基准对比展示":                                          "what-if scenario file (JSON), shown against the baseline",
+	"输出的分析，逗号分隔: %s 或 -plugins 注册的外部分析":                                   "analyses to output, comma-separated: %s or external analyses registered with -plugins",
+	"外部分析程序 (通过标准输入输出交换JSON，见 analyzer 包)，可以重复指定或用逗号分隔；分析名称为去掉 sales- 前缀的文件名": "external analysis programs (exchanging JSON over stdin/stdout, see package analyzer), repeatable or comma-separated; the analysis is named after the file without the sales- prefix",
+	"每次运行外部分析程序的超时秒数 (0 表示不限制)":                                               "timeout in seconds for each run of an external analysis program (0 for none)",
+	"并行汇总的工作协程数 (0 表示CPU核数)":                                                  "number of aggregation workers (0 for the number of CPUs)",
+	"月度销售目标CSV文件 (月份,地区,产品,目标销售额)":                                            "monthly sales targets CSV file (month,region,product,target amount)",
+	"日销售额较前一日下降超过该百分比时提示，如 20 (0 表示不提示)":                                      "warn when daily sales drop by more than this percentage, e.g. 20 (0 to disable)",
+	"近似去重计数的相对误差，如 0.01 (0 表示不启用)":                                            "relative error of approximate distinct counts, e.g. 0.01 (0 to disable)",
+	"近似分位数的秩误差，如 0.01 (0 表示不启用)":                                              "rank error of approximate quantiles, e.g. 0.01 (0 to disable)",
+	"导出格式: %s": "export format: %s",
+	"输出文件，默认为标准输出 (xlsx 必须指定)":                                     "output file, defaults to stdout (required for xlsx)",
+	"旧版数据文件，与输入数据对比，列出新增、删除和修改的记录":                                 "previous data file to compare with the inputs, listing added, removed and changed records",
+	"对比输入数据的两个时间段，如 2025-01-01..2025-01-02,2025-01-03..2025-01-04": "compare two periods of the inputs, e.g. 2025-01-01..2025-01-02,2025-01-03..2025-01-04",
+	"对比时匹配记录的键 (date/product/region，逗号分隔)":                         "key for matching records (date/product/region, comma-separated)",
+	"监听地址": "listen address",
+	"两次检查输入文件是否变化的最短间隔":                            "minimum interval between checks for changed input files",
+	"保存上传数据的目录，设置后接受 POST /api/uploads 上传CSV或xlsx": "directory for uploaded data; when set, accepts CSV or xlsx via POST /api/uploads",
+	// 错误信息
+	"起始日期晚于结束日期: %s > %s":   "start date is after end date: %s > %s",
+	"起始日期晚于结束日期: %s":        "start date is after end date: %s",
+	"无效的日期分组: %q (可选 %s)":   "invalid date interval: %q (choose %s)",
+	"未知的接口: ":               "unknown endpoint: ",
+	"读取上传文件失败: %w":          "failed to read the upload: %w",
+	"保存上传数据失败: %w":          "failed to save the upload: %w",
+	"无法读取xlsx文件: %w":        "cannot read xlsx file: %w",
+	"xlsx文件没有工作表":           "the xlsx file has no sheets",
+	"CSV文件缺少列: %s":          "CSV file is missing column: %s",
+	"无法打开文件: %w":            "cannot open file: %w",
+	"CSV文件没有数据行":            "CSV file has no data rows",
+	"读取CSV文件失败: %w":         "failed to read CSV file: %w",
+	"没有找到输入文件: %v":          "no input files found: %v",
+	"无法识别截止日期 %q: %w":       "cannot parse the as-of date %q: %w",
+	"无法打开目标文件: %w":          "cannot open targets file: %w",
+	"读取目标文件失败: %w":          "failed to read targets file: %w",
+	"目标文件没有数据行":             "targets file has no data rows",
+	"目标文件缺少列: %s":           "targets file is missing column: %s",
+	"第%d行月份格式错误: %q":        "line %d: invalid month: %q",
+	"第%d行目标销售额错误: %w":       "line %d: invalid target amount: %w",
+	"第%d行目标销售额必须为正数: %q":    "line %d: target amount must be a positive number: %q",
+	"第%d行与第%d行重复: %s %s %s": "line %d duplicates line %d: %s %s %s",
+	"环境变量 %s: %w":           "environment variable %s: %w",
+	"无法读取配置文件: %w":          "cannot read config file: %w",
+	"配置文件 %s 格式错误: %w":      "invalid config file %s: %w",
+	"未知的配置项: %s":            "unknown setting: %s",
+	"inputs: 至少需要一个输入文件":    "inputs: at least one input file is required",
+	"workers: 不能为负数":        "workers: must not be negative",
+	"plugin_timeout: 不能为负数": "plugin_timeout: must not be negative",
+	"%s: 不能为负数":             "%s: must not be negative",
+	"需要整数: %q":              "integer required: %q",
+	"需要数字: %q":              "number required: %q",
+	"不支持的类型 %s":             "unsupported type %s",
+	"不支持的筛选字段: %q":          "unsupported filter field: %q",
+	"筛选条件缺少值: %q":           "filter is missing a value: %q",
+	"交互界面运行失败: %w":          "interactive interface failed: %w",
+	"写入Excel文件失败: %w":       "failed to write Excel file: %w",
+	"无法创建工作表 %q: %w":        "cannot create sheet %q: %w",
+	"无法创建单元格样式: %w":         "cannot create cell style: %w",
+	"未找到中文字体，请用 -pdf-font 或 %s 环境变量指定TrueType字体文件 (.ttf)": "no Chinese font found; specify a TrueType font file (.ttf) with -pdf-font or the %s environment variable",
+	"无法读取字体文件: %w":                               "cannot read font file: %w",
+	"生成PDF失败: %w":                                "failed to generate PDF: %w",
+	"无法读取模板: %w":                                 "cannot read template: %w",
+	"模板语法错误: %w":                                 "template syntax error: %w",
+	"执行模板 %s 失败: %w":                             "failed to execute template %s: %w",
+	"不是数字: %v (%T)":                              "not a number: %v (%T)",
+	"不是日期: %v (%T)":                              "not a date: %v (%T)",
+	"未知的emoji名称: %q":                             "unknown emoji name: %q",
+	"不支持的输出格式: %q (可选 %s)":                       "unsupported output format: %q (choose %s)",
+	"未知的日志级别: %q (可选 %s)":                        "unknown log level: %q (choose %s)",
+	"未知的日志格式: %q (可选 %s)":                        "unknown log format: %q (choose %s)",
+	"无法打开情景文件: %w":                               "cannot open scenario file: %w",
+	"解析情景文件失败: %w":                               "failed to parse scenario file: %w",
+	"情景 %q 没有任何调整":                               "scenario %q has no adjustments",
+	"第%d项调整的字段无效: %q (可选 price/quantity/amount)": "adjustment %d: invalid field %q (choose price/quantity/amount)",
+	"第%d项调整的方式无效: %q (可选 multiply/add)":          "adjustment %d: invalid op %q (choose multiply/add)",
+	"第%d项调整的日期格式错误: %q":                          "adjustment %d: invalid date %q",
+	"超过 %s 未完成":                                  "did not finish within %s",
+	"外部分析程序 %s 运行失败: %w\n%s":                     "external analysis %s failed: %w\n%s",
+	"外部分析程序 %s 运行失败: %w":                         "external analysis %s failed: %w",
+	"外部分析程序 %s 的输出格式错误: %w":                      "external analysis %s returned invalid output: %w",
+	"缺少 title":                                   "missing title",
+	"kpis: 未知的 kind %q":                          "kpis: unknown kind %q",
+	"charts: 未知的 value_kind %q":                  "charts: unknown value_kind %q",
+	"charts: 未知的图表类型 %q (可选 bar/line)":           "charts: unknown chart kind %q (choose bar/line)",
+	"charts: labels 与 values 的数量不一致":             "charts: labels and values differ in length",
+	"tables: kinds 多于 columns":                   "tables: more kinds than columns",
+	"tables: 未知的 kind %q":                        "tables: unknown kind %q",
+	"tables: 第 %d 行有 %d 列，表头有 %d 列":              "tables: row %d has %d columns, the header has %d",
+	"未知的 level %q (可选 info/success/warning)":     "unknown level %q (choose info/success/warning)",
+	"分析名称不能为空":                                   "analysis name must not be empty",
+	"分析 %q 已经注册":                                 "analysis %q is already registered",
+	"reports: 未知的分析 %q (可选 %s)":                  "reports: unknown analysis %q (choose %s)",
+	"不支持的主题: %q (可选 %s)":                         "unsupported theme: %q (choose %s)",
+	"不支持的颜色模式: %q (可选 %s)":                       "unsupported color mode: %q (choose %s)",
+	"不支持的表格样式: %q (可选 %s)":                       "unsupported table style: %q (choose %s)",
+	"无效的键字段: %q (可选 date/product/region)":        "invalid key field: %q (choose date/product/region)",
+	"匹配键不能为空":                                    "the match key must not be empty",
+	"不支持的语言: %q (可选 %s)":                         "unsupported language: %q (choose %s)",
+	"金额格式需要包含一个 %%s: %q":                         "the currency format must contain one %%s: %q",
+	"不支持的导出格式: %q (可选 %s)":                       "unsupported export format: %q (choose %s)",
+	"需要 -base 或 -periods 之一":                     "one of -base or -periods is required",
+	"需要两个时间段，用逗号分隔: %q":                          "two comma-separated periods are required: %q",
+	"log.file: 无法打开日志文件: %w":                     "log.file: cannot open log file: %w",
+}
@@ -0,0 +1,160 @@
+// Package i18n 提供报表文本的翻译和与语言区域相关的数字格式。
+//
+// 源代码中的文本直接使用中文书写，并作为消息目录的键: T("总销售额") 在 en-US 下返回
+// "Total sales"，目录中没有的文本原样返回。当前语言区域是进程级的设置，
+// 由命令行在启动时通过 Set 选定，之后各分析和报表共用。
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultTag 默认语言区域
+const DefaultTag = "zh-CN"
+
+// Locale 一个语言区域: 消息目录和数字格式规则
+type Locale struct {
+	Tag      string // BCP 47 标签，如 "zh-CN"
+	Decimal  string // 小数点
+	Group    string // 千位分隔符
+	Currency string // 金额格式，%s 为带千位分隔的数字部分，如 "¥ %s"
+	// ExcelCurrency Excel中金额单元格的数字格式
+	ExcelCurrency string
+	// Messages 中文原文到译文的映射，为nil表示直接使用原文
+	Messages map[string]string
+}
+
+var locales = map[string]*Locale{}
+
+// register 注册语言区域，由各语言的文件在 init 中调用
+func register(l *Locale) {
+	locales[l.Tag] = l
+}
+
+var current atomic.Pointer[Locale]
+
+// Current 返回当前语言区域
+func Current() *Locale {
+	if l := current.Load(); l != nil {
+		return l
+	}
+	return locales[DefaultTag]
+}
+
+// Tags 返回支持的语言区域标签
+func Tags() []string {
+	tags := make([]string, 0, len(locales))
+	for tag := range locales {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Lookup 按标签查找语言区域。
+// 接受 "en-US"、"en_US.UTF-8"、"en" 等写法，只有语言部分匹配时使用该语言的默认区域。
+func Lookup(tag string) (*Locale, error) {
+	normalized := normalize(tag)
+	if l, ok := locales[normalized]; ok {
+		return l, nil
+	}
+	language, _, _ := strings.Cut(normalized, "-")
+	for _, candidate := range Tags() {
+		if strings.EqualFold(strings.SplitN(candidate, "-", 2)[0], language) {
+			return locales[candidate], nil
+		}
+	}
+	return nil, Errorf("不支持的语言: %q (可选 %s)", tag, strings.Join(Tags(), "/"))
+}
+
+// Set 设置当前语言区域
+func Set(tag string) error {
+	l, err := Lookup(tag)
+	if err != nil {
+		return err
+	}
+	current.Store(l)
+	return nil
+}
+
+// SetCurrency 替换当前语言区域的金额格式，如 "US$%s"、"%s 元"，其余规则不变。
+// 应在 Set 之后调用；Excel中的金额格式随之调整。
+func SetCurrency(format string) error {
+	prefix, suffix, found := strings.Cut(format, "%s")
+	if !found || strings.Contains(suffix, "%s") {
+		return Errorf("金额格式需要包含一个 %%s: %q", format)
+	}
+	l := *Current()
+	l.Currency = format
+	l.ExcelCurrency = excelLiteral(prefix) + "#,##0.00" + excelLiteral(suffix)
+	current.Store(&l)
+	return nil
+}
+
+// excelLiteral 将文本转为Excel数字格式中的字面量
+func excelLiteral(s string) string {
+	if s == "" {
+		return ""
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// Detect 确定要使用的语言: 优先使用显式指定的 tag (如 --lang 参数)，
+// 其次依次读取 LC_ALL、LC_MESSAGES、LANG 环境变量；都不可用时返回 DefaultTag。
+// 环境变量中不支持的语言 (如 "C"、"POSIX") 会被忽略，显式指定的则原样返回以便报错。
+func Detect(tag string) string {
+	if tag != "" {
+		return tag
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		if l, err := Lookup(value); err == nil {
+			return l.Tag
+		}
+		// LC_ALL 等设置了但不支持时，不再回退到优先级更低的变量
+		break
+	}
+	return DefaultTag
+}
+
+// normalize 将 "en_us.UTF-8"、"zh_CN@latin" 等写法统一为 "en-US"
+func normalize(tag string) string {
+	tag, _, _ = strings.Cut(tag, ".")
+	tag, _, _ = strings.Cut(tag, "@")
+	language, region, found := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	if !found {
+		return strings.ToLower(language)
+	}
+	return strings.ToLower(language) + "-" + strings.ToUpper(region)
+}
+
+// T 翻译一条文本
+func T(msg string) string {
+	return Current().T(msg)
+}
+
+// Sprintf 翻译格式字符串后再格式化
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Errorf 翻译格式字符串后再创建错误，可以用 %w 包装其他错误。
+// 会显示给用户的错误都应使用它，库内部的一致性检查等错误不必翻译。
+func Errorf(format string, args ...any) error {
+	return fmt.Errorf(T(format), args...)
+}
+
+// T 翻译一条文本，目录中没有时返回原文
+func (l *Locale) T(msg string) string {
+	if translated, ok := l.Messages[msg]; ok {
+		return translated
+	}
+	return msg
+}
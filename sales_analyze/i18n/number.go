@@ -0,0 +1,70 @@
+package i18n
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Number 按语言区域格式化数字: 保留 decimals 位小数并加千位分隔符
+func Number(v float64, decimals int) string {
+	return Current().Number(v, decimals)
+}
+
+// Int 格式化整数
+func Int(n int) string {
+	return Current().Number(float64(n), 0)
+}
+
+// Money 格式化金额，货币符号的位置由语言区域决定
+func Money(v float64) string {
+	return Current().Money(v)
+}
+
+// Percent 格式化百分比，57.1 表示 57.1%
+func Percent(p float64) string {
+	return Current().Number(p, 1) + "%"
+}
+
+// Signed 为格式化后的数字加上正负号，0 不加符号
+func Signed(v float64, formatted string) string {
+	if v > 0 {
+		return "+" + formatted
+	}
+	return formatted
+}
+
+// Number 按语言区域格式化数字
+func (l *Locale) Number(v float64, decimals int) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	s := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(s, ".")
+
+	var b strings.Builder
+	if v < 0 && strings.Trim(s, "0.") != "" {
+		b.WriteString("-")
+	}
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(l.Group)
+		}
+		b.WriteRune(digit)
+	}
+	if fraction != "" {
+		b.WriteString(l.Decimal)
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+// Money 格式化金额，负数的负号放在货币符号之前
+func (l *Locale) Money(v float64) string {
+	s := l.Number(v, 2)
+	if rest, negative := strings.CutPrefix(s, "-"); negative {
+		return "-" + strings.Replace(l.Currency, "%s", rest, 1)
+	}
+	return strings.Replace(l.Currency, "%s", s, 1)
+}
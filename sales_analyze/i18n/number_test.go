@@ -0,0 +1,87 @@
+package i18n
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNumber(t *testing.T) {
+	zh, _ := Lookup("zh-CN")
+	// 小数点和千位分隔符与内置语言区域不同，确认格式化使用了语言区域的设置
+	de := &Locale{Tag: "de-DE", Decimal: ",", Group: ".", Currency: "%s €"}
+
+	tests := []struct {
+		locale   *Locale
+		v        float64
+		decimals int
+		want     string
+	}{
+		{zh, 0, 0, "0"},
+		{zh, 0, 2, "0.00"},
+		{zh, math.Copysign(0, -1), 2, "0.00"},
+		{zh, -0.001, 2, "0.00"},
+		{zh, -0.005, 1, "0.0"},
+		{zh, 999, 0, "999"},
+		{zh, 1000, 0, "1,000"},
+		{zh, -1000, 0, "-1,000"},
+		{zh, 1234567.891, 2, "1,234,567.89"},
+		{zh, -1234567.891, 2, "-1,234,567.89"},
+		{zh, 999999.996, 2, "1,000,000.00"},
+		{zh, 1e15, 0, "1,000,000,000,000,000"},
+		{zh, -12.5, 1, "-12.5"},
+		{zh, math.NaN(), 2, "NaN"},
+		{zh, math.Inf(-1), 2, "-Inf"},
+		{de, 1234567.891, 2, "1.234.567,89"},
+		{de, -1000, 0, "-1.000"},
+	}
+	for _, tt := range tests {
+		if got := tt.locale.Number(tt.v, tt.decimals); got != tt.want {
+			t.Errorf("%s Number(%v, %d) = %q，应为 %q", tt.locale.Tag, tt.v, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestMoney(t *testing.T) {
+	zh, _ := Lookup("zh-CN")
+	en, _ := Lookup("en-US")
+	de := &Locale{Tag: "de-DE", Decimal: ",", Group: ".", Currency: "%s €"}
+
+	tests := []struct {
+		locale *Locale
+		v      float64
+		want   string
+	}{
+		{zh, 0, "¥ 0.00"},
+		{zh, 5998, "¥ 5,998.00"},
+		{zh, -5998, "-¥ 5,998.00"},
+		{zh, -0.001, "¥ 0.00"},
+		{zh, 12345678901.234, "¥ 12,345,678,901.23"},
+		{en, -1234.5, "-CN¥1,234.50"},
+		{de, -1234.5, "-1.234,50 €"},
+		{de, 0, "0,00 €"},
+	}
+	for _, tt := range tests {
+		if got := tt.locale.Money(tt.v); got != tt.want {
+			t.Errorf("%s Money(%v) = %q，应为 %q", tt.locale.Tag, tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		p    float64
+		want string
+	}{
+		{0, "0.0%"},
+		{57.14, "57.1%"},
+		{-12.35, "-12.3%"},
+		{-0.04, "0.0%"},
+		{100, "100.0%"},
+		{123456.7, "123,456.7%"},
+	}
+	for _, tt := range tests {
+		if got := Percent(tt.p); got != tt.want {
+			t.Errorf("Percent(%v) = %q，应为 %q", tt.p, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package i18n
+
+// 源代码中的文本即为简体中文，不需要消息目录
+func init() {
+	register(&Locale{
+		Tag:           "zh-CN",
+		Decimal:       ".",
+		Group:         ",",
+		Currency:      "¥ %s",
+		ExcelCurrency: "¥#,##0.00",
+	})
+}
@@ -0,0 +1,104 @@
+// Package logging 命令行工具的运行日志，基于 log/slog。
+//
+// 日志按级别 (debug/info/warn/error) 过滤，输出格式有三种:
+//
+//   - pretty: 面向终端，每条日志一行，只显示消息本身并按级别着色，与报表的配色一致；
+//   - text: slog 的 key=value 格式，带时间、级别和全部属性；
+//   - json: 每行一个JSON对象，便于日志系统收集。
+//
+// 报表只写到标准输出，日志写到标准错误或日志文件，重定向其中一个不会混入另一个。
+// 消息应是完整的、已翻译的句子；数量、文件名、错误等同时作为属性记录，供 text 和 json 格式的使用者处理。
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"sales-analyzer/i18n"
+	"sales-analyzer/style"
+)
+
+// Formats 支持的日志格式
+var Formats = []string{"pretty", "text", "json"}
+
+// Levels 日志级别，由低到高
+var Levels = []string{"debug", "info", "warn", "error"}
+
+// ParseLevel 解析日志级别名称
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, i18n.Errorf("未知的日志级别: %q (可选 %s)", name, strings.Join(Levels, "/"))
+}
+
+// New 创建按 format 写到 w 的日志，低于 level 的日志被丢弃
+func New(w io.Writer, format string, level slog.Level) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(format) {
+	case "pretty", "":
+		return slog.New(NewPrettyHandler(w, opts)), nil
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, i18n.Errorf("未知的日志格式: %q (可选 %s)", format, strings.Join(Formats, "/"))
+}
+
+// PrettyHandler pretty 格式的 slog.Handler。属性不显示，消息按级别着色:
+// debug 为次要信息、info 为一般提示、warn 为警告、error 为错误；w 不是终端时不着色。
+type PrettyHandler struct {
+	w     io.Writer
+	level slog.Leveler
+	mu    *sync.Mutex
+}
+
+// NewPrettyHandler 创建写到 w 的 PrettyHandler，opts 中只使用 Level
+func NewPrettyHandler(w io.Writer, opts *slog.HandlerOptions) *PrettyHandler {
+	h := &PrettyHandler{w: w, level: slog.LevelInfo, mu: new(sync.Mutex)}
+	if opts != nil && opts.Level != nil {
+		h.level = opts.Level
+	}
+	return h
+}
+
+// Enabled 实现 slog.Handler
+func (h *PrettyHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle 实现 slog.Handler。末尾的换行放在控制序列之外。
+func (h *PrettyHandler) Handle(_ context.Context, r slog.Record) error {
+	role := style.Info
+	switch {
+	case r.Level >= slog.LevelError:
+		role = style.Error
+	case r.Level >= slog.LevelWarn:
+		role = style.Warning
+	case r.Level < slog.LevelInfo:
+		role = style.Muted
+	}
+	line := style.For(h.w).Paint(role, strings.TrimSuffix(r.Message, "\n")) + "\n"
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, line)
+	return err
+}
+
+// WithAttrs 实现 slog.Handler，pretty 格式不显示属性
+func (h *PrettyHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+// WithGroup 实现 slog.Handler
+func (h *PrettyHandler) WithGroup(string) slog.Handler { return h }
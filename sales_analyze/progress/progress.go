@@ -0,0 +1,338 @@
+// Package progress 长时间读取和分析的进度显示。
+//
+// 一个 Progress 管理多个同时进行的任务 (如并行读取的多个文件)，每个任务一个 Bar，
+// 记录已处理的字节数和行数，并由此计算吞吐量和预计剩余时间。
+// 输出是终端时定期原地重绘全部进度条，结束后清除；不是终端 (重定向到文件、CI日志，
+// 或日志不是 pretty 格式) 时改为定期写一条 info 日志，不输出控制序列。
+//
+//	p := progress.New(os.Stderr)
+//	defer p.Stop()
+//	bar := p.Add("sales.csv", info.Size())
+//	rowErrs, err := sales.Scan(bar.Reader(file), func(line int, record sales.Record) {
+//		bar.AddRows(1)
+//	})
+//	bar.Done()
+//
+// 在 Delay 之内完成的任务不显示任何进度，小文件不会闪烁。
+// 所有方法都可以在 nil 的 *Progress 和 *Bar 上调用，此时什么也不做，调用方不必判断是否启用了进度。
+package progress
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sales-analyzer/i18n"
+	"sales-analyzer/style"
+	"sales-analyzer/table"
+)
+
+const (
+	// Delay 开始显示进度前等待的时间
+	Delay = time.Second
+	// LogInterval 不是终端时写进度日志的间隔
+	LogInterval = 10 * time.Second
+	// redrawInterval 终端中重绘进度条的间隔
+	redrawInterval = 200 * time.Millisecond
+	// barWidth 进度条的字符数
+	barWidth = 24
+)
+
+// Progress 一组同时进行的任务的进度
+type Progress struct {
+	w io.Writer // 原地重绘的终端，为nil时写日志
+
+	mu     sync.Mutex
+	bars   []*Bar
+	lines  int  // 终端上已绘制的行数
+	logged bool // 是否写过进度日志
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New 创建进度显示并开始定期刷新。w 是终端时原地重绘；否则 (包括 w 为nil) 定期写 info 日志。
+// 使用完后必须调用 Stop。
+func New(w io.Writer) *Progress {
+	p := &Progress{stop: make(chan struct{}), done: make(chan struct{})}
+	if w != nil && style.IsTerminal(w) {
+		p.w = w
+	}
+	go p.run()
+	return p
+}
+
+// Add 添加一个按字节计算进度的任务，total 为总字节数，未知时为0 (只显示已处理的量和吞吐量)
+func (p *Progress) Add(name string, total int64) *Bar {
+	return p.add(&Bar{name: name, total: total})
+}
+
+// AddRows 添加一个按行计算进度的任务，total 为总行数
+func (p *Progress) AddRows(name string, total int) *Bar {
+	return p.add(&Bar{name: name, total: int64(total), byRows: true})
+}
+
+func (p *Progress) add(b *Bar) *Bar {
+	if p == nil {
+		return nil
+	}
+	b.start = time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bars = append(p.bars, b)
+	return b
+}
+
+// Stop 停止刷新。终端中清除进度条；写过进度日志时为每个任务写一条完成日志。
+func (p *Progress) Stop() {
+	if p == nil {
+		return
+	}
+	select {
+	case <-p.stop:
+		return
+	default:
+	}
+	close(p.stop)
+	<-p.done
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.w != nil && p.lines > 0 {
+		// 回到第一行并清除到屏幕末尾
+		fmt.Fprintf(p.w, "\x1b[%dA\r\x1b[J", p.lines)
+		p.lines = 0
+	}
+	if p.w == nil && p.logged {
+		for _, b := range p.bars {
+			b.log()
+		}
+	}
+}
+
+func (p *Progress) run() {
+	defer close(p.done)
+	wait, interval := Delay, redrawInterval
+	if p.w == nil {
+		wait, interval = LogInterval, LogInterval
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-timer.C:
+		}
+		p.update()
+		timer.Reset(interval)
+	}
+}
+
+// update 重绘全部进度条，或为未完成的任务各写一条日志
+func (p *Progress) update() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.w == nil {
+		for _, b := range p.bars {
+			if !b.finished() {
+				b.log()
+				p.logged = true
+			}
+		}
+		return
+	}
+
+	nameWidth := 0
+	for _, b := range p.bars {
+		nameWidth = max(nameWidth, table.Width(b.name))
+	}
+	maxWidth := table.TerminalWidth(p.w) - 1
+	styler := style.For(p.w)
+
+	var out strings.Builder
+	if p.lines > 0 {
+		fmt.Fprintf(&out, "\x1b[%dA", p.lines)
+	}
+	for _, b := range p.bars {
+		icon := "⏳"
+		if b.finished() {
+			icon = "✅"
+		}
+		head := icon + " " + table.Pad(b.name, nameWidth, table.AlignLeft) + " "
+		tail := "  " + strings.Join(b.parts(), "  ")
+		var graphic string
+		if ratio, ok := b.ratio(); ok {
+			graphic = table.Bar(ratio, barWidth)
+		}
+		line := head + styler.Paint(style.Info, graphic) + tail
+		if plain := head + graphic + tail; maxWidth > 0 && table.Width(plain) > maxWidth {
+			line = table.Truncate(plain, maxWidth)
+		}
+		out.WriteString("\r\x1b[2K" + line + "\n")
+	}
+	p.lines = len(p.bars)
+	io.WriteString(p.w, out.String())
+}
+
+// Bar 一个任务的进度，可以由多个协程同时更新
+type Bar struct {
+	name   string
+	total  int64
+	byRows bool // 按行而不是按字节计算进度
+	start  time.Time
+
+	bytes atomic.Int64
+	rows  atomic.Int64
+	end   atomic.Int64 // 完成时间 (UnixNano)，未完成时为0
+}
+
+// Reader 包装 r，读取的字节计入进度
+func (b *Bar) Reader(r io.Reader) io.Reader {
+	if b == nil {
+		return r
+	}
+	return &reader{r, b}
+}
+
+type reader struct {
+	r   io.Reader
+	bar *Bar
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.bar.bytes.Add(int64(n))
+	return n, err
+}
+
+// AddRows 记录又处理了 n 行，可以直接用作 sales.Options.Progress
+func (b *Bar) AddRows(n int) {
+	if b != nil {
+		b.rows.Add(int64(n))
+	}
+}
+
+// Done 标记任务完成，之后的速度按完成时计算
+func (b *Bar) Done() {
+	if b != nil {
+		b.end.CompareAndSwap(0, time.Now().UnixNano())
+	}
+}
+
+func (b *Bar) finished() bool {
+	return b.end.Load() != 0
+}
+
+// elapsed 从开始到现在或到完成的时间
+func (b *Bar) elapsed() time.Duration {
+	if end := b.end.Load(); end != 0 {
+		return time.Unix(0, end).Sub(b.start)
+	}
+	return time.Since(b.start)
+}
+
+// done 进度的计量值: 字节数或行数
+func (b *Bar) done() int64 {
+	if b.byRows {
+		return b.rows.Load()
+	}
+	return b.bytes.Load()
+}
+
+// ratio 完成的比例，总量未知时 ok 为false
+func (b *Bar) ratio() (ratio float64, ok bool) {
+	if b.finished() {
+		return 1, true
+	}
+	if b.total <= 0 {
+		return 0, false
+	}
+	return math.Min(float64(b.done())/float64(b.total), 1), true
+}
+
+// eta 按平均速度估计的剩余时间，无法估计时 ok 为false
+func (b *Bar) eta() (eta time.Duration, ok bool) {
+	done, elapsed := b.done(), b.elapsed()
+	if b.finished() || b.total <= 0 || done <= 0 || elapsed <= 0 {
+		return 0, false
+	}
+	rate := float64(done) / elapsed.Seconds()
+	return time.Duration(float64(b.total-done) / rate * float64(time.Second)), true
+}
+
+// parts 进度条之后的各项文字: 百分比、已处理的量、吞吐量和剩余时间 (或用时)
+func (b *Bar) parts() []string {
+	var parts []string
+	if ratio, ok := b.ratio(); ok {
+		parts = append(parts, fmt.Sprintf("%5s", i18n.Percent(ratio*100)))
+	}
+
+	seconds := b.elapsed().Seconds()
+	rows := b.rows.Load()
+	if b.byRows {
+		done := i18n.Int(int(rows))
+		if b.total > 0 {
+			done += " / " + i18n.Int(int(b.total))
+		}
+		parts = append(parts, i18n.Sprintf("%s 行", done))
+	} else {
+		bytes := b.bytes.Load()
+		done := Bytes(bytes)
+		if b.total > 0 {
+			done += " / " + Bytes(b.total)
+		}
+		parts = append(parts, done, i18n.Sprintf("%s 行", i18n.Int(int(rows))))
+		if seconds > 0 {
+			parts = append(parts, Bytes(int64(float64(bytes)/seconds))+"/s")
+		}
+	}
+	if b.byRows && seconds > 0 {
+		parts = append(parts, i18n.Sprintf("%s 行/秒", i18n.Number(float64(rows)/seconds, 0)))
+	}
+
+	if b.finished() {
+		parts = append(parts, i18n.Sprintf("用时 %s", b.elapsed().Round(time.Millisecond)))
+	} else if eta, ok := b.eta(); ok {
+		parts = append(parts, i18n.Sprintf("剩余 %s", eta.Round(time.Second)))
+	}
+	return parts
+}
+
+// log 以 info 级别写一条进度日志
+func (b *Bar) log() {
+	icon := "⏳"
+	if b.finished() {
+		icon = "✅"
+	}
+	attrs := []any{"task", b.name, "rows", b.rows.Load(), "elapsed", b.elapsed()}
+	if !b.byRows {
+		attrs = append(attrs, "bytes", b.bytes.Load())
+	}
+	if b.total > 0 {
+		attrs = append(attrs, "total", b.total)
+	}
+	if eta, ok := b.eta(); ok {
+		attrs = append(attrs, "eta", eta)
+	}
+	slog.Info(icon+" "+b.name+": "+strings.Join(b.parts(), "  "), attrs...)
+}
+
+// Bytes 以 B/KB/MB/GB/TB 显示字节数 (按1024进位)
+func Bytes(n int64) string {
+	const units = "KMGT"
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	v, unit := float64(n)/1024, 0
+	for v >= 1024 && unit < len(units)-1 {
+		v /= 1024
+		unit++
+	}
+	return i18n.Number(v, 1) + " " + units[unit:unit+1] + "B"
+}
@@ -0,0 +1,168 @@
+package progress
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KB"},
+		{1536, "1.5 KB"},
+		{1<<20 - 1, "1,024.0 KB"},
+		{1 << 20, "1.0 MB"},
+		{5 << 30, "5.0 GB"},
+		{3 << 40, "3.0 TB"},
+		{2048 << 40, "2,048.0 TB"}, // 没有更大的单位
+	}
+	for _, tt := range tests {
+		if got := Bytes(tt.n); got != tt.want {
+			t.Errorf("Bytes(%d) = %q，应为 %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+// bar 开始于 elapsed 之前、已处理 done 字节 (byRows 时为行) 的任务
+func bar(total, done int64, byRows bool, elapsed time.Duration) *Bar {
+	b := &Bar{name: "sales.csv", total: total, byRows: byRows, start: time.Now().Add(-elapsed)}
+	if byRows {
+		b.rows.Store(done)
+	} else {
+		b.bytes.Store(done)
+	}
+	return b
+}
+
+// finish 标记任务在开始 elapsed 之后完成
+func finish(b *Bar, elapsed time.Duration) *Bar {
+	b.end.Store(b.start.Add(elapsed).UnixNano())
+	return b
+}
+
+func TestRatio(t *testing.T) {
+	tests := []struct {
+		name   string
+		bar    *Bar
+		want   float64
+		wantOK bool
+	}{
+		{"按字节", bar(200, 50, false, time.Second), 0.25, true},
+		{"按行", bar(10, 4, true, time.Second), 0.4, true},
+		{"超过总量", bar(100, 150, false, time.Second), 1, true},
+		{"总量未知", bar(0, 50, false, time.Second), 0, false},
+		{"总量未知但已完成", finish(bar(0, 50, false, time.Second), time.Second), 1, true},
+		{"未处理完就完成", finish(bar(100, 30, false, time.Second), time.Second), 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.bar.ratio()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ratio() = %v, %v，应为 %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestETA(t *testing.T) {
+	// 10秒处理了四分之一，剩余约30秒
+	eta, ok := bar(100, 25, false, 10*time.Second).eta()
+	if !ok || eta < 30*time.Second || eta > 31*time.Second {
+		t.Errorf("eta() = %s, %v，应约为 30s", eta, ok)
+	}
+
+	for name, b := range map[string]*Bar{
+		"总量未知":   bar(0, 25, false, 10*time.Second),
+		"尚未开始处理": bar(100, 0, true, 10*time.Second),
+		"已完成":    finish(bar(100, 25, false, 10*time.Second), 10*time.Second),
+	} {
+		if eta, ok := b.eta(); ok {
+			t.Errorf("%s: eta() = %s，应无法估计", name, eta)
+		}
+	}
+}
+
+func TestParts(t *testing.T) {
+	tests := []struct {
+		name string
+		bar  *Bar
+		want []string
+	}{
+		{"按字节完成", finish(withRows(bar(2048, 2048, false, 0), 10), 2*time.Second),
+			[]string{"100.0%", "2.0 KB / 2.0 KB", "10 行", "1.0 KB/s", "用时 2s"}},
+		{"按行完成", finish(bar(1000, 1000, true, 0), 4*time.Second),
+			[]string{"100.0%", "1,000 / 1,000 行", "250 行/秒", "用时 4s"}},
+		{"总量未知完成", finish(bar(0, 2000, true, 0), 2*time.Second),
+			[]string{"100.0%", "2,000 行", "1,000 行/秒", "用时 2s"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.bar.parts(); !slices.Equal(got, tt.want) {
+				t.Errorf("parts() = %q，应为 %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("进行中", func(t *testing.T) {
+		parts := bar(4096, 1024, false, 10*time.Second).parts()
+		if len(parts) != 5 || parts[0] != "25.0%" || parts[1] != "1.0 KB / 4.0 KB" || !strings.HasPrefix(parts[4], "剩余 ") {
+			t.Errorf("parts() = %q，应有百分比、已处理的量、行数、吞吐量和剩余时间", parts)
+		}
+	})
+	t.Run("总量未知", func(t *testing.T) {
+		parts := bar(0, 5120, false, 10*time.Second).parts()
+		if len(parts) != 3 || parts[0] != "5.0 KB" {
+			t.Errorf("parts() = %q，应只有已处理的量、行数和吞吐量", parts)
+		}
+	})
+}
+
+func withRows(b *Bar, rows int) *Bar {
+	b.AddRows(rows)
+	return b
+}
+
+// TestNil nil 的 *Progress 和 *Bar 上的方法什么也不做
+func TestNil(t *testing.T) {
+	var p *Progress
+	b := p.Add("sales.csv", 100)
+	if b != nil {
+		t.Fatalf("nil Progress 的 Add() = %v，应为 nil", b)
+	}
+	if rows := p.AddRows("sales.csv", 100); rows != nil {
+		t.Fatalf("nil Progress 的 AddRows() = %v，应为 nil", rows)
+	}
+	p.Stop()
+
+	r := strings.NewReader("日期,产品\n")
+	if got := b.Reader(r); got != io.Reader(r) {
+		t.Error("nil Bar 的 Reader() 应原样返回")
+	}
+	b.AddRows(1)
+	b.Done()
+}
+
+func TestReader(t *testing.T) {
+	p := New(nil)
+	b := p.Add("sales.csv", 12)
+	data, err := io.ReadAll(b.Reader(strings.NewReader("日期,产品\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := b.bytes.Load(); got != int64(len(data)) {
+		t.Errorf("读取 %d 字节后进度为 %d", len(data), got)
+	}
+	b.Done()
+	p.Stop()
+	p.Stop() // 可以重复调用
+	if ratio, _ := b.ratio(); ratio != 1 {
+		t.Errorf("完成后 ratio() = %v，应为 1", ratio)
+	}
+}
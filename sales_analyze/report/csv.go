@@ -0,0 +1,59 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// CSVReporter CSV输出。
+// 每张表格是一个数据块: 第一行为 "章节标题" 或 "章节标题 - 表格标题"，
+// 第二行为表头，之后是原始数值 (金额不带货币符号，百分比不带%)，块之间空一行。
+// 说明文字不输出。
+type CSVReporter struct{}
+
+// Render 实现 Reporter
+func (c *CSVReporter) Render(w io.Writer, r *Report) error {
+	writer := csv.NewWriter(w)
+	first := true
+
+	for _, section := range r.Sections {
+		for _, table := range section.Tables {
+			if !first {
+				writer.Write(nil)
+			}
+			first = false
+
+			title := section.Title
+			if table.Title != "" {
+				title += " - " + table.Title
+			}
+			writer.Write([]string{title})
+			writer.Write(table.Columns)
+
+			for _, row := range table.Rows {
+				record := make([]string, len(row))
+				for i, cell := range row {
+					record[i] = rawValue(cell)
+				}
+				writer.Write(record)
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// rawValue 返回单元格的原始值文本，数值不做显示格式化
+func rawValue(cell Cell) string {
+	switch v := cell.Value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,24 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCSVReport(t *testing.T) {
+	var b bytes.Buffer
+	if err := (&CSVReporter{}).Render(&b, writerReport()); err != nil {
+		t.Fatal(err)
+	}
+	want := "🛍️  产品 | 分析\n" +
+		"产品,销售额,占比,销量,变化\n" +
+		"手机|Pro,1234567.5,57.1,1200,\n" +
+		"电脑,0.1,42.9,-3,\n" +
+		"\n" +
+		"总体 - 指标\n" +
+		"指标,值\n" +
+		"订单,2\n"
+	if got := b.String(); got != want {
+		t.Errorf("CSV输出:\n%s\n应为:\n%s", got, want)
+	}
+}
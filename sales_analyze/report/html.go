@@ -0,0 +1,101 @@
+package report
+
+import (
+	"html/template"
+	"io"
+
+	"sales-analyzer/i18n"
+)
+
+// HTMLReporter 输出单个离线HTML文件。
+// 样式和图表 (内联SVG) 全部嵌在页面中，不引用任何外部CSS、JS、字体或网络资源，
+// 可以直接作为邮件附件发送。
+type HTMLReporter struct{}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"svg": func(c Chart) template.HTML {
+		// SVG 内所有文本都已转义
+		return template.HTML(SVG(c))
+	},
+	"numeric": columnNumeric,
+	"t":       i18n.T,
+	"lang": func() string {
+		return i18n.Current().Tag
+	},
+	"date": func(r *Report) string {
+		return r.GeneratedAt.Format("2006-01-02 15:04:05")
+	},
+}).Parse(htmlPage))
+
+// Render 实现 Reporter
+func (h *HTMLReporter) Render(w io.Writer, r *Report) error {
+	return htmlTemplate.Execute(w, r)
+}
+
+const htmlPage = `<!DOCTYPE html>
+<html lang="{{lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+  body { margin: 0; background: #f4f6f9; color: #1f2933;
+         font-family: -apple-system, "PingFang SC", "Microsoft YaHei", "Noto Sans CJK SC", "Segoe UI", sans-serif; }
+  main { max-width: 960px; margin: 0 auto; padding: 24px; }
+  header h1 { margin: 0 0 4px; font-size: 26px; }
+  .meta { color: #6b7785; font-size: 13px; margin-bottom: 12px; }
+  section { background: #fff; border-radius: 10px; padding: 20px 24px; margin: 18px 0;
+            box-shadow: 0 1px 3px rgba(0,0,0,.08); }
+  section h2 { margin: 0 0 14px; font-size: 20px; }
+  .kpis { display: flex; flex-wrap: wrap; gap: 12px; margin-bottom: 16px; }
+  .kpi { flex: 1 1 180px; background: #f0f4fa; border-radius: 8px; padding: 12px 16px; }
+  .kpi .label { color: #6b7785; font-size: 13px; }
+  .kpi .value { font-size: 22px; font-weight: 600; margin-top: 4px; }
+  figure { margin: 8px 0 16px; }
+  figcaption { font-size: 13px; color: #6b7785; margin-bottom: 4px; }
+  svg text { font-size: 12px; fill: #1f2933; }
+  svg .axis { fill: #6b7785; font-size: 11px; }
+  svg .grid { stroke: #e3e8ee; }
+  table { border-collapse: collapse; width: 100%; margin: 8px 0 16px; font-size: 14px; }
+  caption { text-align: left; font-weight: 600; padding: 6px 0; }
+  th, td { padding: 6px 10px; border-bottom: 1px solid #e3e8ee; text-align: left; white-space: nowrap; }
+  th { background: #f0f4fa; }
+  td.num, th.num { text-align: right; font-variant-numeric: tabular-nums; }
+  .note { margin: 4px 0; padding: 6px 12px; border-left: 4px solid #4e79a7; background: #f3f7fc; border-radius: 4px; }
+  .note.success { border-color: #59a14f; background: #f2f9f1; }
+  .note.warning { border-color: #f28e2b; background: #fdf5ec; }
+  footer { color: #6b7785; font-size: 12px; text-align: center; margin: 24px 0; }
+</style>
+</head>
+<body>
+<main>
+<header>
+  <h1>{{.Title}}</h1>
+  <div class="meta">{{if .Source}}{{t "数据来源: "}}{{.Source}} · {{end}}{{t "生成时间: "}}{{date .}}</div>
+  {{range .Notes}}<div class="note {{.Level}}">{{.Text}}</div>{{end}}
+</header>
+{{range .Sections}}
+<section id="{{.ID}}">
+  <h2>{{.Title}}</h2>
+  {{range .Intro}}<div class="note {{.Level}}">{{.Text}}</div>{{end}}
+  {{if .KPIs}}<div class="kpis">
+    {{range .KPIs}}<div class="kpi"><div class="label">{{.Label}}</div><div class="value">{{.Value}}</div></div>{{end}}
+  </div>{{end}}
+  {{range .Charts}}<figure><figcaption>{{.Title}}</figcaption>{{svg .}}</figure>{{end}}
+  {{range $table := .Tables}}
+  <table>
+    {{if .Title}}<caption>{{.Title}}</caption>{{end}}
+    <thead><tr>{{range $i, $c := .Columns}}<th{{if numeric $table $i}} class="num"{{end}}>{{$c}}</th>{{end}}</tr></thead>
+    <tbody>
+    {{range .Rows}}<tr>{{range .}}<td{{if .Kind.Numeric}} class="num"{{end}}>{{.}}</td>{{end}}</tr>
+    {{end}}</tbody>
+  </table>
+  {{end}}
+  {{range .Notes}}<div class="note {{.Level}}">{{.Text}}</div>{{end}}
+</section>
+{{end}}
+<footer>{{.Title}} · {{date .}}</footer>
+</main>
+</body>
+</html>
+`
@@ -0,0 +1,102 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTMLReport(t *testing.T) {
+	r := &Report{
+		Title:       `<script>alert("x")</script> 报表`,
+		Source:      "a&b.csv",
+		GeneratedAt: time.Date(2025, 1, 4, 9, 30, 0, 0, time.UTC),
+		Notes:       []Note{{LevelWarning, "<b>注意</b>"}},
+		Sections: []Section{{
+			ID:    "products",
+			Title: "产品",
+			Charts: []Chart{
+				{Kind: ChartBar, Title: "<i>条形</i>", Labels: []string{"<u>手机</u>", "电脑"}, Values: []float64{3, 1}, ValueKind: KindInt},
+				{Kind: ChartLine, Title: "折线", Labels: []string{"\"2025-01-01\""}, Values: []float64{5}, ValueKind: KindMoney},
+			},
+			Tables: []Table{{
+				Columns: []string{"产品", "销量"},
+				Rows:    [][]Cell{{Text("<td>手机</td>"), Int(3)}},
+			}},
+		}},
+	}
+	var b bytes.Buffer
+	if err := (&HTMLReporter{}).Render(&b, r); err != nil {
+		t.Fatal(err)
+	}
+	page := b.String()
+
+	for _, raw := range []string{"<script>", "<b>注意", "<i>条形", "<u>手机", "<td>手机", `>"2025-01-01"<`} {
+		if strings.Contains(page, raw) {
+			t.Errorf("输出中有未转义的 %q", raw)
+		}
+	}
+	for _, escaped := range []string{"&lt;script&gt;", "&lt;b&gt;注意", "&lt;u&gt;手机", "&lt;td&gt;手机", "a&amp;b.csv", "&#34;2025-01-01&#34;"} {
+		if !strings.Contains(page, escaped) {
+			t.Errorf("输出中没有转义后的 %q", escaped)
+		}
+	}
+
+	// 离线页面: 除SVG的命名空间外不出现任何URL，也没有外部样式、脚本和图片
+	if external := regexp.MustCompile(`(?i)<link|<script|\bsrc=|href=|url\(|@import`).FindAllString(page, -1); len(external) > 0 {
+		t.Errorf("输出引用了外部资源: %q", external)
+	}
+	page = strings.ReplaceAll(page, `xmlns="http://www.w3.org/2000/svg"`, "")
+	if strings.Contains(page, "http:") || strings.Contains(page, "https:") {
+		t.Error("输出中有外部URL")
+	}
+}
+
+func TestLineSVG(t *testing.T) {
+	axisLabel := regexp.MustCompile(`<text x="[\d.]+" y="\d+" text-anchor="middle" class="axis">([^<]*)</text>`)
+
+	t.Run("单个数据点", func(t *testing.T) {
+		svg := SVG(Chart{Kind: ChartLine, Labels: []string{"2025-01-01"}, Values: []float64{100}, ValueKind: KindMoney})
+		if strings.Contains(svg, "NaN") || strings.Contains(svg, "Inf") {
+			t.Errorf("坐标中有 NaN/Inf:\n%s", svg)
+		}
+		// 单个点画在绘图区的正中
+		if !strings.Contains(svg, `<circle cx="355.0"`) {
+			t.Errorf("数据点不在横轴正中:\n%s", svg)
+		}
+		if labels := axisLabel.FindAllStringSubmatch(svg, -1); len(labels) != 1 || labels[0][1] != "2025-01-01" {
+			t.Errorf("横轴标签 = %q，应只有 2025-01-01", labels)
+		}
+	})
+
+	t.Run("一个月的数据", func(t *testing.T) {
+		c := Chart{Kind: ChartLine, ValueKind: KindMoney}
+		for day := 1; day <= 31; day++ {
+			c.Labels = append(c.Labels, fmt.Sprintf("2025-01-%02d", day))
+			c.Values = append(c.Values, float64(day*100))
+		}
+		svg := SVG(c)
+		var labels []string
+		for _, m := range axisLabel.FindAllStringSubmatch(svg, -1) {
+			labels = append(labels, m[1])
+		}
+		want := []string{"2025-01-01", "2025-01-05", "2025-01-09", "2025-01-13", "2025-01-17", "2025-01-21", "2025-01-25", "2025-01-31"}
+		if fmt.Sprint(labels) != fmt.Sprint(want) {
+			t.Errorf("横轴标签 = %v，应为 %v", labels, want)
+		}
+		// 每个数据点仍有带标签的提示
+		if got := strings.Count(svg, "<circle"); got != 31 {
+			t.Errorf("数据点 %d 个，应为 31 个", got)
+		}
+	})
+
+	t.Run("全部为0", func(t *testing.T) {
+		svg := SVG(Chart{Kind: ChartLine, Labels: []string{"a", "b"}, Values: []float64{0, 0}})
+		if strings.Contains(svg, "NaN") || strings.Contains(svg, "Inf") {
+			t.Errorf("坐标中有 NaN/Inf:\n%s", svg)
+		}
+	})
+}
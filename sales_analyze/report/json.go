@@ -0,0 +1,77 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// JSONReporter JSON输出。
+// 章节带有结构化结果 (Section.Data) 时直接序列化该结果，否则输出表格的原始值。
+type JSONReporter struct {
+	Indent string
+}
+
+type jsonReport struct {
+	Title       string        `json:"title"`
+	Source      string        `json:"source,omitempty"`
+	GeneratedAt time.Time     `json:"generated_at"`
+	Notes       []Note        `json:"notes,omitempty"`
+	Sections    []jsonSection `json:"sections"`
+}
+
+type jsonSection struct {
+	ID     string      `json:"id"`
+	Title  string      `json:"title"`
+	Data   any         `json:"data,omitempty"`
+	Tables []jsonTable `json:"tables,omitempty"`
+	Notes  []Note      `json:"notes,omitempty"`
+}
+
+type jsonTable struct {
+	Title   string   `json:"title,omitempty"`
+	Columns []string `json:"columns"`
+	Rows    [][]any  `json:"rows"`
+}
+
+// Render 实现 Reporter
+func (j *JSONReporter) Render(w io.Writer, r *Report) error {
+	out := jsonReport{
+		Title:       r.Title,
+		Source:      r.Source,
+		GeneratedAt: r.GeneratedAt,
+		Notes:       r.Notes,
+		Sections:    []jsonSection{},
+	}
+
+	for _, section := range r.Sections {
+		js := jsonSection{
+			ID:    section.ID,
+			Title: section.Title,
+			Data:  section.Data,
+			Notes: append(append([]Note(nil), section.Intro...), section.Notes...),
+		}
+		if section.Data == nil {
+			for _, table := range section.Tables {
+				js.Tables = append(js.Tables, toJSONTable(table))
+			}
+		}
+		out.Sections = append(out.Sections, js)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", j.Indent)
+	return encoder.Encode(out)
+}
+
+func toJSONTable(t Table) jsonTable {
+	jt := jsonTable{Title: t.Title, Columns: t.Columns, Rows: [][]any{}}
+	for _, row := range t.Rows {
+		values := make([]any, len(row))
+		for i, cell := range row {
+			values[i] = cell.Value
+		}
+		jt.Rows = append(jt.Rows, values)
+	}
+	return jt
+}
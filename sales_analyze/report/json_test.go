@@ -0,0 +1,80 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writerReport 各种数值类型的单元格、需要转义的文本，以及带结构化结果的章节
+func writerReport() *Report {
+	return &Report{
+		Title: "📊 销售报表",
+		Sections: []Section{
+			{
+				ID:    "products",
+				Title: "🛍️  产品 | 分析",
+				Tables: []Table{{
+					Columns: []string{"产品", "销售额", "占比", "销量", "变化"},
+					Rows: [][]Cell{
+						{Text("手机|Pro"), Money(1234567.5), Percent(57.1), Int(1200), Empty()},
+						{Text("电脑"), Money(0.1), Percent(42.9), Int(-3), Empty()},
+					},
+				}},
+				Notes: []Note{{Level: LevelInfo, Text: "手机|Pro 领先"}},
+			},
+			{
+				ID:     "overall",
+				Title:  "总体",
+				Tables: []Table{{Title: "指标", Columns: []string{"指标", "值"}, Rows: [][]Cell{{Text("订单"), Int(2)}}}},
+				Data:   map[string]int{"orders": 2},
+			},
+		},
+	}
+}
+
+func TestJSONReport(t *testing.T) {
+	var b bytes.Buffer
+	if err := (&JSONReporter{Indent: "  "}).Render(&b, writerReport()); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(b.String(), "¥") || strings.Contains(b.String(), "%") {
+		t.Errorf("JSON中应为原始数值，不应有货币符号或百分号:\n%s", b.String())
+	}
+
+	var got struct {
+		Sections []struct {
+			ID     string
+			Data   map[string]int
+			Tables []struct {
+				Columns []string
+				Rows    [][]any
+			}
+			Notes []Note
+		}
+	}
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Sections) != 2 || len(got.Sections[0].Tables) != 1 {
+		t.Fatalf("章节 = %+v，应为两个章节，第一个有一张表格", got.Sections)
+	}
+	wantRows := [][]any{
+		{"手机|Pro", 1234567.5, 57.1, float64(1200), nil},
+		{"电脑", 0.1, 42.9, float64(-3), nil},
+	}
+	if rows := got.Sections[0].Tables[0].Rows; !reflect.DeepEqual(rows, wantRows) {
+		t.Errorf("行 = %v，应为 %v", rows, wantRows)
+	}
+	if notes := got.Sections[0].Notes; len(notes) != 1 || notes[0].Text != "手机|Pro 领先" {
+		t.Errorf("说明 = %+v", notes)
+	}
+
+	// 带结构化结果的章节输出结果本身，不再输出表格
+	overall := got.Sections[1]
+	if overall.ID != "overall" || overall.Data["orders"] != 2 || overall.Tables != nil {
+		t.Errorf("带结构化结果的章节 = %+v，应只有 data", overall)
+	}
+}
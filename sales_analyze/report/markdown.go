@@ -0,0 +1,103 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"sales-analyzer/i18n"
+)
+
+// MarkdownReporter Markdown输出，适合贴到wiki页面
+type MarkdownReporter struct{}
+
+// Render 实现 Reporter
+func (m *MarkdownReporter) Render(w io.Writer, r *Report) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", r.Title)
+	var meta []string
+	if r.Source != "" {
+		meta = append(meta, i18n.T("数据来源: ")+"`"+r.Source+"`")
+	}
+	if !r.GeneratedAt.IsZero() {
+		meta = append(meta, i18n.T("生成时间: ")+r.GeneratedAt.Format("2006-01-02 15:04:05"))
+	}
+	if len(meta) > 0 {
+		fmt.Fprintf(&b, "> %s\n\n", strings.Join(meta, " · "))
+	}
+	markdownNotes(&b, r.Notes)
+
+	for _, section := range r.Sections {
+		fmt.Fprintf(&b, "## %s\n\n", section.Title)
+		markdownNotes(&b, section.Intro)
+
+		for _, table := range section.Tables {
+			if table.Title != "" {
+				fmt.Fprintf(&b, "**%s**\n\n", escapeMarkdown(table.Title))
+			}
+			markdownTable(&b, table)
+		}
+
+		markdownNotes(&b, section.Notes)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func markdownNotes(b *strings.Builder, notes []Note) {
+	if len(notes) == 0 {
+		return
+	}
+	for _, note := range notes {
+		fmt.Fprintf(b, "- %s\n", escapeMarkdown(note.Text))
+	}
+	b.WriteString("\n")
+}
+
+// markdownTable 输出管道表格，数值列右对齐
+func markdownTable(b *strings.Builder, t Table) {
+	b.WriteString("|")
+	for _, column := range t.Columns {
+		fmt.Fprintf(b, " %s |", escapeMarkdown(column))
+	}
+	b.WriteString("\n|")
+
+	for i := range t.Columns {
+		if columnNumeric(t, i) {
+			b.WriteString(" ---: |")
+		} else {
+			b.WriteString(" --- |")
+		}
+	}
+	b.WriteString("\n")
+
+	for _, row := range t.Rows {
+		b.WriteString("|")
+		for _, cell := range row {
+			fmt.Fprintf(b, " %s |", escapeMarkdown(cell.String()))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+}
+
+// columnNumeric 第 i 列的所有非空单元格是否都是数值
+func columnNumeric(t Table, i int) bool {
+	numeric := false
+	for _, row := range t.Rows {
+		if i >= len(row) || row[i].Value == nil {
+			continue
+		}
+		if !row[i].Kind.Numeric() {
+			return false
+		}
+		numeric = true
+	}
+	return numeric
+}
+
+func escapeMarkdown(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
@@ -0,0 +1,35 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMarkdownReport(t *testing.T) {
+	var b bytes.Buffer
+	if err := (&MarkdownReporter{}).Render(&b, writerReport()); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"# 📊 销售报表\n\n",
+		"## 🛍️  产品 | 分析\n\n",
+		"| 产品 | 销售额 | 占比 | 销量 | 变化 |\n| --- | ---: | ---: | ---: | --- |\n",
+		"| 手机\\|Pro | ¥ 1,234,567.50 | 57.1% | 1,200 | - |\n",
+		"- 手机\\|Pro 领先\n",
+		"**指标**\n\n| 指标 | 值 |\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Markdown输出中没有 %q:\n%s", want, got)
+		}
+	}
+	// 表格的每一行都有相同的列数，单元格中的 | 不会多分出一列
+	for _, line := range strings.Split(got, "\n") {
+		if strings.HasPrefix(line, "| 手机") || strings.HasPrefix(line, "| 产品") {
+			if n := strings.Count(line, "|") - strings.Count(line, "\\|"); n != 6 {
+				t.Errorf("%q 有 %d 个分隔符，应为 6", line, n)
+			}
+		}
+	}
+}
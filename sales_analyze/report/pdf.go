@@ -0,0 +1,484 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-pdf/fpdf"
+
+	"sales-analyzer/i18n"
+)
+
+// FontEnv 指定PDF中文字体文件的环境变量
+const FontEnv = "SALES_PDF_FONT"
+
+// cjkFontPaths 各平台常见的、包含中文字形的TrueType字体。
+// PDF输出会嵌入字体子集，只支持单个 .ttf 文件 (不支持 .ttc 字体集和CFF轮廓的 .otf)。
+var cjkFontPaths = map[string][]string{
+	"linux": {
+		"/usr/share/fonts/truetype/droid/DroidSansFallbackFull.ttf",
+		"/usr/share/fonts/truetype/wqy/wqy-microhei.ttf",
+		"/usr/share/fonts/truetype/arphic/ukai.ttf",
+		"/usr/share/fonts/google-droid/DroidSansFallback.ttf",
+	},
+	"darwin": {
+		"/Library/Fonts/Arial Unicode.ttf",
+		"/System/Library/Fonts/Supplemental/Arial Unicode.ttf",
+	},
+	"windows": {
+		`C:\Windows\Fonts\simhei.ttf`,
+		`C:\Windows\Fonts\simkai.ttf`,
+		`C:\Windows\Fonts\simfang.ttf`,
+	},
+}
+
+// FindFont 查找用于PDF的中文字体: 优先使用 SALES_PDF_FONT 环境变量，其次是系统常见字体
+func FindFont() (string, error) {
+	if path := os.Getenv(FontEnv); path != "" {
+		return path, nil
+	}
+	for _, path := range cjkFontPaths[runtime.GOOS] {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", i18n.Errorf("未找到中文字体，请用 -pdf-font 或 %s 环境变量指定TrueType字体文件 (.ttf)", FontEnv)
+}
+
+// PDF页面布局，单位毫米
+const (
+	pdfMargin     = 15.0
+	pdfFooter     = 12.0 // 页脚区域高度
+	pdfLineHeight = 6.0
+	pdfRowHeight  = 7.0
+	pdfFont       = "cjk"
+)
+
+// PDFReporter 输出分页的PDF报表: 封面、各章节的指标、图表和表格，每页底部有数据来源和生成时间。
+// 完全在本地用Go生成，嵌入中文字体子集，不依赖浏览器。
+type PDFReporter struct {
+	// FontPath TrueType字体文件，为空时由 FindFont 查找
+	FontPath string
+}
+
+// Render 实现 Reporter
+func (p *PDFReporter) Render(w io.Writer, r *Report) error {
+	fontPath := p.FontPath
+	if fontPath == "" {
+		var err error
+		if fontPath, err = FindFont(); err != nil {
+			return err
+		}
+	}
+	font, err := os.ReadFile(fontPath)
+	if err != nil {
+		return i18n.Errorf("无法读取字体文件: %w", err)
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin+pdfFooter)
+	// 没有单独的粗体字体文件，粗体也使用同一字体
+	pdf.AddUTF8FontFromBytes(pdfFont, "", font)
+	pdf.AddUTF8FontFromBytes(pdfFont, "B", font)
+	pdf.SetTitle(plainText(r.Title), true)
+	pdf.SetCreator("sales-analyzer", true)
+	pdf.SetCreationDate(r.GeneratedAt)
+	pdf.AliasNbPages("")
+
+	doc := &pdfDocument{pdf: pdf}
+	pdf.SetFooterFunc(func() { doc.footer(r) })
+
+	doc.titlePage(r)
+	for _, section := range r.Sections {
+		doc.section(section)
+	}
+
+	if err := pdf.Error(); err != nil {
+		return i18n.Errorf("生成PDF失败: %w", err)
+	}
+	return pdf.Output(w)
+}
+
+type pdfDocument struct {
+	pdf *fpdf.Fpdf
+}
+
+// contentWidth 页面去掉左右边距后的宽度
+func (d *pdfDocument) contentWidth() float64 {
+	width, _ := d.pdf.GetPageSize()
+	return width - 2*pdfMargin
+}
+
+// bottom 正文区域底部的纵坐标，其下是页脚
+func (d *pdfDocument) bottom() float64 {
+	_, pageHeight := d.pdf.GetPageSize()
+	return pageHeight - pdfMargin - pdfFooter
+}
+
+// ensure 当前页剩余空间不足 height 时换页
+func (d *pdfDocument) ensure(height float64) {
+	if d.pdf.GetY()+height > d.bottom() {
+		d.pdf.AddPage()
+	}
+}
+
+func (d *pdfDocument) font(size float64, bold bool) {
+	style := ""
+	if bold {
+		style = "B"
+	}
+	d.pdf.SetFont(pdfFont, style, size)
+}
+
+func (d *pdfDocument) titlePage(r *Report) {
+	d.pdf.AddPage()
+	_, pageHeight := d.pdf.GetPageSize()
+	d.pdf.SetY(pageHeight / 3)
+
+	d.font(26, true)
+	d.pdf.CellFormat(0, 14, plainText(r.Title), "", 1, "C", false, 0, "")
+	d.pdf.Ln(6)
+
+	d.font(12, false)
+	d.pdf.SetTextColor(107, 119, 133)
+	if r.Source != "" {
+		d.pdf.CellFormat(0, 8, i18n.T("数据来源: ")+r.Source, "", 1, "C", false, 0, "")
+	}
+	d.pdf.CellFormat(0, 8, i18n.T("生成时间: ")+r.GeneratedAt.Format("2006-01-02 15:04:05"), "", 1, "C", false, 0, "")
+	d.pdf.SetTextColor(0, 0, 0)
+	d.pdf.Ln(10)
+
+	for _, note := range r.Notes {
+		d.note(note)
+	}
+
+	// 目录
+	d.pdf.Ln(10)
+	d.font(12, false)
+	for i := range r.Sections {
+		d.pdf.CellFormat(0, 7, fmt.Sprintf("%d. %s", i+1, plainText(r.Sections[i].Title)), "", 1, "C", false, 0, "")
+	}
+}
+
+func (d *pdfDocument) footer(r *Report) {
+	if d.pdf.PageNo() == 1 {
+		return
+	}
+	_, pageHeight := d.pdf.GetPageSize()
+	d.pdf.SetY(pageHeight - pdfMargin - pdfFooter/2)
+	d.font(8, false)
+	d.pdf.SetTextColor(107, 119, 133)
+
+	text := i18n.T("生成时间: ") + r.GeneratedAt.Format("2006-01-02 15:04:05")
+	if r.Source != "" {
+		text = i18n.T("数据来源: ") + r.Source + "  ·  " + text
+	}
+	d.pdf.CellFormat(d.contentWidth()/2, 5, text, "T", 0, "L", false, 0, "")
+	d.pdf.CellFormat(d.contentWidth()/2, 5, i18n.Sprintf("第 %d / {nb} 页", d.pdf.PageNo()), "T", 0, "R", false, 0, "")
+	d.pdf.SetTextColor(0, 0, 0)
+}
+
+// section 每个章节从新的一页开始
+func (d *pdfDocument) section(s Section) {
+	d.pdf.AddPage()
+	d.font(18, true)
+	d.pdf.CellFormat(0, 10, plainText(s.Title), "", 1, "L", false, 0, "")
+	d.pdf.Ln(2)
+
+	for _, note := range s.Intro {
+		d.note(note)
+	}
+	if len(s.KPIs) > 0 {
+		d.kpis(s.KPIs)
+	}
+	for _, chart := range s.Charts {
+		d.chart(chart)
+	}
+	for _, t := range s.Tables {
+		d.table(t)
+	}
+	if len(s.Notes) > 0 {
+		d.pdf.Ln(2)
+		for _, note := range s.Notes {
+			d.note(note)
+		}
+	}
+}
+
+// note 带级别颜色竖条的说明文字
+func (d *pdfDocument) note(n Note) {
+	d.font(10, false)
+	text := plainText(n.Text)
+	lines := d.pdf.SplitText(text, d.contentWidth()-6)
+	height := float64(len(lines)) * pdfLineHeight
+	d.ensure(height)
+
+	x, y := d.pdf.GetX(), d.pdf.GetY()
+	d.setFill(noteColors[n.Level])
+	d.pdf.Rect(x, y+0.5, 1.2, height-1, "F")
+	d.pdf.SetX(x + 4)
+	d.pdf.MultiCell(d.contentWidth()-4, pdfLineHeight, text, "", "L", false)
+	d.pdf.Ln(1)
+}
+
+var noteColors = map[Level]string{
+	LevelInfo:    "#4e79a7",
+	LevelSuccess: "#59a14f",
+	LevelWarning: "#f28e2b",
+}
+
+// kpis 一行指标卡片
+func (d *pdfDocument) kpis(kpis []KPI) {
+	const height, gap = 18.0, 4.0
+	d.ensure(height + 4)
+	width := (d.contentWidth() - gap*float64(len(kpis)-1)) / float64(len(kpis))
+	x, y := d.pdf.GetX(), d.pdf.GetY()
+
+	for i, kpi := range kpis {
+		left := x + float64(i)*(width+gap)
+		d.setFill("#f0f4fa")
+		d.pdf.Rect(left, y, width, height, "F")
+
+		d.font(8, false)
+		d.pdf.SetTextColor(107, 119, 133)
+		d.pdf.SetXY(left+3, y+2)
+		d.pdf.CellFormat(width-6, 5, kpi.Label, "", 0, "L", false, 0, "")
+
+		d.font(12, true)
+		d.pdf.SetTextColor(0, 0, 0)
+		d.pdf.SetXY(left+3, y+8)
+		d.pdf.CellFormat(width-6, 8, kpi.Value.String(), "", 0, "L", false, 0, "")
+	}
+	d.pdf.SetXY(x, y+height+6)
+}
+
+func (d *pdfDocument) chart(c Chart) {
+	if len(c.Values) == 0 {
+		return
+	}
+	if c.Kind == ChartLine {
+		d.lineChart(c)
+	} else {
+		d.barChart(c)
+	}
+	d.pdf.Ln(4)
+}
+
+// barChart 横向条形图，与HTML报表中的SVG图表布局一致。
+// 一页放不下时在新页继续，续页重复图表标题。
+func (d *pdfDocument) barChart(c Chart) {
+	const titleHeight, rowHeight, barHeight = 8.0, 8.0, 5.5
+	labelWidth, valueWidth := 30.0, 35.0
+	height := titleHeight + rowHeight*float64(len(c.Values))
+	if height > d.bottom()-pdfMargin {
+		// 比一页还高时不整体换页，只保证标题后至少有几行
+		height = titleHeight + 3*rowHeight
+	}
+	d.ensure(height)
+	d.chartTitle(c.Title)
+
+	// 标签和数值用绝对坐标绘制，自动换页会让越过页底的每个单元格各开一页，
+	// 因此绘制期间关闭，由下面按行换页
+	d.pdf.SetAutoPageBreak(false, 0)
+	defer d.pdf.SetAutoPageBreak(true, pdfMargin+pdfFooter)
+
+	barArea := d.contentWidth() - labelWidth - valueWidth
+	maxValue := maxOf(c.Values)
+	x, top := d.pdf.GetX(), d.pdf.GetY()
+
+	d.font(9, false)
+	for i, value := range c.Values {
+		if top+rowHeight > d.bottom() {
+			d.pdf.AddPage()
+			d.chartTitle(c.Title + i18n.T(" (续)"))
+			d.font(9, false)
+			top = d.pdf.GetY()
+		}
+		width := 0.0
+		if maxValue > 0 {
+			width = math.Max(0, value/maxValue*barArea)
+		}
+		d.pdf.SetXY(x, top)
+		d.pdf.CellFormat(labelWidth-3, barHeight, c.Labels[i], "", 0, "R", false, 0, "")
+		d.setFill(chartPalette[i%len(chartPalette)])
+		d.pdf.Rect(x+labelWidth, top, width, barHeight, "F")
+		d.pdf.SetXY(x+labelWidth+width+2, top)
+		d.pdf.CellFormat(valueWidth, barHeight, c.Format(i), "", 0, "L", false, 0, "")
+		top += rowHeight
+	}
+	d.pdf.SetXY(x, top)
+}
+
+// lineChart 折线图，纵轴从0开始，带网格线。横轴标签较多时只显示一部分，见 Chart.showLabel。
+func (d *pdfDocument) lineChart(c Chart) {
+	const height, left, bottom, gridNum = 60.0, 28.0, 8.0, 4
+	d.ensure(8 + height)
+	d.chartTitle(c.Title)
+
+	x, y := d.pdf.GetX(), d.pdf.GetY()
+	plotLeft := x + left
+	plotWidth := d.contentWidth() - left - 5
+	plotHeight := height - bottom
+	maxValue := niceCeil(maxOf(c.Values))
+
+	px := func(i int) float64 {
+		if len(c.Values) == 1 {
+			return plotLeft + plotWidth/2
+		}
+		return plotLeft + plotWidth*float64(i)/float64(len(c.Values)-1)
+	}
+	py := func(v float64) float64 {
+		if maxValue <= 0 {
+			return y + plotHeight
+		}
+		return y + plotHeight*(1-v/maxValue)
+	}
+
+	// 网格线和纵轴刻度
+	d.font(7, false)
+	d.pdf.SetLineWidth(0.2)
+	d.setDraw("#e3e8ee")
+	for g := 0; g <= gridNum; g++ {
+		value := maxValue * float64(g) / gridNum
+		gy := py(value)
+		d.pdf.Line(plotLeft, gy, plotLeft+plotWidth, gy)
+		d.pdf.SetXY(x, gy-2)
+		d.pdf.CellFormat(left-2, 4, Cell{Value: value, Kind: c.ValueKind}.String(), "", 0, "R", false, 0, "")
+	}
+
+	// 折线和数据点
+	d.pdf.SetLineWidth(0.6)
+	d.setDraw(chartPalette[0])
+	d.setFill(chartPalette[0])
+	for i := range c.Values {
+		if i > 0 {
+			d.pdf.Line(px(i-1), py(c.Values[i-1]), px(i), py(c.Values[i]))
+		}
+	}
+	for i, value := range c.Values {
+		d.pdf.Circle(px(i), py(value), 0.9, "F")
+		if c.showLabel(i) {
+			d.pdf.SetXY(px(i)-15, y+plotHeight+1)
+			d.pdf.CellFormat(30, 5, c.Labels[i], "", 0, "C", false, 0, "")
+		}
+	}
+
+	d.pdf.SetLineWidth(0.2)
+	d.setDraw("#000000")
+	d.pdf.SetXY(x, y+height)
+}
+
+func (d *pdfDocument) chartTitle(title string) {
+	d.font(10, true)
+	d.pdf.SetTextColor(107, 119, 133)
+	d.pdf.CellFormat(0, 7, title, "", 1, "L", false, 0, "")
+	d.pdf.SetTextColor(0, 0, 0)
+}
+
+// table 绘制表格，数值列右对齐；跨页时在新页重复表头
+func (d *pdfDocument) table(t Table) {
+	d.pdf.Ln(2)
+	if t.Title != "" {
+		d.ensure(pdfLineHeight + 2*pdfRowHeight)
+		d.font(11, true)
+		d.pdf.CellFormat(0, pdfLineHeight, plainText(t.Title), "", 1, "L", false, 0, "")
+	}
+
+	widths := d.columnWidths(t)
+	header := func() {
+		d.font(9, true)
+		d.setFill("#f0f4fa")
+		for i, column := range t.Columns {
+			d.pdf.CellFormat(widths[i], pdfRowHeight, column, "B", 0, d.align(t, i), true, 0, "")
+		}
+		d.pdf.Ln(-1)
+	}
+
+	d.ensure(2 * pdfRowHeight)
+	header()
+	d.font(9, false)
+	for _, row := range t.Rows {
+		if d.pdf.GetY()+pdfRowHeight > d.bottom() {
+			d.pdf.AddPage()
+			header()
+			d.font(9, false)
+		}
+		for i, cell := range row {
+			if i < len(widths) {
+				d.pdf.CellFormat(widths[i], pdfRowHeight, plainText(cell.String()), "B", 0, d.align(t, i), false, 0, "")
+			}
+		}
+		d.pdf.Ln(-1)
+	}
+	d.pdf.Ln(4)
+}
+
+// columnWidths 按内容宽度分配列宽，总宽超过页面时按比例缩小
+func (d *pdfDocument) columnWidths(t Table) []float64 {
+	widths := make([]float64, len(t.Columns))
+	d.font(9, true)
+	for i, column := range t.Columns {
+		widths[i] = d.pdf.GetStringWidth(column) + 4
+	}
+	d.font(9, false)
+	for _, row := range t.Rows {
+		for i, cell := range row {
+			if i < len(widths) {
+				widths[i] = math.Max(widths[i], d.pdf.GetStringWidth(plainText(cell.String()))+4)
+			}
+		}
+	}
+
+	total := 0.0
+	for _, w := range widths {
+		total += w
+	}
+	if total > d.contentWidth() {
+		scale := d.contentWidth() / total
+		for i := range widths {
+			widths[i] *= scale
+		}
+	}
+	return widths
+}
+
+func (d *pdfDocument) align(t Table, col int) string {
+	if columnNumeric(t, col) {
+		return "R"
+	}
+	return "L"
+}
+
+func (d *pdfDocument) setFill(hex string) {
+	r, g, b := parseHexColor(hex)
+	d.pdf.SetFillColor(r, g, b)
+}
+
+func (d *pdfDocument) setDraw(hex string) {
+	r, g, b := parseHexColor(hex)
+	d.pdf.SetDrawColor(r, g, b)
+}
+
+// parseHexColor 解析 "#rrggbb" 格式的颜色
+func parseHexColor(hex string) (int, int, int) {
+	v, _ := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	return int(v >> 16 & 0xff), int(v >> 8 & 0xff), int(v & 0xff)
+}
+
+// plainText 去掉emoji等符号: 常见的中文TrueType字体不含这些字形
+func plainText(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.So, r) || r == '\uFE0F' || r == '\u200D' {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.TrimSpace(s)
+}
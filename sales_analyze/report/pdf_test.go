@@ -0,0 +1,93 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// testFont 测试用的TrueType字体，没有中文字体时退而使用常见的西文字体，都没有时跳过测试
+func testFont(t *testing.T) string {
+	t.Helper()
+	if path, err := FindFont(); err == nil {
+		return path
+	}
+	for _, path := range []string{
+		"/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf",
+		"/Library/Fonts/Arial Unicode.ttf",
+		`C:\Windows\Fonts\arial.ttf`,
+	} {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	t.Skip("没有可用的TrueType字体")
+	return ""
+}
+
+// pdfPages PDF文档的页数
+func pdfPages(t *testing.T, data []byte) int {
+	t.Helper()
+	return len(regexp.MustCompile(`/Type /Page\b[^s]`).FindAll(data, -1))
+}
+
+func TestPDFBarChartPages(t *testing.T) {
+	font := testFont(t)
+	tests := []struct {
+		bars  int
+		pages int // 包括封面
+	}{
+		{5, 2},
+		{29, 2},  // 正好放满章节的第一页
+		{45, 3},  // 在第二页继续
+		{100, 5}, // 每页约31行
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.bars), func(t *testing.T) {
+			chart := Chart{Kind: ChartBar, Title: "Products", ValueKind: KindMoney}
+			for i := range tt.bars {
+				chart.Labels = append(chart.Labels, fmt.Sprintf("product-%d", i+1))
+				chart.Values = append(chart.Values, float64(1000*(tt.bars-i)))
+			}
+			r := &Report{
+				Title:       "Report",
+				GeneratedAt: time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC),
+				Sections:    []Section{{ID: "products", Title: "Products", Charts: []Chart{chart}}},
+			}
+			var b bytes.Buffer
+			if err := (&PDFReporter{FontPath: font}).Render(&b, r); err != nil {
+				t.Fatal(err)
+			}
+			if got := pdfPages(t, b.Bytes()); got != tt.pages {
+				t.Errorf("%d 个条形的图表共 %d 页，应为 %d 页", tt.bars, got, tt.pages)
+			}
+		})
+	}
+}
+
+func TestChartShowLabel(t *testing.T) {
+	tests := []struct {
+		n    int
+		want []int // 显示标签的下标
+	}{
+		{1, []int{0}},
+		{8, []int{0, 1, 2, 3, 4, 5, 6, 7}},
+		{9, []int{0, 2, 4, 6, 8}},
+		{31, []int{0, 4, 8, 12, 16, 20, 24, 30}},
+	}
+	for _, tt := range tests {
+		c := Chart{Labels: make([]string, tt.n)}
+		var got []int
+		for i := range tt.n {
+			if c.showLabel(i) {
+				got = append(got, i)
+			}
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%d 个标签时显示 %v，应为 %v", tt.n, got, tt.want)
+		}
+	}
+}
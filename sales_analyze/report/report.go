@@ -0,0 +1,231 @@
+// Package report 定义与输出格式无关的报表文档模型，
+// 以及把文档写成终端表格、JSON、CSV、Markdown、HTML 的 Reporter。
+//
+// 各项分析先得到结构化结果 (见 sales、target 等包)，再由 sections.go 中的函数
+// 转换为 Section；Reporter 只关心文档本身，不依赖具体的分析。
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"sales-analyzer/i18n"
+)
+
+// Report 一份完整的报表
+type Report struct {
+	Title       string
+	Source      string // 数据来源，通常是输入文件名
+	GeneratedAt time.Time
+	Notes       []Note // 标题下方的说明
+	Sections    []Section
+}
+
+// Section 报表中的一个分析章节
+type Section struct {
+	ID     string // 稳定的标识，如 "products"，用于JSON键和锚点
+	Title  string
+	Intro  []Note // 表格之前的说明
+	KPIs   []KPI  // 关键指标，图形化输出时显示为卡片
+	Charts []Chart
+	Tables []Table
+	Notes  []Note // 表格之后的洞察和提示
+	// Data 章节对应的结构化分析结果，JSON输出时原样序列化
+	Data any
+}
+
+// Table 一张二维表格
+type Table struct {
+	Title   string
+	Columns []string
+	Rows    [][]Cell
+}
+
+// KPI 一个关键指标
+type KPI struct {
+	Label string
+	Value Cell
+}
+
+// ChartKind 图表类型
+type ChartKind string
+
+const (
+	ChartBar  ChartKind = "bar"  // 横向条形图，适合分类对比
+	ChartLine ChartKind = "line" // 折线图，适合时间序列
+)
+
+// Chart 一组用于绘图的数据，Labels 与 Values 一一对应
+type Chart struct {
+	Kind      ChartKind
+	Title     string
+	Labels    []string
+	Values    []float64
+	ValueKind Kind // 数值的显示格式
+}
+
+// Format 按 ValueKind 格式化第 i 个数值
+func (c Chart) Format(i int) string {
+	return Cell{Value: c.Values[i], Kind: c.ValueKind}.String()
+}
+
+// showLabel 折线图横轴上是否显示第 i 个标签。与看板的折线图一样最多显示约8个，
+// 最后一个总是显示，离它不足一个间隔的标签省略，避免重叠。
+func (c Chart) showLabel(i int) bool {
+	n := len(c.Labels)
+	every := (n + 7) / 8
+	return i == n-1 || (i%every == 0 && n-1-i >= every)
+}
+
+// Level 说明文字的级别，决定终端中的颜色
+type Level string
+
+const (
+	LevelInfo    Level = "info"
+	LevelSuccess Level = "success"
+	LevelWarning Level = "warning"
+)
+
+// Note 一行说明文字
+type Note struct {
+	Level Level  `json:"level"`
+	Text  string `json:"text"`
+}
+
+// Info 创建普通说明，format 按当前语言翻译
+func Info(format string, args ...any) Note {
+	return Note{LevelInfo, i18n.Sprintf(format, args...)}
+}
+
+// Success 创建正面的说明，format 按当前语言翻译
+func Success(format string, args ...any) Note {
+	return Note{LevelSuccess, i18n.Sprintf(format, args...)}
+}
+
+// Warning 创建需要关注的说明，format 按当前语言翻译
+func Warning(format string, args ...any) Note {
+	return Note{LevelWarning, i18n.Sprintf(format, args...)}
+}
+
+// Kind 单元格的数值类型，决定显示格式
+type Kind int
+
+const (
+	KindText        Kind = iota
+	KindInt              // 整数
+	KindMoney            // 金额
+	KindPercent          // 百分比
+	KindChange           // 带符号的百分比变化
+	KindMoneyChange      // 带符号的金额变化
+	KindIntChange        // 带符号的整数变化
+)
+
+// Numeric 是否为数值类型，数值列通常右对齐
+func (k Kind) Numeric() bool {
+	return k != KindText
+}
+
+// Cell 表格中的一个单元格。
+// Value 保留原始值 (string、int 或 float64)，供CSV、JSON等机器可读格式使用；
+// String 按 Kind 格式化为显示文本。Value 为nil表示没有数据。
+type Cell struct {
+	Value any
+	Kind  Kind
+	Unit  string // 显示时附加的单位，如 "件"
+}
+
+// Text 文本单元格
+func Text(s string) Cell {
+	return Cell{Value: s, Kind: KindText}
+}
+
+// Int 整数单元格，可选单位
+func Int(n int, unit ...string) Cell {
+	return Cell{Value: n, Kind: KindInt, Unit: firstOf(unit)}
+}
+
+// Money 金额单元格
+func Money(amount float64) Cell {
+	return Cell{Value: amount, Kind: KindMoney}
+}
+
+// Percent 百分比单元格，57.1 表示 57.1%
+func Percent(p float64) Cell {
+	return Cell{Value: p, Kind: KindPercent}
+}
+
+// Change 百分比变化单元格，rate 为nil时显示为 "-"
+func Change(rate *float64) Cell {
+	if rate == nil {
+		return Cell{Kind: KindChange}
+	}
+	return Cell{Value: *rate, Kind: KindChange}
+}
+
+// MoneyChange 金额变化单元格
+func MoneyChange(amount float64) Cell {
+	return Cell{Value: amount, Kind: KindMoneyChange}
+}
+
+// IntChange 整数变化单元格
+func IntChange(n int) Cell {
+	return Cell{Value: n, Kind: KindIntChange}
+}
+
+// Empty 没有数据的单元格，显示为 "-"
+func Empty() Cell {
+	return Cell{}
+}
+
+// String 返回单元格的显示文本，数字按当前语言区域格式化 (千位分隔符、货币符号等)
+func (c Cell) String() string {
+	if c.Value == nil {
+		return "-"
+	}
+
+	var s string
+	v, numeric := number(c.Value)
+	switch {
+	case !numeric || c.Kind == KindText:
+		s = fmt.Sprint(c.Value)
+	case c.Kind == KindMoney:
+		s = i18n.Money(v)
+	case c.Kind == KindPercent:
+		s = i18n.Percent(v)
+	case c.Kind == KindChange:
+		s = i18n.Signed(v, i18n.Percent(v))
+	case c.Kind == KindMoneyChange:
+		s = i18n.Signed(v, i18n.Number(v, 2))
+	case c.Kind == KindIntChange:
+		s = i18n.Signed(v, i18n.Number(v, 0))
+	default:
+		s = i18n.Number(v, 0)
+	}
+
+	if c.Unit != "" {
+		s += " " + c.Unit
+	}
+	return s
+}
+
+// number 将 int、float64 等数值转换为 float64
+func number(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func firstOf(values []string) string {
+	if len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+package report
+
+import (
+	"io"
+	"strings"
+
+	"sales-analyzer/i18n"
+)
+
+// Reporter 将报表写成某种输出格式
+type Reporter interface {
+	Render(w io.Writer, r *Report) error
+}
+
+// Formats 支持的输出格式
+var Formats = []string{"table", "json", "csv", "markdown", "html", "xlsx", "pdf"}
+
+// New 按格式名称创建 Reporter
+func New(format string) (Reporter, error) {
+	switch strings.ToLower(format) {
+	case "table", "text", "":
+		return &TextReporter{}, nil
+	case "json":
+		return &JSONReporter{Indent: "  "}, nil
+	case "csv":
+		return &CSVReporter{}, nil
+	case "markdown", "md":
+		return &MarkdownReporter{}, nil
+	case "html":
+		return &HTMLReporter{}, nil
+	case "xlsx", "excel":
+		return &XLSXReporter{}, nil
+	case "pdf":
+		return &PDFReporter{}, nil
+	default:
+		return nil, i18n.Errorf("不支持的输出格式: %q (可选 %s)", format, strings.Join(Formats, "/"))
+	}
+}
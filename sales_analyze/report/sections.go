@@ -0,0 +1,473 @@
+package report
+
+import (
+	"strings"
+
+	"sales-analyzer/diff"
+	"sales-analyzer/i18n"
+	"sales-analyzer/sales"
+	"sales-analyzer/scenario"
+	"sales-analyzer/target"
+)
+
+// columns 翻译表头
+func columns(names ...string) []string {
+	translated := make([]string, len(names))
+	for i, name := range names {
+		translated[i] = i18n.T(name)
+	}
+	return translated
+}
+
+// OverallSection 总体销售分析
+func OverallSection(o sales.Overall) Section {
+	return Section{
+		ID:    "overall",
+		Title: i18n.T("📈 总体销售分析"),
+		KPIs: []KPI{
+			{i18n.T("总销售额"), Money(o.TotalAmount)},
+			{i18n.T("总销量"), Int(o.TotalQuantity, i18n.T("件"))},
+			{i18n.T("平均订单金额"), Money(o.AvgAmount)},
+			{i18n.T("订单数量"), Int(o.Orders, i18n.T("笔"))},
+		},
+		Tables: []Table{{
+			Columns: columns("指标", "数值"),
+			Rows: [][]Cell{
+				{Text(i18n.T("总销售额")), Money(o.TotalAmount)},
+				{Text(i18n.T("总销量")), Int(o.TotalQuantity, i18n.T("件"))},
+				{Text(i18n.T("平均订单金额")), Money(o.AvgAmount)},
+				{Text(i18n.T("订单数量")), Int(o.Orders, i18n.T("笔"))},
+			},
+		}},
+		Data: o,
+	}
+}
+
+// ProductSection 产品销售分析
+func ProductSection(products []sales.ProductSummary) Section {
+	table := Table{Columns: columns("产品", "销量", "销售额", "平均订单", "订单数")}
+	chart := Chart{Kind: ChartBar, Title: i18n.T("各产品销售额"), ValueKind: KindMoney}
+	for _, product := range products {
+		chart.Labels = append(chart.Labels, product.Product)
+		chart.Values = append(chart.Values, product.TotalAmount)
+		table.Rows = append(table.Rows, []Cell{
+			Text(product.Product),
+			Int(product.TotalQty),
+			Money(product.TotalAmount),
+			Money(product.AvgAmount),
+			Int(product.RecordCount),
+		})
+	}
+
+	section := Section{
+		ID:     "products",
+		Title:  i18n.T("🛍️  产品销售分析"),
+		Charts: []Chart{chart},
+		Tables: []Table{table},
+		Data:   products,
+	}
+
+	// 显示最佳产品
+	if len(products) > 0 {
+		section.Notes = append(section.Notes,
+			Success("🏆 最佳销售产品: %s (%s)", products[0].Product, Money(products[0].TotalAmount)))
+	}
+	return section
+}
+
+// RegionSection 地区销售分析
+func RegionSection(regions []sales.RegionSummary) Section {
+	table := Table{Columns: columns("地区", "销量", "销售额", "订单数", "市场占比")}
+	chart := Chart{Kind: ChartBar, Title: i18n.T("各地区销售额"), ValueKind: KindMoney}
+	for _, region := range regions {
+		chart.Labels = append(chart.Labels, region.Region)
+		chart.Values = append(chart.Values, region.TotalAmount)
+		table.Rows = append(table.Rows, []Cell{
+			Text(region.Region),
+			Int(region.TotalQty),
+			Money(region.TotalAmount),
+			Int(region.RecordCount),
+			Percent(region.MarketShare),
+		})
+	}
+
+	section := Section{
+		ID:     "regions",
+		Title:  i18n.T("🗺️  地区销售分析"),
+		Charts: []Chart{chart},
+		Tables: []Table{table},
+		Data:   regions,
+	}
+
+	// 显示最佳地区
+	if len(regions) > 0 {
+		section.Notes = append(section.Notes,
+			Success("🏆 最佳销售地区: %s (%s)", regions[0].Region, Money(regions[0].TotalAmount)))
+	}
+	return section
+}
+
+// DateSection 日期销售分析
+func DateSection(trend sales.DateTrend) Section {
+	table := Table{Columns: columns("日期", "销量", "销售额", "日增长率")}
+	chart := Chart{Kind: ChartLine, Title: i18n.T("每日销售额"), ValueKind: KindMoney}
+	for _, day := range trend.Days {
+		chart.Labels = append(chart.Labels, day.Date)
+		chart.Values = append(chart.Values, day.TotalAmount)
+		table.Rows = append(table.Rows, []Cell{
+			Text(day.Date),
+			Int(day.TotalQty),
+			Money(day.TotalAmount),
+			Change(day.GrowthRate),
+		})
+	}
+
+	section := Section{
+		ID:     "dates",
+		Title:  i18n.T("📅 日期销售分析"),
+		Charts: []Chart{chart},
+		Tables: []Table{table},
+		Data:   trend,
+	}
+
+	// 趋势分析
+	section.Notes = append(section.Notes, Info("📊 趋势分析:"))
+	if trend.TotalGrowth != nil {
+		if *trend.TotalGrowth > 0 {
+			section.Notes = append(section.Notes, Success("📈 整体增长: %s", Change(trend.TotalGrowth)))
+		} else {
+			section.Notes = append(section.Notes, Warning("📉 整体变化: %s", Change(trend.TotalGrowth)))
+		}
+	}
+	if trend.BestDay != nil {
+		section.Notes = append(section.Notes,
+			Success("🏆 最佳销售日: %s (%s)", trend.BestDay.Date, Money(trend.BestDay.TotalAmount)),
+			Warning("📉 最低销售日: %s (%s)", trend.WorstDay.Date, Money(trend.WorstDay.TotalAmount)),
+		)
+	}
+	return section
+}
+
+// DropNotes 日销售额较前一日下降超过 threshold (%) 的日期，threshold 不大于0时不提示
+func DropNotes(trend sales.DateTrend, threshold float64) []Note {
+	if threshold <= 0 {
+		return nil
+	}
+	var notes []Note
+	for _, day := range trend.Days {
+		if day.GrowthRate != nil && -*day.GrowthRate > threshold {
+			notes = append(notes, Warning("⚠️  %s 销售额较前一日下降 %s，超过 %s 的提示阈值",
+				day.Date, Percent(-*day.GrowthRate), Percent(threshold)))
+		}
+	}
+	return notes
+}
+
+// SketchSection 近似统计分析
+func SketchSection(summary sales.SketchSummary) Section {
+	section := Section{
+		ID:    "sketches",
+		Title: i18n.T("🔬 近似统计分析"),
+		Data:  summary,
+	}
+
+	if len(summary.Distinct) > 0 {
+		table := Table{Columns: columns("维度", "近似去重数", "相对误差")}
+		for _, d := range summary.Distinct {
+			table.Rows = append(table.Rows, []Cell{
+				Text(distinctName(d)),
+				Int(int(d.Count)),
+				Text("±" + Percent(d.RelativeError*100).String()),
+			})
+		}
+		section.Tables = append(section.Tables, table)
+	}
+
+	if len(summary.Amount) > 0 {
+		table := Table{Columns: columns("订单金额分布", "P50", "P90", "P99", "秩误差")}
+		for _, q := range summary.Amount {
+			table.Rows = append(table.Rows, []Cell{
+				Text(quantileName(q)),
+				Money(q.P50),
+				Money(q.P90),
+				Money(q.P99),
+				Text("±" + Percent(q.RankError*100).String()),
+			})
+		}
+		section.Tables = append(section.Tables, table)
+		section.Notes = append(section.Notes,
+			Info("📦 订单销量中位数: %.0f 件 (P90: %.0f 件)", summary.QuantityMedian, summary.QuantityP90))
+	}
+	if len(section.Tables) == 0 {
+		section.Notes = append(section.Notes, Info("没有符合条件的数据"))
+	}
+
+	return section
+}
+
+// distinctName 去重计数维度的显示名称
+func distinctName(d sales.DistinctCount) string {
+	switch d.Dimension {
+	case sales.DimensionProduct:
+		return i18n.T("产品")
+	case sales.DimensionRegion:
+		return i18n.T("地区")
+	case sales.DimensionDate:
+		return i18n.T("销售日")
+	case sales.DimensionRegionProduct:
+		return i18n.Sprintf("%s 在售产品", d.Region)
+	}
+	return d.Dimension
+}
+
+// quantileName 一组订单金额分位数的显示名称
+func quantileName(q sales.QuantileSummary) string {
+	switch q.Dimension {
+	case sales.DimensionAll:
+		return i18n.T("全部订单")
+	case sales.DimensionProduct:
+		return i18n.Sprintf("产品 %s", q.Product)
+	case sales.DimensionRegion:
+		return i18n.Sprintf("地区 %s", q.Region)
+	}
+	return q.Dimension
+}
+
+// TargetSection 目标达成分析
+func TargetSection(r target.Report) Section {
+	section := Section{
+		ID:    "targets",
+		Title: i18n.T("🎯 目标达成分析"),
+		Intro: []Note{Info("截止日期: %s", r.AsOf)},
+		Data:  r,
+	}
+
+	names := columns("月份", "地区", "目标", "实际", "达成率", "差距", "预计完成", "预计达成率", "状态")
+	regions := Table{Columns: names}
+	for _, region := range r.Regions {
+		regions.Rows = append(regions.Rows, attainmentRow(region.Region, region))
+	}
+
+	names = append([]string{}, names...)
+	names[1] = i18n.T("地区/产品")
+	details := Table{Columns: names}
+	for _, detail := range r.Details {
+		details.Rows = append(details.Rows, attainmentRow(detail.Region+"/"+detail.Product, detail))
+	}
+	section.Tables = []Table{regions, details}
+
+	// 突出显示进度落后的地区
+	for _, region := range r.Regions {
+		if region.BehindPace {
+			section.Notes = append(section.Notes, Warning("⚠️  %s %s 进度落后: 预计完成 %s，距目标还差 %s",
+				region.Month, region.Region, Money(region.Projection), Money(region.Target-region.Projection)))
+		}
+	}
+	if len(section.Notes) == 0 {
+		section.Notes = append(section.Notes, Success("✅ 所有地区均按计划推进"))
+	}
+	return section
+}
+
+// attainmentRow 将目标对比结果格式化为表格行
+func attainmentRow(name string, a target.Attainment) []Cell {
+	status := i18n.T("✅ 正常")
+	if a.BehindPace {
+		status = i18n.T("⚠️ 落后")
+	} else if a.ElapsedDays == 0 {
+		status = "-"
+	}
+
+	return []Cell{
+		Text(a.Month),
+		Text(name),
+		Money(a.Target),
+		Money(a.Actual),
+		Percent(a.Attainment),
+		Money(a.Gap),
+		Money(a.Projection),
+		Percent(a.ProjectedAttainment),
+		Text(status),
+	}
+}
+
+// ScenarioResult 情景模拟的结构化结果
+type ScenarioResult struct {
+	Scenario   *scenario.Scenario `json:"scenario"`
+	Affected   int                `json:"affected"`
+	Comparison sales.Comparison   `json:"comparison"`
+}
+
+// ScenarioSection 情景模拟，基准与情景并排对比
+func ScenarioSection(r ScenarioResult) Section {
+	sc, c := r.Scenario, r.Comparison
+	section := Section{
+		ID:    "scenario",
+		Title: i18n.Sprintf("🔮 情景模拟: %s", sc.Name),
+		Data:  r,
+	}
+	if sc.Description != "" {
+		section.Intro = append(section.Intro, Info("%s", sc.Description))
+	}
+	section.Intro = append(section.Intro, Info("共 %d 项调整，影响 %d 条记录", len(sc.Adjustments), r.Affected))
+
+	overall := c.Overall
+	rate := overall.AmountChangeRate()
+	section.Tables = append(section.Tables, Table{
+		Columns: columns("指标", "基准", "情景", "变化", "变化率"),
+		Rows: [][]Cell{
+			{Text(i18n.T("总销售额")), Money(overall.Base.Amount), Money(overall.Other.Amount),
+				MoneyChange(overall.AmountChange()), Change(&rate)},
+			{Text(i18n.T("总销量")), Int(overall.Base.Quantity, i18n.T("件")), Int(overall.Other.Quantity, i18n.T("件")),
+				IntChange(overall.QuantityChange()), Empty()},
+		},
+	})
+
+	for _, group := range []struct {
+		title  string
+		deltas []sales.Delta
+	}{
+		{"产品", c.Products},
+		{"地区", c.Regions},
+		{"日期", c.Dates},
+	} {
+		table := Table{Columns: columns(group.title, "基准销量", "情景销量", "基准销售额", "情景销售额", "变化率")}
+		for _, d := range group.deltas {
+			rate := d.AmountChangeRate()
+			table.Rows = append(table.Rows, []Cell{
+				Text(d.Name),
+				Int(d.Base.Quantity),
+				Int(d.Other.Quantity),
+				Money(d.Base.Amount),
+				Money(d.Other.Amount),
+				Change(&rate),
+			})
+		}
+		section.Tables = append(section.Tables, table)
+	}
+
+	if overall.AmountChange() >= 0 {
+		section.Notes = append(section.Notes, Success("📈 情景下总销售额变化: %s", Change(&rate)))
+	} else {
+		section.Notes = append(section.Notes, Warning("📉 情景下总销售额变化: %s", Change(&rate)))
+	}
+	return section
+}
+
+// DiffFieldNames 对比报告中字段的显示名称
+var DiffFieldNames = map[string]string{
+	diff.FieldDate:     "日期",
+	diff.FieldProduct:  "产品",
+	diff.FieldRegion:   "地区",
+	diff.FieldQuantity: "销量",
+	diff.FieldAmount:   "销售额",
+}
+
+// DiffResult 数据差异分析的结构化结果
+type DiffResult struct {
+	Old    string           `json:"old"`
+	New    string           `json:"new"`
+	Diff   *diff.Result     `json:"diff"`
+	Effect sales.Comparison `json:"effect"`
+}
+
+// DiffSection 数据差异分析
+func DiffSection(r DiffResult) Section {
+	result, effect := r.Diff, r.Effect
+
+	var keyNames []string
+	for _, field := range result.Key {
+		keyNames = append(keyNames, i18n.T(DiffFieldNames[field]))
+	}
+
+	section := Section{
+		ID:    "diff",
+		Title: i18n.T("🔍 数据差异分析"),
+		Intro: []Note{
+			Info("原数据: %s  →  新数据: %s", r.Old, r.New),
+			Info("匹配键: %s", strings.Join(keyNames, "+")),
+		},
+		Data: r,
+	}
+
+	section.Tables = append(section.Tables, Table{
+		Columns: columns("类型", "记录数"),
+		Rows: [][]Cell{
+			{Text(i18n.T("➕ 新增")), Int(len(result.Added))},
+			{Text(i18n.T("➖ 删除")), Int(len(result.Removed))},
+			{Text(i18n.T("✏️ 修改")), Int(len(result.Modified))},
+			{Text(i18n.T("未变化")), Int(result.Unchanged)},
+		},
+	})
+
+	if !result.Changed() {
+		section.Notes = append(section.Notes, Success("✅ 两份数据没有差异"))
+		return section
+	}
+
+	recordTable := func(title string, records []sales.Record) Table {
+		table := Table{Title: title, Columns: columns("日期", "产品", "销量", "销售额", "地区")}
+		for _, record := range records {
+			table.Rows = append(table.Rows, []Cell{
+				Text(record.Date),
+				Text(record.Product),
+				Int(record.Quantity),
+				Money(record.Amount),
+				Text(record.Region),
+			})
+		}
+		return table
+	}
+
+	if len(result.Added) > 0 {
+		section.Tables = append(section.Tables, recordTable(i18n.T("➕ 新增记录"), result.Added))
+	}
+	if len(result.Removed) > 0 {
+		section.Tables = append(section.Tables, recordTable(i18n.T("➖ 删除记录"), result.Removed))
+	}
+	if len(result.Modified) > 0 {
+		table := Table{Title: i18n.T("✏️  修改记录"), Columns: columns("键", "字段", "原值", "新值")}
+		for _, m := range result.Modified {
+			for _, change := range m.Changes {
+				table.Rows = append(table.Rows, []Cell{
+					Text(m.Key), Text(i18n.T(DiffFieldNames[change.Field])), Text(change.Old), Text(change.New),
+				})
+			}
+		}
+		section.Tables = append(section.Tables, table)
+	}
+
+	// 差异对各项汇总的影响
+	for _, group := range []struct {
+		title  string
+		deltas []sales.Delta
+	}{
+		{"范围", []sales.Delta{effect.Overall}},
+		{"产品", effect.Products},
+		{"地区", effect.Regions},
+	} {
+		table := Table{Columns: columns(group.title, "原销量", "新销量", "原销售额", "新销售额", "变化", "变化率")}
+		if group.title == "范围" {
+			table.Title = i18n.T("📊 对汇总的影响")
+		}
+		for _, d := range group.deltas {
+			rate := d.AmountChangeRate()
+			name := d.Name
+			if name == "" {
+				name = i18n.T("总体")
+			}
+			table.Rows = append(table.Rows, []Cell{
+				Text(name),
+				Int(d.Base.Quantity),
+				Int(d.Other.Quantity),
+				Money(d.Base.Amount),
+				Money(d.Other.Amount),
+				MoneyChange(d.AmountChange()),
+				Change(&rate),
+			})
+		}
+		section.Tables = append(section.Tables, table)
+	}
+
+	return section
+}
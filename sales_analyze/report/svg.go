@@ -0,0 +1,141 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+)
+
+// 图表配色，条形图按顺序循环使用
+var chartPalette = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7"}
+
+// SVG 将图表绘制为内联SVG，不依赖任何外部资源
+func SVG(c Chart) string {
+	if len(c.Values) == 0 {
+		return ""
+	}
+	if c.Kind == ChartLine {
+		return lineSVG(c)
+	}
+	return barSVG(c)
+}
+
+// barSVG 横向条形图: 左侧标签，右侧条形和数值
+func barSVG(c Chart) string {
+	const (
+		width      = 640
+		labelWidth = 120
+		valueWidth = 130
+		rowHeight  = 30
+		barHeight  = 20
+	)
+	height := rowHeight*len(c.Values) + 10
+	maxValue := maxOf(c.Values)
+	barArea := float64(width - labelWidth - valueWidth)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="100%%" role="img" aria-label="%s">`,
+		width, height, html.EscapeString(c.Title))
+	for i, value := range c.Values {
+		y := 5 + i*rowHeight
+		barWidth := 0.0
+		if maxValue > 0 {
+			barWidth = math.Max(0, value/maxValue*barArea)
+		}
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end" class="label">%s</text>`,
+			labelWidth-10, y+barHeight-5, html.EscapeString(c.Labels[i]))
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%.1f" height="%d" rx="3" fill="%s"/>`,
+			labelWidth, y, barWidth, barHeight, chartPalette[i%len(chartPalette)])
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" class="value">%s</text>`,
+			float64(labelWidth)+barWidth+6, y+barHeight-5, html.EscapeString(c.Format(i)))
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// lineSVG 折线图: 横轴为标签 (通常是日期)，纵轴从0开始，带4条网格线。
+// 每个数据点都有带标签的提示，横轴标签较多时只显示一部分，见 Chart.showLabel。
+func lineSVG(c Chart) string {
+	const (
+		width   = 640
+		height  = 260
+		left    = 90
+		right   = 20
+		top     = 20
+		bottom  = 40
+		gridNum = 4
+	)
+	plotWidth := float64(width - left - right)
+	plotHeight := float64(height - top - bottom)
+	maxValue := niceCeil(maxOf(c.Values))
+
+	x := func(i int) float64 {
+		if len(c.Values) == 1 {
+			return left + plotWidth/2
+		}
+		return left + plotWidth*float64(i)/float64(len(c.Values)-1)
+	}
+	y := func(v float64) float64 {
+		if maxValue <= 0 {
+			return top + plotHeight
+		}
+		return top + plotHeight*(1-v/maxValue)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="100%%" role="img" aria-label="%s">`,
+		width, height, html.EscapeString(c.Title))
+
+	// 网格线和纵轴刻度
+	for g := 0; g <= gridNum; g++ {
+		value := maxValue * float64(g) / gridNum
+		gy := y(value)
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" class="grid"/>`, left, gy, width-right, gy)
+		tick := Cell{Value: value, Kind: c.ValueKind}.String()
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end" class="axis">%s</text>`, left-8, gy+4, html.EscapeString(tick))
+	}
+
+	// 折线
+	var points []string
+	for i, value := range c.Values {
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x(i), y(value)))
+	}
+	fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2.5"/>`,
+		strings.Join(points, " "), chartPalette[0])
+
+	// 数据点、数值和横轴标签
+	for i, value := range c.Values {
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="4" fill="%s"><title>%s: %s</title></circle>`,
+			x(i), y(value), chartPalette[0], html.EscapeString(c.Labels[i]), html.EscapeString(c.Format(i)))
+		if c.showLabel(i) {
+			fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle" class="axis">%s</text>`,
+				x(i), height-bottom+18, html.EscapeString(c.Labels[i]))
+		}
+	}
+
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+func maxOf(values []float64) float64 {
+	m := 0.0
+	for _, v := range values {
+		m = math.Max(m, v)
+	}
+	return m
+}
+
+// niceCeil 将坐标轴上限取整到 1、2、5 乘以10的幂，使刻度易读
+func niceCeil(v float64) float64 {
+	if v <= 0 {
+		return 0
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, step := range []float64{1, 2, 5, 10} {
+		if v <= step*magnitude {
+			return step * magnitude
+		}
+	}
+	return 10 * magnitude
+}
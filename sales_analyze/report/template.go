@@ -0,0 +1,238 @@
+package report
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"sales-analyzer/i18n"
+	"sales-analyzer/sales"
+	"sales-analyzer/table"
+	"sales-analyzer/target"
+)
+
+// TemplateReporter 按用户提供的模板文件输出报表，各团队不需要修改程序就能定制报表布局。
+//
+// 扩展名为 .html 或 .htm 的文件用 html/template 解析，输出时自动转义；
+// 其他文件用 text/template 解析，适合 Markdown、纯文本邮件等格式。
+// 模板的数据见 TemplateData，可用的函数见 TemplateFuncs。
+type TemplateReporter struct {
+	Path string
+	tmpl interface {
+		Execute(w io.Writer, data any) error
+	}
+}
+
+// NewTemplate 读取并解析模板文件，模板有语法错误时立即返回错误
+func NewTemplate(path string) (*TemplateReporter, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, i18n.Errorf("无法读取模板: %w", err)
+	}
+
+	t := &TemplateReporter{Path: path}
+	name := filepath.Base(path)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		t.tmpl, err = htmltemplate.New(name).Funcs(TemplateFuncs).Parse(string(text))
+	default:
+		t.tmpl, err = template.New(name).Funcs(TemplateFuncs).Parse(string(text))
+	}
+	if err != nil {
+		return nil, i18n.Errorf("模板语法错误: %w", err)
+	}
+	return t, nil
+}
+
+// Render 实现 Reporter。模板先完整执行到内存中，出错时不会留下半份输出。
+func (t *TemplateReporter) Render(w io.Writer, r *Report) error {
+	var b bytes.Buffer
+	if err := t.tmpl.Execute(&b, NewTemplateData(r)); err != nil {
+		return i18n.Errorf("执行模板 %s 失败: %w", t.Path, err)
+	}
+	_, err := b.WriteTo(w)
+	return err
+}
+
+// TemplateData 模板中 "." 的值。
+//
+// 内嵌的 Report 提供 .Title、.Source、.GeneratedAt、.Notes 和按顺序排列的 .Sections，
+// 每个章节的表格、图表和说明都已按当前语言翻译、格式化，适合原样输出；
+// 其余字段是各项分析的结构化结果，适合自行排版，对应的分析没有运行时为nil。
+type TemplateData struct {
+	*Report
+	Lang string // 报表语言，如 "zh-CN"
+
+	Overall  *sales.Overall         // 总体指标
+	Products []sales.ProductSummary // 各产品汇总，按销售额降序
+	Regions  []sales.RegionSummary  // 各地区汇总，按销售额降序
+	Dates    *sales.DateTrend       // 每日销售额和趋势
+	Sketches *sales.SketchSummary   // 近似统计 (-distinct-error / -quantile-error)
+	Targets  *target.Report         // 目标达成 (-targets)
+	Scenario *ScenarioResult        // 情景模拟 (-scenario)
+	Diff     *DiffResult            // 数据差异 (diff 命令)
+}
+
+// NewTemplateData 从报表中取出各章节的结构化结果
+func NewTemplateData(r *Report) *TemplateData {
+	d := &TemplateData{Report: r, Lang: i18n.Current().Tag}
+	for _, section := range r.Sections {
+		switch data := section.Data.(type) {
+		case sales.Overall:
+			d.Overall = &data
+		case []sales.ProductSummary:
+			d.Products = data
+		case []sales.RegionSummary:
+			d.Regions = data
+		case sales.DateTrend:
+			d.Dates = &data
+		case sales.SketchSummary:
+			d.Sketches = &data
+		case target.Report:
+			d.Targets = &data
+		case ScenarioResult:
+			d.Scenario = &data
+		case DiffResult:
+			d.Diff = &data
+		}
+	}
+	return d
+}
+
+// Section 按ID查找章节 (如 "products")，不存在时返回nil，可用于 {{with .Section "products"}}
+func (d *TemplateData) Section(id string) *Section {
+	for i := range d.Sections {
+		if d.Sections[i].ID == id {
+			return &d.Sections[i]
+		}
+	}
+	return nil
+}
+
+// TemplateFuncs 模板中可用的函数。数字按当前语言区域格式化，参数可以是 int 或 float64。
+//
+//	money 1050000           → ¥ 1,050,000.00
+//	number 1234.5 1         → 1,234.5 (第二个参数为小数位数，默认0)
+//	percent 41.4            → 41.4%   (参数是百分数，41.4 表示 41.4%)
+//	change .GrowthRate      → +12.5%  (带符号；参数可以是 *float64，nil 显示为 "-")
+//	date .GeneratedAt       → 2025-01-04 (time.Time 或 sales.DateLayout 格式的字符串，可选第二个参数指定布局)
+//	t "总销售额"            → 按当前语言翻译
+//	emoji "trophy"          → 🏆 (名称见 Emojis)
+//	noemoji .Title          → 去掉emoji，适合不支持emoji的邮件或系统
+//	pad "华东" 6            → 按显示宽度补齐到6列 (中文占2列)，padLeft 右对齐
+//	table (index .Tables 0) → 将章节中的 Table 绘制为对齐的文本表格
+//	svg (index .Charts 0)   → 将章节中的 Chart 绘制为内联SVG
+var TemplateFuncs = map[string]any{
+	"money": func(v any) (string, error) {
+		f, err := templateNumber(v)
+		return i18n.Money(f), err
+	},
+	"number": func(v any, digits ...int) (string, error) {
+		f, err := templateNumber(v)
+		if len(digits) == 0 {
+			digits = []int{0}
+		}
+		return i18n.Number(f, digits[0]), err
+	},
+	"percent": func(v any) (string, error) {
+		f, err := templateNumber(v)
+		return i18n.Percent(f), err
+	},
+	"change": func(v any) (string, error) {
+		if p, ok := v.(*float64); ok {
+			return Change(p).String(), nil
+		}
+		f, err := templateNumber(v)
+		return i18n.Signed(f, i18n.Percent(f)), err
+	},
+	"date":    templateDate,
+	"t":       i18n.T,
+	"emoji":   templateEmoji,
+	"noemoji": plainText,
+	"pad": func(s string, width int) string {
+		return table.Pad(s, width, table.AlignLeft)
+	},
+	"padLeft": func(s string, width int) string {
+		return table.Pad(s, width, table.AlignRight)
+	},
+	"table": func(t Table) string {
+		tw := table.New(t.Columns...)
+		for i := range t.Columns {
+			if columnNumeric(t, i) {
+				tw.SetAlign(i, table.AlignRight)
+			}
+		}
+		for _, row := range t.Rows {
+			cells := make([]string, len(row))
+			for i, cell := range row {
+				cells[i] = cell.String()
+			}
+			tw.Append(cells...)
+		}
+		return tw.String()
+	},
+	"svg": func(c Chart) htmltemplate.HTML {
+		// SVG 内所有文本都已转义
+		return htmltemplate.HTML(SVG(c))
+	},
+}
+
+// Emojis 模板函数 emoji 可用的名称，与报表中使用的图标一致
+var Emojis = map[string]string{
+	"chart":   "📊",
+	"up":      "📈",
+	"down":    "📉",
+	"product": "🛍️",
+	"region":  "🗺️",
+	"date":    "📅",
+	"trophy":  "🏆",
+	"target":  "🎯",
+	"success": "✅",
+	"warning": "⚠️",
+	"error":   "❌",
+	"info":    "💡",
+	"search":  "🔍",
+}
+
+func templateNumber(v any) (float64, error) {
+	if p, ok := v.(*float64); ok && p != nil {
+		v = *p
+	}
+	f, ok := number(v)
+	if !ok {
+		return 0, i18n.Errorf("不是数字: %v (%T)", v, v)
+	}
+	return f, nil
+}
+
+func templateDate(v any, layout ...string) (string, error) {
+	format := sales.DateLayout
+	if len(layout) > 0 {
+		format = layout[0]
+	}
+	switch d := v.(type) {
+	case time.Time:
+		return d.Format(format), nil
+	case string:
+		parsed, err := time.Parse(sales.DateLayout, d)
+		if err != nil {
+			return "", i18n.Errorf("日期格式错误: %q", d)
+		}
+		return parsed.Format(format), nil
+	default:
+		return "", i18n.Errorf("不是日期: %v (%T)", v, v)
+	}
+}
+
+func templateEmoji(name string) (string, error) {
+	e, ok := Emojis[name]
+	if !ok {
+		return "", i18n.Errorf("未知的emoji名称: %q", name)
+	}
+	return e, nil
+}
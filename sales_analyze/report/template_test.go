@@ -0,0 +1,77 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"sales-analyzer/sales"
+)
+
+// fixtureReport 三天、两个产品、两个地区的报表，包含周报模板用到的全部章节
+func fixtureReport() *Report {
+	records := []sales.Record{
+		{Date: "2025-01-01", Product: "手机", Quantity: 2, Amount: 5998, Region: "华东"},
+		{Date: "2025-01-01", Product: "电脑", Quantity: 1, Amount: 6999, Region: "华南"},
+		{Date: "2025-01-02", Product: "手机", Quantity: 1, Amount: 2999, Region: "华南"},
+		{Date: "2025-01-03", Product: "电脑", Quantity: 2, Amount: 13998, Region: "华东"},
+	}
+	result := sales.Aggregate(records, sales.Options{})
+	return &Report{
+		Title:       "📊 销售周报",
+		Source:      "sales.csv",
+		GeneratedAt: time.Date(2025, 1, 4, 9, 30, 0, 0, time.UTC),
+		Sections: []Section{
+			OverallSection(sales.AnalyzeOverall(result)),
+			ProductSection(sales.AnalyzeByProduct(result)),
+			RegionSection(sales.AnalyzeByRegion(result)),
+			DateSection(sales.AnalyzeByDate(result)),
+		},
+	}
+}
+
+func TestWeeklyTemplate(t *testing.T) {
+	tmpl, err := NewTemplate("../templates/weekly.md.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Render(&b, fixtureReport()); err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("testdata/weekly.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); got != string(want) {
+		t.Errorf("周报输出与 testdata/weekly.md 不一致:\n%s", got)
+	}
+}
+
+func TestTemplateDate(t *testing.T) {
+	generated := time.Date(2025, 1, 4, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		v      any
+		layout []string
+		want   string
+		err    bool
+	}{
+		{generated, nil, "2025-01-04", false},
+		{generated, []string{"01/02 15:04"}, "01/04 09:30", false},
+		{"2025-01-04", nil, "2025-01-04", false},
+		{"2025-01-04", []string{"Jan 2"}, "Jan 4", false},
+		{"2025/01/04", nil, "", true},
+		{20250104, nil, "", true},
+	}
+	for _, tt := range tests {
+		got, err := templateDate(tt.v, tt.layout...)
+		if (err != nil) != tt.err {
+			t.Errorf("templateDate(%v, %v) 错误 = %v", tt.v, tt.layout, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("templateDate(%v, %v) = %q，应为 %q", tt.v, tt.layout, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package report
+
+import (
+	"math"
+	"strings"
+
+	"sales-analyzer/i18n"
+	"sales-analyzer/style"
+	"sales-analyzer/table"
+)
+
+// barWidth 最长条形的字符数
+const barWidth = 30
+
+// sparkLevels 迷你折线图的8个高度
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// chart 在终端中绘制图表
+func (p *textPrinter) chart(c Chart) {
+	if len(c.Values) == 0 {
+		return
+	}
+	if c.Title != "" {
+		p.printf("%s\n", c.Title)
+	}
+	if c.Kind == ChartLine {
+		p.sparkline(c)
+		return
+	}
+	p.bars(c)
+}
+
+// bars 横向条形图: 标签、条形和数值各占一列
+func (p *textPrinter) bars(c Chart) {
+	labelWidth := 0
+	for _, label := range c.Labels {
+		labelWidth = max(labelWidth, table.Width(label))
+	}
+	maxValue := maxOf(c.Values)
+
+	for i, value := range c.Values {
+		// 负值、NaN 和无穷大都由 table.Bar 截断到 [0, 1]
+		ratio := 0.0
+		if maxValue > 0 {
+			ratio = value / maxValue
+		}
+		p.printf("%s %s %s\n", table.Pad(c.Labels[i], labelWidth, table.AlignLeft),
+			p.style.Series(i, table.Bar(ratio, barWidth)), c.Format(i))
+	}
+}
+
+// sparkline 用一行块字符表示序列走势，并标出起止标签和最高、最低值
+func (p *textPrinter) sparkline(c Chart) {
+	lo, hi := c.Values[0], c.Values[0]
+	loIndex, hiIndex := 0, 0
+	for i, value := range c.Values {
+		if value < lo {
+			lo, loIndex = value, i
+		}
+		if value > hi {
+			hi, hiIndex = value, i
+		}
+	}
+
+	var b strings.Builder
+	for _, value := range c.Values {
+		level := len(sparkLevels) / 2
+		if hi > lo {
+			// NaN 和无穷大算出的位置不在 [0, 1] 内，按中间高度绘制
+			if position := (value - lo) / (hi - lo); position >= 0 && position <= 1 {
+				level = int(math.Round(position * float64(len(sparkLevels)-1)))
+			}
+		}
+		// 每个数据点占两格，点数较少时也能看清
+		b.WriteString(strings.Repeat(string(sparkLevels[level]), 2))
+	}
+
+	p.printf("%s  %s → %s\n", p.style.Series(0, b.String()), c.Labels[0], c.Labels[len(c.Labels)-1])
+	p.printf("%s\n", p.style.Paint(style.Muted, i18n.Sprintf("最高 %s (%s)  最低 %s (%s)",
+		c.Format(hiIndex), c.Labels[hiIndex], c.Format(loIndex), c.Labels[loIndex])))
+}
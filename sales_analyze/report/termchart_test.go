@@ -0,0 +1,61 @@
+package report
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"sales-analyzer/style"
+)
+
+func TestTerminalChartsNonFinite(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	tests := []struct {
+		name   string
+		values []float64
+	}{
+		{"NaN", []float64{100, nan, 50}},
+		{"全部NaN", []float64{nan, nan}},
+		{"正无穷", []float64{100, inf, 50}},
+		{"负无穷", []float64{100, -inf, 50}},
+		{"正负无穷", []float64{inf, -inf}},
+		{"负数", []float64{-100, 50}},
+	}
+	for _, tt := range tests {
+		for _, kind := range []ChartKind{ChartBar, ChartLine} {
+			t.Run(tt.name+"/"+string(kind), func(t *testing.T) {
+				labels := make([]string, len(tt.values))
+				for i := range labels {
+					labels[i] = strings.Repeat("x", i+1)
+				}
+				var buf bytes.Buffer
+				p := &textPrinter{w: &buf, style: style.For(&buf)}
+				p.chart(Chart{Kind: kind, Labels: labels, Values: tt.values, ValueKind: KindMoney})
+				if p.err != nil {
+					t.Fatal(p.err)
+				}
+				if buf.Len() == 0 {
+					t.Error("没有输出图表")
+				}
+			})
+		}
+	}
+}
+
+func TestTerminalBarsClamped(t *testing.T) {
+	var buf bytes.Buffer
+	p := &textPrinter{w: &buf, style: style.For(&buf)}
+	p.chart(Chart{Kind: ChartBar, Labels: []string{"a", "b"}, Values: []float64{-50, 100}, ValueKind: KindInt})
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("应输出2行，得到 %q", buf.String())
+	}
+	if !strings.Contains(lines[0], strings.Repeat("░", barWidth)) {
+		t.Errorf("负值应绘制为空条形: %q", lines[0])
+	}
+	if !strings.Contains(lines[1], strings.Repeat("█", barWidth)) {
+		t.Errorf("最大值应绘制为满条形: %q", lines[1])
+	}
+}
@@ -0,0 +1,113 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"sales-analyzer/style"
+	"sales-analyzer/table"
+)
+
+// levelRoles 说明文字的级别对应的样式角色
+var levelRoles = map[Level]style.Role{
+	LevelInfo:    style.Info,
+	LevelSuccess: style.Success,
+	LevelWarning: style.Warning,
+}
+
+// TextReporter 终端表格输出。
+// 颜色由 style 包的当前主题决定，输出目标不是终端或设置了 NO_COLOR 时不输出颜色。
+type TextReporter struct {
+	TableStyle *table.Style // 表格的边框样式，nil 表示 table.StyleASCII
+}
+
+// Render 实现 Reporter
+func (t *TextReporter) Render(w io.Writer, r *Report) error {
+	p := &textPrinter{w: w, style: style.For(w), width: table.TerminalWidth(w), tableStyle: t.TableStyle}
+
+	p.header(r.Title, style.Title)
+	p.notes(r.Notes)
+
+	for _, section := range r.Sections {
+		fmt.Fprintln(w)
+		p.header(section.Title, style.Header)
+
+		if len(section.Intro) > 0 {
+			p.notes(section.Intro)
+			fmt.Fprintln(w)
+		}
+
+		for i, table := range section.Tables {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			if table.Title != "" {
+				fmt.Fprintln(w, table.Title)
+			}
+			p.table(table)
+		}
+
+		for _, chart := range section.Charts {
+			fmt.Fprintln(w)
+			p.chart(chart)
+		}
+
+		if len(section.Notes) > 0 {
+			fmt.Fprintln(w)
+			p.notes(section.Notes)
+		}
+	}
+
+	return p.err
+}
+
+type textPrinter struct {
+	w          io.Writer
+	style      *style.Styler
+	width      int // 终端宽度，0 表示不限制
+	tableStyle *table.Style
+	err        error
+}
+
+func (p *textPrinter) printf(format string, args ...any) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = fmt.Fprintf(p.w, format, args...)
+}
+
+// header 按角色样式打印标题和分隔线
+func (p *textPrinter) header(text string, role style.Role) {
+	p.printf("%s\n", p.style.Paint(role, text))
+	p.printf("%s\n", strings.Repeat("=", 50))
+}
+
+func (p *textPrinter) notes(notes []Note) {
+	for _, note := range notes {
+		p.printf("%s\n", p.style.Paint(levelRoles[note.Level], note.Text))
+	}
+}
+
+// table 打印表格，数值列右对齐，超出终端宽度时文本列折行
+func (p *textPrinter) table(t Table) {
+	tw := table.New(t.Columns...)
+	tw.Style = p.tableStyle
+	tw.MaxWidth = p.width
+	for i := range t.Columns {
+		if columnNumeric(t, i) {
+			tw.SetAlign(i, table.AlignRight)
+		}
+	}
+	for _, row := range t.Rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = cell.String()
+		}
+		tw.Append(cells...)
+	}
+
+	if p.err == nil {
+		p.err = tw.Render(p.w)
+	}
+}
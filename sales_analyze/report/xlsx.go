@@ -0,0 +1,267 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/xuri/excelize/v2"
+
+	"sales-analyzer/i18n"
+	"sales-analyzer/sales"
+	"sales-analyzer/table"
+)
+
+// XLSXReporter 输出Excel工作簿: 每个章节一个工作表，另有一个清洗后原始数据的工作表。
+// 单元格写入数值类型并设置金额、百分比等数字格式，表头行冻结，列宽按内容自动调整。
+type XLSXReporter struct {
+	// Records 清洗后的原始记录，非空时写入 "原始数据" 工作表
+	Records []sales.Record
+}
+
+// 数值类型对应的Excel数字格式，金额格式取决于语言区域
+var xlsxNumberFormats = map[Kind]string{
+	KindInt:         "#,##0",
+	KindPercent:     "0.0%",
+	KindChange:      "+0.0%;-0.0%;0.0%",
+	KindMoneyChange: "+#,##0.00;-#,##0.00;0.00",
+	KindIntChange:   "+#,##0;-#,##0;0",
+}
+
+// Render 实现 Reporter
+func (x *XLSXReporter) Render(w io.Writer, r *Report) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	book := &workbook{file: f, styles: make(map[string]int), names: make(map[string]bool)}
+	for _, section := range r.Sections {
+		if len(section.Tables) == 0 {
+			continue
+		}
+		if err := book.sheet(section.Title, section.Tables); err != nil {
+			return err
+		}
+	}
+	if len(x.Records) > 0 {
+		if err := book.sheet(i18n.T("原始数据"), []Table{RecordsTable(x.Records)}); err != nil {
+			return err
+		}
+	}
+
+	f.SetActiveSheet(0)
+
+	if _, err := f.WriteTo(w); err != nil {
+		return i18n.Errorf("写入Excel文件失败: %w", err)
+	}
+	return nil
+}
+
+// RecordsTable 将原始记录转换为表格
+func RecordsTable(records []sales.Record) Table {
+	t := Table{Columns: columns("日期", "产品", "销量", "销售额", "地区")}
+	for _, record := range records {
+		t.Rows = append(t.Rows, []Cell{
+			Text(record.Date),
+			Text(record.Product),
+			Int(record.Quantity),
+			Money(record.Amount),
+			Text(record.Region),
+		})
+	}
+	return t
+}
+
+// defaultSheet 新建工作簿自带的空白工作表
+const defaultSheet = "Sheet1"
+
+type workbook struct {
+	file   *excelize.File
+	styles map[string]int  // 按数字格式缓存的样式ID
+	names  map[string]bool // 已使用的工作表名 (小写，Excel不区分大小写)
+	count  int
+}
+
+// sheet 新建一个工作表，依次写入各表格，表格之间空一行。
+// 第一个工作表由自带的空白工作表改名而来，章节标题恰好是 Sheet1 时也不会与之混淆。
+func (b *workbook) sheet(title string, tables []Table) error {
+	name := b.sheetName(title)
+	if b.count == 0 {
+		if err := b.file.SetSheetName(defaultSheet, name); err != nil {
+			return i18n.Errorf("无法创建工作表 %q: %w", name, err)
+		}
+	} else if _, err := b.file.NewSheet(name); err != nil {
+		return i18n.Errorf("无法创建工作表 %q: %w", name, err)
+	}
+	b.count++
+
+	headerStyle, err := b.style("", true)
+	if err != nil {
+		return err
+	}
+
+	var widths []int
+	row := 1
+	for i, t := range tables {
+		if i > 0 {
+			row++
+		}
+		if t.Title != "" {
+			if err := b.file.SetCellValue(name, cellName(0, row), t.Title); err != nil {
+				return err
+			}
+			row++
+		}
+
+		for col, column := range t.Columns {
+			if err := b.set(name, col, row, column, headerStyle); err != nil {
+				return err
+			}
+			widths = growWidths(widths, col, table.Width(column))
+		}
+		// 冻结第一张表格的表头
+		if i == 0 {
+			if err := b.file.SetPanes(name, &excelize.Panes{
+				Freeze:      true,
+				YSplit:      row,
+				TopLeftCell: cellName(0, row+1),
+				ActivePane:  "bottomLeft",
+			}); err != nil {
+				return err
+			}
+		}
+		row++
+
+		for _, cells := range t.Rows {
+			for col, cell := range cells {
+				value, format := xlsxValue(cell)
+				style, err := b.style(format, false)
+				if err != nil {
+					return err
+				}
+				if err := b.set(name, col, row, value, style); err != nil {
+					return err
+				}
+				widths = growWidths(widths, col, table.Width(cell.String()))
+			}
+			row++
+		}
+	}
+
+	for col, width := range widths {
+		column := columnName(col)
+		if err := b.file.SetColWidth(name, column, column, float64(width)+2); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *workbook) set(sheet string, col, row int, value any, style int) error {
+	cell := cellName(col, row)
+	if err := b.file.SetCellValue(sheet, cell, value); err != nil {
+		return err
+	}
+	return b.file.SetCellStyle(sheet, cell, cell, style)
+}
+
+// style 返回指定数字格式的样式ID，相同格式只创建一次
+func (b *workbook) style(format string, header bool) (int, error) {
+	key := format
+	if header {
+		key = "header"
+	}
+	if id, ok := b.styles[key]; ok {
+		return id, nil
+	}
+
+	style := &excelize.Style{}
+	if header {
+		style.Font = &excelize.Font{Bold: true}
+		style.Fill = excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"F0F4FA"}}
+	}
+	if format != "" {
+		style.CustomNumFmt = &format
+	}
+	id, err := b.file.NewStyle(style)
+	if err != nil {
+		return 0, i18n.Errorf("无法创建单元格样式: %w", err)
+	}
+	b.styles[key] = id
+	return id, nil
+}
+
+// sheetName 由章节标题生成合法且不重复 (不区分大小写) 的工作表名:
+// 去掉开头的emoji，去掉Excel不允许的字符，最长31个字符
+func (b *workbook) sheetName(title string) string {
+	name := strings.TrimLeftFunc(title, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" {
+		name = "Sheet"
+	}
+	name = truncateRunes(name, 31)
+
+	unique := name
+	for n := 2; b.names[strings.ToLower(unique)]; n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		unique = truncateRunes(name, 31-len(suffix)) + suffix
+	}
+	b.names[strings.ToLower(unique)] = true
+	return unique
+}
+
+// xlsxValue 返回写入单元格的值和数字格式。
+// 百分比在报表模型中以 57.1 表示 57.1%，Excel 的百分比格式需要 0.571。
+func xlsxValue(c Cell) (any, string) {
+	if c.Value == nil {
+		return nil, ""
+	}
+	format := xlsxNumberFormats[c.Kind]
+	if c.Kind == KindMoney {
+		format = i18n.Current().ExcelCurrency
+	}
+	if c.Unit != "" && format != "" && !strings.Contains(format, ";") {
+		format += ` "` + c.Unit + `"`
+	}
+
+	switch c.Kind {
+	case KindPercent, KindChange:
+		if v, ok := c.Value.(float64); ok {
+			return v / 100, format
+		}
+	}
+	return c.Value, format
+}
+
+func growWidths(widths []int, col, width int) []int {
+	for len(widths) <= col {
+		widths = append(widths, 0)
+	}
+	widths[col] = max(widths[col], width)
+	return widths
+}
+
+func cellName(col, row int) string {
+	name, _ := excelize.CoordinatesToCellName(col+1, row)
+	return name
+}
+
+func columnName(col int) string {
+	name, _ := excelize.ColumnNumberToName(col + 1)
+	return name
+}
+
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
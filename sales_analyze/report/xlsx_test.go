@@ -0,0 +1,143 @@
+package report
+
+import (
+	"bytes"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+
+	"sales-analyzer/sales"
+)
+
+// renderXLSX 输出工作簿并重新打开
+func renderXLSX(t *testing.T, x *XLSXReporter, r *Report) *excelize.File {
+	t.Helper()
+	var b bytes.Buffer
+	if err := x.Render(&b, r); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestXLSXCells(t *testing.T) {
+	records := []sales.Record{{Date: "2025-01-01", Product: "手机", Quantity: 2, Amount: 5998, Region: "华东"}}
+	f := renderXLSX(t, &XLSXReporter{Records: records}, writerReport())
+
+	if got, want := f.GetSheetList(), []string{"产品 | 分析", "总体", "原始数据"}; !slices.Equal(got, want) {
+		t.Fatalf("工作表 = %v，应为 %v", got, want)
+	}
+
+	const sheet = "产品 | 分析"
+	tests := []struct {
+		cell, raw, format string
+	}{
+		{"A1", "产品", ""},
+		{"A2", "手机|Pro", ""},
+		{"B2", "1234567.5", "¥#,##0.00"},
+		{"C2", "0.571", "0.0%"}, // 百分比除以100后按百分比格式显示
+		{"D2", "1200", "#,##0"},
+		{"E2", "", ""},
+		{"D3", "-3", "#,##0"},
+	}
+	for _, tt := range tests {
+		raw, err := f.GetCellValue(sheet, tt.cell, excelize.Options{RawCellValue: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sameValue(raw, tt.raw) {
+			t.Errorf("%s = %q，应为 %q", tt.cell, raw, tt.raw)
+		}
+		if format := numberFormat(t, f, sheet, tt.cell); format != tt.format {
+			t.Errorf("%s 的数字格式 = %q，应为 %q", tt.cell, format, tt.format)
+		}
+		if tt.format != "" {
+			if typ, _ := f.GetCellType(sheet, tt.cell); typ == excelize.CellTypeSharedString || typ == excelize.CellTypeInlineString {
+				t.Errorf("%s 应为数值单元格，实际为文本", tt.cell)
+			}
+		}
+	}
+
+	// 冻结第一张表格的表头，表格标题占一行时冻结到标题之下的表头
+	for sheet, want := range map[string]int{"产品 | 分析": 1, "总体": 2, "原始数据": 1} {
+		panes, err := f.GetPanes(sheet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !panes.Freeze || panes.YSplit != want {
+			t.Errorf("%s 的冻结窗格 = %+v，应冻结前 %d 行", sheet, panes, want)
+		}
+	}
+}
+
+// sameValue 两个单元格原始值是否相同，数值允许浮点误差
+func sameValue(a, b string) bool {
+	x, errx := strconv.ParseFloat(a, 64)
+	y, erry := strconv.ParseFloat(b, 64)
+	if errx != nil || erry != nil {
+		return a == b
+	}
+	return math.Abs(x-y) < 1e-9
+}
+
+func numberFormat(t *testing.T, f *excelize.File, sheet, cell string) string {
+	t.Helper()
+	id, err := f.GetCellStyle(sheet, cell)
+	if err != nil {
+		t.Fatal(err)
+	}
+	style, err := f.GetStyle(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if style.CustomNumFmt == nil {
+		return ""
+	}
+	return *style.CustomNumFmt
+}
+
+func TestXLSXSheetNames(t *testing.T) {
+	long := strings.Repeat("月度", 20)
+	titles := []string{"🛍️  产品分析", "Sheet1", "sheet1", "汇总", "汇总", "a/b:c?[d]", "📈", long, long}
+	r := &Report{}
+	for i, title := range titles {
+		r.Sections = append(r.Sections, Section{Title: title, Tables: []Table{{
+			Columns: []string{"序号"},
+			Rows:    [][]Cell{{Int(i)}},
+		}}})
+	}
+	f := renderXLSX(t, &XLSXReporter{}, r)
+
+	truncated := string([]rune(long)[:31])
+	want := []string{"产品分析", "Sheet1", "sheet1 (2)", "汇总", "汇总 (2)", "abcd", "Sheet", truncated, truncated[:len(truncated)-3*4] + " (2)"}
+	got := f.GetSheetList()
+	if !slices.Equal(got, want) {
+		t.Fatalf("工作表 = %q，应为 %q", got, want)
+	}
+	for i, name := range got {
+		if n := utf8.RuneCountInString(name); n > 31 {
+			t.Errorf("%q 有 %d 个字符，超过31个", name, n)
+		}
+		// 每个章节的数据都在自己的工作表中，标题为 Sheet1 的章节没有被当作空白工作表删除
+		if value, _ := f.GetCellValue(name, "A2"); value != string(rune('0'+i)) {
+			t.Errorf("工作表 %q 的数据 = %q，应为 %d", name, value, i)
+		}
+	}
+}
+
+// TestXLSXEmpty 没有表格时输出只有一个空白工作表的工作簿
+func TestXLSXEmpty(t *testing.T) {
+	f := renderXLSX(t, &XLSXReporter{}, &Report{Sections: []Section{{Title: "总体"}}})
+	if got := f.GetSheetList(); len(got) != 1 {
+		t.Errorf("工作表 = %v，应只有一个空白工作表", got)
+	}
+}
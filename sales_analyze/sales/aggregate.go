@@ -0,0 +1,248 @@
+package sales
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// DefaultShardSize 默认每个分片包含的记录数。
+// 分片大小固定而不随CPU核数变化，保证在不同机器上合并出完全相同的浮点结果。
+const DefaultShardSize = 4096
+
+// Summary 一组记录的汇总值
+type Summary struct {
+	Quantity int     `json:"quantity"`
+	Amount   float64 `json:"amount"`
+	Count    int     `json:"count"`
+}
+
+func (s *Summary) add(record Record) {
+	s.Quantity += record.Quantity
+	s.Amount += record.Amount
+	s.Count++
+}
+
+func (s *Summary) merge(other *Summary) {
+	s.Quantity += other.Quantity
+	s.Amount += other.Amount
+	s.Count += other.Count
+}
+
+// Key 聚合的最细粒度: 日期+产品+地区
+type Key struct {
+	Date    string
+	Product string
+	Region  string
+}
+
+// Result 一次遍历得到的全部汇总，供各个报表共用
+type Result struct {
+	Overall  Summary
+	Products map[string]*Summary
+	Regions  map[string]*Summary
+	Dates    map[string]*Summary
+	Cells    map[Key]*Summary
+
+	// Sketches 近似度量，未启用时为nil
+	Sketches *Sketches
+}
+
+// NewResult 创建空的汇总结果，sketches 指定需要启用的近似度量
+func NewResult(sketches SketchOptions) *Result {
+	result := &Result{
+		Products: make(map[string]*Summary),
+		Regions:  make(map[string]*Summary),
+		Dates:    make(map[string]*Summary),
+		Cells:    make(map[Key]*Summary),
+	}
+	if sketches.Enabled() {
+		result.Sketches = newSketches(sketches)
+	}
+	return result
+}
+
+// Add 将一条记录计入汇总
+func (r *Result) Add(record Record) {
+	r.Overall.add(record)
+	group(r.Products, record.Product).add(record)
+	group(r.Regions, record.Region).add(record)
+	group(r.Dates, record.Date).add(record)
+	group(r.Cells, Key{record.Date, record.Product, record.Region}).add(record)
+	if r.Sketches != nil {
+		r.Sketches.add(record)
+	}
+}
+
+// Merge 将另一个部分汇总合并进来。
+// 每个分组只与自身累加，结果不受map遍历顺序影响。
+// 两个汇总必须使用相同的 SketchOptions 创建，否则草图无法合并，返回错误。
+func (r *Result) Merge(other *Result) error {
+	if (r.Sketches == nil) != (other.Sketches == nil) {
+		return fmt.Errorf("合并汇总失败: 只有一方启用了近似度量")
+	}
+	if r.Sketches != nil {
+		if err := r.Sketches.merge(other.Sketches); err != nil {
+			return fmt.Errorf("合并汇总失败: %w", err)
+		}
+	}
+	r.Overall.merge(&other.Overall)
+	mergeGroups(r.Products, other.Products)
+	mergeGroups(r.Regions, other.Regions)
+	mergeGroups(r.Dates, other.Dates)
+	mergeGroups(r.Cells, other.Cells)
+	return nil
+}
+
+// CellKeys 返回按 日期、产品、地区 排序的最细粒度分组键
+func (r *Result) CellKeys() []Key {
+	keys := make([]Key, 0, len(r.Cells))
+	for key := range r.Cells {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Date != keys[j].Date {
+			return keys[i].Date < keys[j].Date
+		}
+		if keys[i].Product != keys[j].Product {
+			return keys[i].Product < keys[j].Product
+		}
+		return keys[i].Region < keys[j].Region
+	})
+	return keys
+}
+
+// SortedKeys 返回分组名称的升序列表
+func SortedKeys(groups map[string]*Summary) []string {
+	return sortedKeys(groups)
+}
+
+func sortedKeys[V any](groups map[string]V) []string {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func group[K comparable](groups map[K]*Summary, key K) *Summary {
+	summary, exists := groups[key]
+	if !exists {
+		summary = &Summary{}
+		groups[key] = summary
+	}
+	return summary
+}
+
+func mergeGroups[K comparable](dst, src map[K]*Summary) {
+	for key, summary := range src {
+		group(dst, key).merge(summary)
+	}
+}
+
+// Shard 输入数据的一个分片，Index 从0开始连续编号
+type Shard struct {
+	Index   int
+	Records []Record
+}
+
+// Split 将记录按固定大小切分为分片，不复制数据
+func Split(records []Record, size int) []Shard {
+	if size <= 0 {
+		size = DefaultShardSize
+	}
+
+	var shards []Shard
+	for start := 0; start < len(records); start += size {
+		end := min(start+size, len(records))
+		shards = append(shards, Shard{Index: len(shards), Records: records[start:end]})
+	}
+	return shards
+}
+
+// Options 聚合选项
+type Options struct {
+	Workers   int // 工作协程数，默认为 GOMAXPROCS
+	ShardSize int // 每个分片的记录数，默认为 DefaultShardSize
+	Sketches  SketchOptions
+	// Progress 每汇总完一个分片调用一次，参数为该分片的记录数；由多个工作协程并发调用，可以为nil
+	Progress func(records int)
+}
+
+// Aggregate 将记录切分为分片，由工作池并行汇总
+func Aggregate(records []Record, opts Options) *Result {
+	in := make(chan Shard)
+	go func() {
+		defer close(in)
+		for _, shard := range Split(records, opts.ShardSize) {
+			in <- shard
+		}
+	}()
+
+	return AggregateShards(in, opts)
+}
+
+// AggregateShards 从通道读取分片并由 opts.Workers 个协程并行汇总。
+// 每个分片得到独立的部分汇总，再严格按 Index 顺序合并，
+// 因此相同的分片划分总是得到相同的结果，与协程数和调度顺序无关。
+// 分片的 Index 必须从0开始连续编号。
+// opts.ShardSize 在这里不起作用，分片由调用方划分。
+func AggregateShards(in <-chan Shard, opts Options) *Result {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	type partial struct {
+		index  int
+		result *Result
+	}
+
+	partials := make(chan partial, workers)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for shard := range in {
+				result := NewResult(opts.Sketches)
+				for _, record := range shard.Records {
+					result.Add(record)
+				}
+				if opts.Progress != nil {
+					opts.Progress(len(shard.Records))
+				}
+				partials <- partial{shard.Index, result}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(partials)
+	}()
+
+	// 乱序到达的部分汇总先暂存，等前面的分片到齐后再依次合并
+	total := NewResult(opts.Sketches)
+	pending := make(map[int]*Result)
+	next := 0
+	for p := range partials {
+		pending[p.index] = p.result
+		for {
+			result, ok := pending[next]
+			if !ok {
+				break
+			}
+			// 所有部分汇总都按 opts.Sketches 创建，合并不会失败
+			if err := total.Merge(result); err != nil {
+				panic(err)
+			}
+			delete(pending, next)
+			next++
+		}
+	}
+
+	return total
+}
@@ -0,0 +1,130 @@
+package sales
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+// testRecords 生成固定的测试记录。金额的数量级相差很大，
+// 浮点加法的结果依赖相加的顺序，合并顺序一旦不固定就会得到不同的结果。
+func testRecords(n int) []Record {
+	r := rand.New(rand.NewPCG(42, 1024))
+	products := []string{"手机", "电脑", "耳机", "平板", "手表"}
+	regions := []string{"华东", "华南", "华北", "西南"}
+	records := make([]Record, n)
+	for i := range records {
+		records[i] = Record{
+			Date:     fmt.Sprintf("2025-01-%02d", 1+r.IntN(28)),
+			Product:  products[r.IntN(len(products))],
+			Quantity: 1 + r.IntN(9),
+			Amount:   r.Float64() * math.Pow(10, float64(r.IntN(12))),
+			Region:   regions[r.IntN(len(regions))],
+		}
+	}
+	return records
+}
+
+func TestTestRecordsOrderSensitive(t *testing.T) {
+	// 前提: 正序与倒序求和的结果不同，否则下面的测试发现不了合并顺序的问题
+	records := testRecords(10000)
+	var forward, backward float64
+	for i := range records {
+		forward += records[i].Amount
+		backward += records[len(records)-1-i].Amount
+	}
+	if forward == backward {
+		t.Fatal("测试数据的求和与顺序无关")
+	}
+}
+
+func TestAggregateDeterministic(t *testing.T) {
+	records := testRecords(10000)
+	sketches := SketchOptions{DistinctError: 0.02, QuantileError: 0.02}
+
+	for _, shardSize := range []int{13, 100, 1000, DefaultShardSize, len(records)} {
+		t.Run(fmt.Sprintf("shard=%d", shardSize), func(t *testing.T) {
+			want := Aggregate(records, Options{Workers: 1, ShardSize: shardSize, Sketches: sketches})
+			for _, workers := range []int{4, 16} {
+				// 重复几次，让协程的调度顺序有机会不同
+				for range 3 {
+					got := Aggregate(records, Options{Workers: workers, ShardSize: shardSize, Sketches: sketches})
+					if !reflect.DeepEqual(got, want) {
+						t.Fatalf("Workers=%d 的结果与 Workers=1 不同: 总金额 %v != %v", workers, got.Overall.Amount, want.Overall.Amount)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestAggregateSingleShardMatchesSequential(t *testing.T) {
+	records := testRecords(5000)
+
+	want := NewResult(SketchOptions{})
+	for _, record := range records {
+		want.Add(record)
+	}
+	got := Aggregate(records, Options{Workers: 4, ShardSize: len(records)})
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("只有一个分片时结果与顺序累加不同: 总金额 %v != %v", got.Overall.Amount, want.Overall.Amount)
+	}
+}
+
+func TestAggregateShardSizes(t *testing.T) {
+	// 分片大小不同时浮点求和的顺序不同，金额只要求在舍入误差内一致，其余字段必须完全相同
+	records := testRecords(10000)
+	want := Aggregate(records, Options{Workers: 1, ShardSize: DefaultShardSize})
+
+	for _, shardSize := range []int{1, 7, 100, 1000} {
+		got := Aggregate(records, Options{Workers: 8, ShardSize: shardSize})
+		for _, groups := range []struct {
+			name      string
+			got, want map[string]*Summary
+		}{
+			{"产品", got.Products, want.Products},
+			{"地区", got.Regions, want.Regions},
+			{"日期", got.Dates, want.Dates},
+			{"全部", map[string]*Summary{"": &got.Overall}, map[string]*Summary{"": &want.Overall}},
+		} {
+			if len(groups.got) != len(groups.want) {
+				t.Fatalf("shard=%d: %s分组数 %d != %d", shardSize, groups.name, len(groups.got), len(groups.want))
+			}
+			for key, w := range groups.want {
+				g := groups.got[key]
+				if g == nil || g.Quantity != w.Quantity || g.Count != w.Count ||
+					math.Abs(g.Amount-w.Amount) > 1e-9*math.Abs(w.Amount) {
+					t.Errorf("shard=%d: %s %q 为 %+v，应为 %+v", shardSize, groups.name, key, g, w)
+				}
+			}
+		}
+		if len(got.Cells) != len(want.Cells) {
+			t.Errorf("shard=%d: 最细粒度分组数 %d != %d", shardSize, len(got.Cells), len(want.Cells))
+		}
+	}
+}
+
+func TestAggregateEmpty(t *testing.T) {
+	got := Aggregate(nil, Options{Workers: 4})
+	if !reflect.DeepEqual(got, NewResult(SketchOptions{})) {
+		t.Errorf("没有记录时的结果应为空汇总，得到 %+v", got)
+	}
+}
+
+// BenchmarkAggregate 不同工作协程数下的汇总吞吐量:
+//
+//	go test -bench Aggregate -benchmem ./sales
+func BenchmarkAggregate(b *testing.B) {
+	records := testRecords(200000)
+	for _, workers := range []int{1, 2, 4, 8, 16} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				Aggregate(records, Options{Workers: workers})
+			}
+			b.ReportMetric(float64(len(records))*float64(b.N)/b.Elapsed().Seconds(), "records/s")
+		})
+	}
+}
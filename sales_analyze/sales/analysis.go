@@ -0,0 +1,152 @@
+package sales
+
+import (
+	"sort"
+)
+
+// Overall 总体销售指标
+type Overall struct {
+	TotalAmount   float64 `json:"total_amount"`
+	TotalQuantity int     `json:"total_quantity"`
+	AvgAmount     float64 `json:"avg_amount"`
+	Orders        int     `json:"orders"`
+}
+
+// ProductSummary 产品汇总结构体
+type ProductSummary struct {
+	Product     string  `json:"product"`
+	TotalQty    int     `json:"total_qty"`
+	TotalAmount float64 `json:"total_amount"`
+	AvgAmount   float64 `json:"avg_amount"`
+	RecordCount int     `json:"record_count"`
+}
+
+// RegionSummary 地区汇总结构体
+type RegionSummary struct {
+	Region      string  `json:"region"`
+	TotalQty    int     `json:"total_qty"`
+	TotalAmount float64 `json:"total_amount"`
+	RecordCount int     `json:"record_count"`
+	MarketShare float64 `json:"market_share"` // 占总销售额的百分比
+}
+
+// DaySummary 单日汇总
+type DaySummary struct {
+	Date        string   `json:"date"`
+	TotalQty    int      `json:"total_qty"`
+	TotalAmount float64  `json:"total_amount"`
+	GrowthRate  *float64 `json:"growth_rate"` // 较前一日的增长率 (%)，第一天为nil
+}
+
+// DateTrend 按日期的销售趋势
+type DateTrend struct {
+	Days []DaySummary `json:"days"`
+	// TotalGrowth 最后一天相对第一天的变化 (%)，不足两天时为nil
+	TotalGrowth *float64    `json:"total_growth,omitempty"`
+	BestDay     *DaySummary `json:"best_day,omitempty"`
+	WorstDay    *DaySummary `json:"worst_day,omitempty"`
+}
+
+// AnalyzeOverall 总体分析
+func AnalyzeOverall(result *Result) Overall {
+	o := Overall{
+		TotalAmount:   result.Overall.Amount,
+		TotalQuantity: result.Overall.Quantity,
+		Orders:        result.Overall.Count,
+	}
+	if o.Orders > 0 {
+		o.AvgAmount = o.TotalAmount / float64(o.Orders)
+	}
+	return o
+}
+
+// AnalyzeByProduct 按产品分析，按销售额降序排列
+func AnalyzeByProduct(result *Result) []ProductSummary {
+	var products []ProductSummary
+	for _, name := range SortedKeys(result.Products) {
+		summary := result.Products[name]
+		products = append(products, ProductSummary{
+			Product:     name,
+			TotalQty:    summary.Quantity,
+			TotalAmount: summary.Amount,
+			AvgAmount:   summary.Amount / float64(summary.Count),
+			RecordCount: summary.Count,
+		})
+	}
+
+	sort.SliceStable(products, func(i, j int) bool {
+		return products[i].TotalAmount > products[j].TotalAmount
+	})
+	return products
+}
+
+// AnalyzeByRegion 按地区分析，按销售额降序排列，并计算市场占比
+func AnalyzeByRegion(result *Result) []RegionSummary {
+	var regions []RegionSummary
+	for _, name := range SortedKeys(result.Regions) {
+		summary := result.Regions[name]
+		region := RegionSummary{
+			Region:      name,
+			TotalQty:    summary.Quantity,
+			TotalAmount: summary.Amount,
+			RecordCount: summary.Count,
+		}
+		if result.Overall.Amount != 0 {
+			region.MarketShare = summary.Amount / result.Overall.Amount * 100
+		}
+		regions = append(regions, region)
+	}
+
+	sort.SliceStable(regions, func(i, j int) bool {
+		return regions[i].TotalAmount > regions[j].TotalAmount
+	})
+	return regions
+}
+
+// AnalyzeByDate 按日期分析，计算日增长率、整体变化以及最佳和最差销售日
+func AnalyzeByDate(result *Result) DateTrend {
+	var trend DateTrend
+
+	for i, date := range SortedKeys(result.Dates) {
+		summary := result.Dates[date]
+		day := DaySummary{
+			Date:        date,
+			TotalQty:    summary.Quantity,
+			TotalAmount: summary.Amount,
+		}
+		if i > 0 {
+			day.GrowthRate = growth(trend.Days[i-1].TotalAmount, day.TotalAmount)
+		}
+		trend.Days = append(trend.Days, day)
+	}
+
+	if len(trend.Days) == 0 {
+		return trend
+	}
+
+	if len(trend.Days) >= 2 {
+		trend.TotalGrowth = growth(trend.Days[0].TotalAmount, trend.Days[len(trend.Days)-1].TotalAmount)
+	}
+
+	best, worst := trend.Days[0], trend.Days[0]
+	for _, day := range trend.Days[1:] {
+		if day.TotalAmount > best.TotalAmount {
+			best = day
+		}
+		if day.TotalAmount < worst.TotalAmount {
+			worst = day
+		}
+	}
+	trend.BestDay, trend.WorstDay = &best, &worst
+
+	return trend
+}
+
+// growth 计算从 from 到 to 的变化率 (%)，基数为0时返回nil
+func growth(from, to float64) *float64 {
+	if from == 0 {
+		return nil
+	}
+	rate := (to - from) / from * 100
+	return &rate
+}
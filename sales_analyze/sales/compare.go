@@ -0,0 +1,81 @@
+package sales
+
+import "sort"
+
+// Delta 某个分组在基准和对比两侧的汇总
+type Delta struct {
+	Name  string  `json:"name,omitempty"` // 产品、地区或日期；Comparison.Overall 的为空
+	Base  Summary `json:"base"`
+	Other Summary `json:"other"`
+}
+
+// AmountChange 销售额变化
+func (d Delta) AmountChange() float64 {
+	return d.Other.Amount - d.Base.Amount
+}
+
+// AmountChangeRate 销售额变化率 (%)，基准为0时返回0
+func (d Delta) AmountChangeRate() float64 {
+	if d.Base.Amount == 0 {
+		return 0
+	}
+	return d.AmountChange() / d.Base.Amount * 100
+}
+
+// QuantityChange 销量变化
+func (d Delta) QuantityChange() int {
+	return d.Other.Quantity - d.Base.Quantity
+}
+
+// Comparison 两份汇总在各标准报表维度上的对比，
+// 用于情景模拟 (基准 vs 情景) 和数据集差异 (原数据 vs 新数据)
+type Comparison struct {
+	Overall  Delta   `json:"overall"`
+	Products []Delta `json:"products"` // 按基准销售额降序
+	Regions  []Delta `json:"regions"`  // 按基准销售额降序
+	Dates    []Delta `json:"dates"`    // 按日期升序
+}
+
+// Compare 以 base 为基准对比另一份汇总结果
+func Compare(base, other *Result) Comparison {
+	c := Comparison{
+		Overall:  Delta{Base: base.Overall, Other: other.Overall},
+		Products: deltas(base.Products, other.Products),
+		Regions:  deltas(base.Regions, other.Regions),
+		Dates:    deltas(base.Dates, other.Dates),
+	}
+
+	byBaseAmount := func(items []Delta) {
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].Base.Amount > items[j].Base.Amount
+		})
+	}
+	byBaseAmount(c.Products)
+	byBaseAmount(c.Regions)
+
+	return c
+}
+
+// deltas 按名称关联两侧的分组，任一侧缺失的分组按0计
+func deltas(base, other map[string]*Summary) []Delta {
+	names := SortedKeys(base)
+	for _, name := range SortedKeys(other) {
+		if _, exists := base[name]; !exists {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var result []Delta
+	for _, name := range names {
+		d := Delta{Name: name}
+		if summary, exists := base[name]; exists {
+			d.Base = *summary
+		}
+		if summary, exists := other[name]; exists {
+			d.Other = *summary
+		}
+		result = append(result, d)
+	}
+	return result
+}
@@ -0,0 +1,37 @@
+// Package sales 定义销售记录，提供CSV读取、筛选、按分片并行汇总的聚合引擎，
+// 以及在汇总结果上计算的各项分析。命令行工具 sales 和其他服务都通过这个包完成分析。
+//
+// 典型用法分三步: 读取记录、汇总一次、在汇总结果上运行需要的分析。
+//
+//	records, rowErrs, err := sales.LoadFile("sales_data.csv")
+//	if err != nil {
+//		return err
+//	}
+//	for _, e := range rowErrs {
+//		log.Printf("跳过 %v", e) // 格式错误的行不会中断读取
+//	}
+//
+//	records = sales.Filter{Regions: []string{"华东"}, From: "2025-01-02"}.Apply(records)
+//	result := sales.Aggregate(records, sales.Options{})
+//
+//	overall := sales.AnalyzeOverall(result)
+//	for _, p := range sales.AnalyzeByProduct(result) {
+//		fmt.Printf("%s %.2f\n", p.Product, p.TotalAmount)
+//	}
+//	fmt.Println(overall.TotalAmount)
+//
+// 数据量很大时用 Scan 边读边汇总，不在内存中保留全部记录:
+//
+//	result := sales.NewResult(sales.SketchOptions{DistinctError: 0.01})
+//	_, err := sales.ScanFile("big.csv", func(line int, r sales.Record) {
+//		result.Add(r)
+//	})
+//
+// 多个来源的部分汇总可以用 Result.Merge 合并，Compare 对比两份汇总。
+// 分析结果类型 (Overall、ProductSummary、RegionSummary、DateTrend 等) 带有 json 标签，
+// 可以直接编码后提供给其他服务。
+//
+// 问题描述 (RowError.Message、Validate 的返回值) 按 i18n 包当前设置的语言翻译。
+//
+// 导出的类型和函数保持向后兼容: 只增加新的函数和结构体字段，不修改或删除已有的。
+package sales
@@ -0,0 +1,64 @@
+package sales_test
+
+import (
+	"fmt"
+	"strings"
+
+	"sales-analyzer/sales"
+)
+
+const exampleCSV = `日期,产品,销量,销售额,地区
+2025-01-01,手机,2,5998,华东
+2025-01-01,电脑,1,6999,华南
+2025-01-02,手机,1,2999,华南
+2025-01-02,耳机,x,399,华东
+2025-01-03,电脑,2,13998,华东
+`
+
+func exampleRecords() []sales.Record {
+	records, _, err := sales.Load(strings.NewReader(exampleCSV))
+	if err != nil {
+		panic(err)
+	}
+	return records
+}
+
+func ExampleLoad() {
+	records, rowErrs, err := sales.Load(strings.NewReader(exampleCSV))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(len(records), "条记录")
+	for _, e := range rowErrs {
+		fmt.Println("跳过第", e.Line, "行")
+	}
+	fmt.Printf("%s %s %d %.0f %s\n", records[0].Date, records[0].Product, records[0].Quantity, records[0].Amount, records[0].Region)
+	// Output:
+	// 4 条记录
+	// 跳过第 5 行
+	// 2025-01-01 手机 2 5998 华东
+}
+
+func ExampleAggregate() {
+	result := sales.Aggregate(exampleRecords(), sales.Options{Workers: 4})
+
+	overall := sales.AnalyzeOverall(result)
+	fmt.Printf("总销售额 %.2f，%d 笔订单\n", overall.TotalAmount, overall.Orders)
+	for _, p := range sales.AnalyzeByProduct(result) {
+		fmt.Printf("%s %d %.2f\n", p.Product, p.TotalQty, p.TotalAmount)
+	}
+	// Output:
+	// 总销售额 29994.00，4 笔订单
+	// 电脑 3 20997.00
+	// 手机 3 8997.00
+}
+
+func ExampleFilter() {
+	filter := sales.Filter{Regions: []string{"华东"}, From: "2025-01-02"}
+	for _, r := range filter.Apply(exampleRecords()) {
+		fmt.Println(r.Date, r.Product, r.Region)
+	}
+	// Output:
+	// 2025-01-03 电脑 华东
+}
@@ -0,0 +1,43 @@
+package sales
+
+import "slices"
+
+// Filter 按产品、地区和日期范围筛选记录，零值不筛选
+type Filter struct {
+	Products []string // 只保留这些产品，为空表示不限
+	Regions  []string // 只保留这些地区，为空表示不限
+	From     string   // 起始日期 (含)，格式见 DateLayout
+	To       string   // 结束日期 (含)
+}
+
+// IsZero 是否没有任何筛选条件
+func (f Filter) IsZero() bool {
+	return len(f.Products) == 0 && len(f.Regions) == 0 && f.From == "" && f.To == ""
+}
+
+// Match 记录是否满足所有条件
+func (f Filter) Match(record Record) bool {
+	switch {
+	case len(f.Products) > 0 && !slices.Contains(f.Products, record.Product):
+	case len(f.Regions) > 0 && !slices.Contains(f.Regions, record.Region):
+	case f.From != "" && record.Date < f.From:
+	case f.To != "" && record.Date > f.To:
+	default:
+		return true
+	}
+	return false
+}
+
+// Apply 返回满足条件的记录；没有条件时原样返回 records
+func (f Filter) Apply(records []Record) []Record {
+	if f.IsZero() {
+		return records
+	}
+	var matched []Record
+	for _, record := range records {
+		if f.Match(record) {
+			matched = append(matched, record)
+		}
+	}
+	return matched
+}
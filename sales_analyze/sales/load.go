@@ -0,0 +1,288 @@
+package sales
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"sales-analyzer/i18n"
+)
+
+// DateLayout 销售数据中日期列的格式
+const DateLayout = "2006-01-02"
+
+// Columns 销售数据CSV文件的表头，列按此顺序排列
+var Columns = []string{"日期", "产品", "销量", "销售额", "地区"}
+
+// Schema CSV表头中各字段的列名，用于读取列名或列顺序与 Columns 不同的文件。
+// 零值表示不看表头，按 Columns 的顺序读取各列；只设置了部分字段时，其余字段使用 Columns 中的列名。
+//
+//	schema := sales.Schema{Date: "Date", Product: "Item", Quantity: "Qty", Amount: "Revenue", Region: "Area"}
+//	records, rowErrs, err := schema.LoadFile("export.csv")
+type Schema struct {
+	Date     string
+	Product  string
+	Quantity string
+	Amount   string
+	Region   string
+}
+
+// DefaultSchema 按列名读取 Columns 表头的文件，列的顺序可以不同
+var DefaultSchema = Schema{Date: "日期", Product: "产品", Quantity: "销量", Amount: "销售额", Region: "地区"}
+
+// IsZero 是否为按列顺序读取的零值
+func (s Schema) IsZero() bool {
+	return s == Schema{}
+}
+
+// names 按 Columns 的顺序返回各字段的列名，未设置的字段使用 Columns 中的列名
+func (s Schema) names() []string {
+	names := []string{s.Date, s.Product, s.Quantity, s.Amount, s.Region}
+	for i, name := range names {
+		if name == "" {
+			names[i] = Columns[i]
+		}
+	}
+	return names
+}
+
+// index 在表头中定位各字段，返回按 Columns 顺序排列的列下标
+func (s Schema) index(header []string) ([]int, error) {
+	if s.IsZero() {
+		return []int{0, 1, 2, 3, 4}, nil
+	}
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		// 第一列可能带有 UTF-8 BOM
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if _, seen := positions[name]; !seen {
+			positions[name] = i
+		}
+	}
+	index := make([]int, len(Columns))
+	for i, name := range s.names() {
+		position, ok := positions[name]
+		if !ok {
+			return nil, i18n.Errorf("CSV文件缺少列: %s", name)
+		}
+		index[i] = position
+	}
+	return index, nil
+}
+
+// RowError 输入中无法使用的一行。读取时遇到这样的行不会中断，而是跳过并记录下来。
+type RowError struct {
+	File    string `json:"file,omitempty"` // 文件名，从 io.Reader 读取时为空
+	Line    int    `json:"line"`           // 行号，表头为第1行
+	Message string `json:"message"`        // 已按当前语言翻译
+}
+
+func (e RowError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("%d: %s", e.Line, e.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+}
+
+// Load 从CSV读取销售记录。第一行为表头，之后每行为 日期,产品,销量,销售额,地区。
+// 列数不对或数字无法解析的行跳过并记入 rowErrs；只有CSV本身无法读取时才返回 err。
+//
+//	records, rowErrs, err := sales.Load(strings.NewReader(data))
+func Load(r io.Reader) (records []Record, rowErrs []RowError, err error) {
+	return Schema{}.Load(r)
+}
+
+// Load 按 Schema 读取销售记录，见包级函数 Load
+func (s Schema) Load(r io.Reader) (records []Record, rowErrs []RowError, err error) {
+	rowErrs, err = s.Scan(r, func(line int, record Record) {
+		records = append(records, record)
+	})
+	return records, rowErrs, err
+}
+
+// LoadFile 从CSV文件读取销售记录，行为与 Load 相同，RowError.File 为文件名。
+//
+//	records, rowErrs, err := sales.LoadFile("sales_data.csv")
+//	if err != nil {
+//		return err
+//	}
+//	for _, e := range rowErrs {
+//		log.Printf("跳过 %v", e)
+//	}
+//	result := sales.Aggregate(records, sales.Options{})
+func LoadFile(filename string) (records []Record, rowErrs []RowError, err error) {
+	return Schema{}.LoadFile(filename)
+}
+
+// LoadFile 按 Schema 读取销售数据文件，见包级函数 LoadFile
+func (s Schema) LoadFile(filename string) (records []Record, rowErrs []RowError, err error) {
+	rowErrs, err = s.ScanFile(filename, func(line int, record Record) {
+		records = append(records, record)
+	})
+	return records, rowErrs, err
+}
+
+// ScanFile 逐行读取CSV文件，行为与 Scan 相同，RowError.File 为文件名
+func ScanFile(filename string, visit func(line int, record Record)) ([]RowError, error) {
+	return Schema{}.ScanFile(filename, visit)
+}
+
+// ScanFile 按 Schema 逐行读取CSV文件，见包级函数 ScanFile
+func (s Schema) ScanFile(filename string, visit func(line int, record Record)) ([]RowError, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, i18n.Errorf("无法打开文件: %w", err)
+	}
+	defer file.Close()
+
+	rowErrs, err := s.Scan(file, visit)
+	for i := range rowErrs {
+		rowErrs[i].File = filename
+	}
+	return rowErrs, err
+}
+
+// Scan 逐行读取CSV，对每条能解析的记录调用 visit (line 为行号)，不在内存中保留全部记录。
+// 适合数据量很大、边读边汇总的场景:
+//
+//	result := sales.NewResult(sales.SketchOptions{})
+//	rowErrs, err := sales.Scan(r, func(line int, record sales.Record) {
+//		result.Add(record)
+//	})
+func Scan(r io.Reader, visit func(line int, record Record)) (rowErrs []RowError, err error) {
+	return Schema{}.Scan(r, visit)
+}
+
+// Scan 按 Schema 逐行读取CSV，见包级函数 Scan
+func (s Schema) Scan(r io.Reader, visit func(line int, record Record)) (rowErrs []RowError, err error) {
+	reader := csv.NewReader(r)
+	// 列数不对的行也读出来，由下面逐行检查
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, i18n.Errorf("CSV文件没有数据行")
+		}
+		return nil, i18n.Errorf("读取CSV文件失败: %w", err)
+	}
+	index, err := s.index(header)
+	if err != nil {
+		return nil, err
+	}
+	// 按列名读取时每行应与表头列数相同
+	width := len(Columns)
+	if !s.IsZero() {
+		width = len(header)
+	}
+
+	rowErr := func(line int, format string, args ...any) {
+		rowErrs = append(rowErrs, RowError{Line: line, Message: i18n.Sprintf(format, args...)})
+	}
+
+	rows := 0
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return rowErrs, i18n.Errorf("读取CSV文件失败: %w", err)
+		}
+		rows++
+		line, _ := reader.FieldPos(0)
+
+		if len(row) != width {
+			rowErr(line, "数据格式错误: 需要%d列，实际%d列", width, len(row))
+			continue
+		}
+
+		quantity, err := strconv.Atoi(row[index[2]])
+		if err != nil {
+			rowErr(line, "销量数据错误: %v", err)
+			continue
+		}
+
+		// 超出 float64 范围的值 (如 1e400) 由 ParseFloat 报错，NaN 和 Inf 能解析但不是有效金额
+		amount, err := strconv.ParseFloat(row[index[3]], 64)
+		if err != nil {
+			rowErr(line, "销售额数据错误: %v", err)
+			continue
+		}
+		if !finite(amount) {
+			rowErr(line, "销售额不是有效的数值: %v", amount)
+			continue
+		}
+
+		visit(line, Record{
+			Date:     row[index[0]],
+			Product:  row[index[1]],
+			Quantity: quantity,
+			Amount:   amount,
+			Region:   row[index[4]],
+		})
+	}
+
+	if rows == 0 {
+		return nil, i18n.Errorf("CSV文件没有数据行")
+	}
+	return rowErrs, nil
+}
+
+// Validate 检查一条已解析的记录的内容: 日期格式、空的产品或地区、负数、NaN 和无穷大。
+// 返回按当前语言翻译的问题描述，没有问题时返回nil。
+func Validate(record Record) []string {
+	var problems []string
+	if _, err := time.Parse(DateLayout, record.Date); err != nil {
+		problems = append(problems, i18n.Sprintf("日期格式错误: %q", record.Date))
+	}
+	if strings.TrimSpace(record.Product) == "" {
+		problems = append(problems, i18n.T("产品为空"))
+	}
+	if strings.TrimSpace(record.Region) == "" {
+		problems = append(problems, i18n.T("地区为空"))
+	}
+	if record.Quantity < 0 {
+		problems = append(problems, i18n.Sprintf("销量为负数: %d", record.Quantity))
+	}
+	if !finite(record.Amount) {
+		problems = append(problems, i18n.Sprintf("销售额不是有效的数值: %v", record.Amount))
+	} else if record.Amount < 0 {
+		problems = append(problems, i18n.Sprintf("销售额为负数: %v", record.Amount))
+	}
+	return problems
+}
+
+// finite 是否为有限的数值，NaN 和正负无穷大无法参与汇总，也无法编码为JSON
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// WriteCSV 按 Columns 的表头写出记录，输出可以再由 Load 读回
+func WriteCSV(w io.Writer, records []Record) error {
+	return Schema{}.WriteCSV(w, records)
+}
+
+// WriteCSV 以 Schema 中的列名为表头写出记录，输出可以再由同一 Schema 读回
+func (s Schema) WriteCSV(w io.Writer, records []Record) error {
+	writer := csv.NewWriter(w)
+	writer.Write(s.names())
+	for _, r := range records {
+		writer.Write([]string{
+			r.Date,
+			r.Product,
+			strconv.Itoa(r.Quantity),
+			strconv.FormatFloat(r.Amount, 'f', -1, 64),
+			r.Region,
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,57 @@
+package sales
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestLoadRejectsNonFiniteAmounts(t *testing.T) {
+	for _, amount := range []string{"NaN", "nan", "Inf", "+Inf", "-Inf", "infinity", "1e400", "-1e400"} {
+		t.Run(amount, func(t *testing.T) {
+			data := "日期,产品,销量,销售额,地区\n" +
+				"2025-01-01,手机,1," + amount + ",华东\n" +
+				"2025-01-01,电脑,1,6999,华东\n"
+			records, rowErrs, err := Load(strings.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != 1 || records[0].Product != "电脑" {
+				t.Errorf("应只读出第3行，得到 %+v", records)
+			}
+			if len(rowErrs) != 1 || rowErrs[0].Line != 2 {
+				t.Errorf("第2行应被跳过，得到 %+v", rowErrs)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := Record{Date: "2025-01-01", Product: "手机", Quantity: 1, Amount: 2999, Region: "华东"}
+	tests := []struct {
+		name   string
+		modify func(r *Record)
+		want   int // 问题数
+	}{
+		{"有效", func(r *Record) {}, 0},
+		{"金额为0", func(r *Record) { r.Amount = 0 }, 0},
+		{"日期格式", func(r *Record) { r.Date = "2025/01/01" }, 1},
+		{"产品为空", func(r *Record) { r.Product = " " }, 1},
+		{"地区为空", func(r *Record) { r.Region = "" }, 1},
+		{"销量为负", func(r *Record) { r.Quantity = -1 }, 1},
+		{"金额为负", func(r *Record) { r.Amount = -1 }, 1},
+		{"NaN", func(r *Record) { r.Amount = math.NaN() }, 1},
+		{"正无穷", func(r *Record) { r.Amount = math.Inf(1) }, 1},
+		{"负无穷", func(r *Record) { r.Amount = math.Inf(-1) }, 1},
+		{"多个问题", func(r *Record) { r.Date, r.Amount = "", math.NaN() }, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := valid
+			tt.modify(&record)
+			if problems := Validate(record); len(problems) != tt.want {
+				t.Errorf("Validate(%+v) = %q，应有 %d 个问题", record, problems, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package sales
+
+// Record 一条销售记录
+type Record struct {
+	Date     string  `csv:"日期" json:"date"`
+	Product  string  `csv:"产品" json:"product"`
+	Quantity int     `csv:"销量" json:"quantity"`
+	Amount   float64 `csv:"销售额" json:"amount"`
+	Region   string  `csv:"地区" json:"region"`
+}
@@ -0,0 +1,211 @@
+package sales
+
+import (
+	"fmt"
+
+	"sales-analyzer/sketch"
+)
+
+// SketchOptions 近似统计的误差上限，为0表示不启用对应的度量
+type SketchOptions struct {
+	DistinctError float64 // 去重计数的相对误差 (HyperLogLog)
+	QuantileError float64 // 分位数的秩误差 (KLL)
+}
+
+// Enabled 是否启用了任一近似度量
+func (o SketchOptions) Enabled() bool {
+	return o.DistinctError > 0 || o.QuantileError > 0
+}
+
+// Sketches 基于草图的近似度量，可随分片部分汇总一起合并。
+// 未启用的度量为nil。
+type Sketches struct {
+	Products *sketch.HyperLogLog // 不同产品数
+	Regions  *sketch.HyperLogLog // 不同地区数
+	Dates    *sketch.HyperLogLog // 不同销售日数
+	// RegionProducts 各地区销售过的不同产品数
+	RegionProducts map[string]*sketch.HyperLogLog
+
+	Amount   *sketch.KLL // 订单金额分布
+	Quantity *sketch.KLL // 订单销量分布
+	// ProductAmount 和 RegionAmount 分别为各产品、各地区的订单金额分布
+	ProductAmount map[string]*sketch.KLL
+	RegionAmount  map[string]*sketch.KLL
+
+	opts SketchOptions
+}
+
+func newSketches(opts SketchOptions) *Sketches {
+	s := &Sketches{opts: opts}
+	if opts.DistinctError > 0 {
+		s.Products = sketch.NewHyperLogLog(opts.DistinctError)
+		s.Regions = sketch.NewHyperLogLog(opts.DistinctError)
+		s.Dates = sketch.NewHyperLogLog(opts.DistinctError)
+		s.RegionProducts = make(map[string]*sketch.HyperLogLog)
+	}
+	if opts.QuantileError > 0 {
+		s.Amount = sketch.NewKLL(opts.QuantileError)
+		s.Quantity = sketch.NewKLL(opts.QuantileError)
+		s.ProductAmount = make(map[string]*sketch.KLL)
+		s.RegionAmount = make(map[string]*sketch.KLL)
+	}
+	return s
+}
+
+func (s *Sketches) add(record Record) {
+	if s.opts.DistinctError > 0 {
+		s.Products.Add(record.Product)
+		s.Regions.Add(record.Region)
+		s.Dates.Add(record.Date)
+		s.distinct(s.RegionProducts, record.Region).Add(record.Product)
+	}
+	if s.opts.QuantileError > 0 {
+		s.Amount.Add(record.Amount)
+		s.Quantity.Add(float64(record.Quantity))
+		s.quantiles(s.ProductAmount, record.Product).Add(record.Amount)
+		s.quantiles(s.RegionAmount, record.Region).Add(record.Amount)
+	}
+}
+
+// merge 合并另一组草图。两组草图的误差设置必须相同，否则无法合并，返回错误。
+func (s *Sketches) merge(other *Sketches) error {
+	if s.opts != other.opts {
+		return fmt.Errorf("草图的误差设置不一致: %+v != %+v", s.opts, other.opts)
+	}
+	if s.opts.DistinctError > 0 {
+		for _, pair := range [][2]*sketch.HyperLogLog{
+			{s.Products, other.Products},
+			{s.Regions, other.Regions},
+			{s.Dates, other.Dates},
+		} {
+			if err := pair[0].Merge(pair[1]); err != nil {
+				return err
+			}
+		}
+		for _, key := range sortedKeys(other.RegionProducts) {
+			if err := s.distinct(s.RegionProducts, key).Merge(other.RegionProducts[key]); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	}
+	if s.opts.QuantileError > 0 {
+		if err := s.Amount.Merge(other.Amount); err != nil {
+			return err
+		}
+		if err := s.Quantity.Merge(other.Quantity); err != nil {
+			return err
+		}
+		for _, key := range sortedKeys(other.ProductAmount) {
+			if err := s.quantiles(s.ProductAmount, key).Merge(other.ProductAmount[key]); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+		for _, key := range sortedKeys(other.RegionAmount) {
+			if err := s.quantiles(s.RegionAmount, key).Merge(other.RegionAmount[key]); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Sketches) distinct(groups map[string]*sketch.HyperLogLog, key string) *sketch.HyperLogLog {
+	h, exists := groups[key]
+	if !exists {
+		h = sketch.NewHyperLogLog(s.opts.DistinctError)
+		groups[key] = h
+	}
+	return h
+}
+
+func (s *Sketches) quantiles(groups map[string]*sketch.KLL, key string) *sketch.KLL {
+	q, exists := groups[key]
+	if !exists {
+		q = sketch.NewKLL(s.opts.QuantileError)
+		groups[key] = q
+	}
+	return q
+}
+
+// 近似统计结果中的维度。取值与语言无关，可以作为JSON的键；显示名称由 report 包翻译。
+const (
+	DimensionAll           = "all"            // 全部订单
+	DimensionProduct       = "product"        // 产品
+	DimensionRegion        = "region"         // 地区
+	DimensionDate          = "date"           // 销售日
+	DimensionRegionProduct = "region_product" // 某个地区的在售产品
+)
+
+// DistinctCount 一个维度的近似去重计数
+type DistinctCount struct {
+	Dimension     string  `json:"dimension"`        // product/region/date/region_product
+	Region        string  `json:"region,omitempty"` // Dimension 为 region_product 时的地区
+	Count         uint64  `json:"count"`
+	RelativeError float64 `json:"relative_error"`
+}
+
+// QuantileSummary 一组订单金额的近似分位数
+type QuantileSummary struct {
+	Dimension string  `json:"dimension"`         // all 为全部订单，product/region 为一个产品或地区的订单
+	Product   string  `json:"product,omitempty"` // Dimension 为 product 时的产品
+	Region    string  `json:"region,omitempty"`  // Dimension 为 region 时的地区
+	P50       float64 `json:"p50"`
+	P90       float64 `json:"p90"`
+	P99       float64 `json:"p99"`
+	RankError float64 `json:"rank_error"`
+}
+
+// SketchSummary 从草图中读出的近似统计结果。没有数据的草图不产生分位数，
+// 没有任何订单时 Amount 为空，QuantityMedian 和 QuantityP90 为0。
+type SketchSummary struct {
+	Distinct       []DistinctCount   `json:"distinct,omitempty"`
+	Amount         []QuantileSummary `json:"amount,omitempty"`
+	QuantityMedian float64           `json:"quantity_median,omitempty"`
+	QuantityP90    float64           `json:"quantity_p90,omitempty"`
+}
+
+// Summarize 读出各草图的估计值。
+// 去重计数依次为产品、地区、销售日和各地区在售产品；
+// 分位数依次为全部订单、各产品和各地区。
+func (s *Sketches) Summarize() SketchSummary {
+	var summary SketchSummary
+
+	if s.opts.DistinctError > 0 {
+		distinct := func(d DistinctCount, h *sketch.HyperLogLog) DistinctCount {
+			d.Count, d.RelativeError = h.Count(), h.RelativeError()
+			return d
+		}
+		summary.Distinct = append(summary.Distinct,
+			distinct(DistinctCount{Dimension: DimensionProduct}, s.Products),
+			distinct(DistinctCount{Dimension: DimensionRegion}, s.Regions),
+			distinct(DistinctCount{Dimension: DimensionDate}, s.Dates),
+		)
+		for _, region := range sortedKeys(s.RegionProducts) {
+			summary.Distinct = append(summary.Distinct,
+				distinct(DistinctCount{Dimension: DimensionRegionProduct, Region: region}, s.RegionProducts[region]))
+		}
+	}
+
+	if s.opts.QuantileError > 0 {
+		// 空草图的分位数是 NaN，既没有意义也无法输出为JSON，直接跳过
+		quantiles := func(q QuantileSummary, k *sketch.KLL) {
+			if k.Count() > 0 {
+				q.P50, q.P90, q.P99, q.RankError = k.Quantile(0.5), k.Quantile(0.9), k.Quantile(0.99), k.RankError()
+				summary.Amount = append(summary.Amount, q)
+			}
+		}
+		quantiles(QuantileSummary{Dimension: DimensionAll}, s.Amount)
+		for _, product := range sortedKeys(s.ProductAmount) {
+			quantiles(QuantileSummary{Dimension: DimensionProduct, Product: product}, s.ProductAmount[product])
+		}
+		for _, region := range sortedKeys(s.RegionAmount) {
+			quantiles(QuantileSummary{Dimension: DimensionRegion, Region: region}, s.RegionAmount[region])
+		}
+		if s.Quantity.Count() > 0 {
+			summary.QuantityMedian = s.Quantity.Quantile(0.5)
+			summary.QuantityP90 = s.Quantity.Quantile(0.9)
+		}
+	}
+
+	return summary
+}
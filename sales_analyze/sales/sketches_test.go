@@ -0,0 +1,104 @@
+package sales
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestResultMergeSketchMismatch(t *testing.T) {
+	record := Record{Date: "2025-01-01", Product: "手机", Quantity: 1, Amount: 2999, Region: "华东"}
+	tests := []struct {
+		name        string
+		left, right SketchOptions
+	}{
+		{"精度不同", SketchOptions{DistinctError: 0.01}, SketchOptions{DistinctError: 0.1}},
+		{"秩误差不同", SketchOptions{QuantileError: 0.01}, SketchOptions{QuantileError: 0.05}},
+		{"只有一方启用", SketchOptions{DistinctError: 0.01}, SketchOptions{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left, right := NewResult(tt.left), NewResult(tt.right)
+			left.Add(record)
+			right.Add(record)
+			if err := left.Merge(right); err == nil {
+				t.Fatal("草图设置不同的汇总合并时应返回错误")
+			}
+			if left.Overall.Count != 1 {
+				t.Errorf("合并失败后订单数为 %d，应保持 1", left.Overall.Count)
+			}
+		})
+	}
+}
+
+func TestResultMergeSketches(t *testing.T) {
+	opts := SketchOptions{DistinctError: 0.01, QuantileError: 0.01}
+	left, right := NewResult(opts), NewResult(opts)
+	left.Add(Record{Date: "2025-01-01", Product: "手机", Quantity: 1, Amount: 2999, Region: "华东"})
+	right.Add(Record{Date: "2025-01-02", Product: "电脑", Quantity: 1, Amount: 6999, Region: "华东"})
+	if err := left.Merge(right); err != nil {
+		t.Fatal(err)
+	}
+	if got := left.Sketches.Products.Count(); got != 2 {
+		t.Errorf("合并后不同产品数为 %d，应为 2", got)
+	}
+	if got := left.Sketches.Amount.Count(); got != 2 {
+		t.Errorf("合并后订单金额个数为 %d，应为 2", got)
+	}
+}
+
+// TestSummarizeEmpty 没有订单时不应产生 NaN 分位数，结果可以输出为JSON
+func TestSummarizeEmpty(t *testing.T) {
+	result := NewResult(SketchOptions{DistinctError: 0.01, QuantileError: 0.01})
+	summary := result.Sketches.Summarize()
+	if len(summary.Amount) != 0 {
+		t.Errorf("没有订单时 Amount = %+v，应为空", summary.Amount)
+	}
+	if summary.QuantityMedian != 0 || summary.QuantityP90 != 0 {
+		t.Errorf("没有订单时销量分位数为 %v/%v，应为0", summary.QuantityMedian, summary.QuantityP90)
+	}
+	for _, d := range summary.Distinct {
+		if d.Count != 0 {
+			t.Errorf("没有订单时 %s 的去重数为 %d，应为0", d.Dimension, d.Count)
+		}
+	}
+	if _, err := json.Marshal(summary); err != nil {
+		t.Errorf("json.Marshal: %v", err)
+	}
+}
+
+// TestSummarizeDimensions 结果中只有与语言无关的维度和原始名称
+func TestSummarizeDimensions(t *testing.T) {
+	result := NewResult(SketchOptions{DistinctError: 0.01, QuantileError: 0.01})
+	result.Add(Record{Date: "2025-01-01", Product: "手机", Quantity: 1, Amount: 2999, Region: "华东"})
+	summary := result.Sketches.Summarize()
+
+	wantDistinct := []DistinctCount{
+		{Dimension: DimensionProduct},
+		{Dimension: DimensionRegion},
+		{Dimension: DimensionDate},
+		{Dimension: DimensionRegionProduct, Region: "华东"},
+	}
+	if len(summary.Distinct) != len(wantDistinct) {
+		t.Fatalf("Distinct 有 %d 项，应为 %d 项", len(summary.Distinct), len(wantDistinct))
+	}
+	for i, want := range wantDistinct {
+		if got := summary.Distinct[i]; got.Dimension != want.Dimension || got.Region != want.Region {
+			t.Errorf("Distinct[%d] = %s/%s，应为 %s/%s", i, got.Dimension, got.Region, want.Dimension, want.Region)
+		}
+	}
+
+	wantAmount := []QuantileSummary{
+		{Dimension: DimensionAll},
+		{Dimension: DimensionProduct, Product: "手机"},
+		{Dimension: DimensionRegion, Region: "华东"},
+	}
+	if len(summary.Amount) != len(wantAmount) {
+		t.Fatalf("Amount 有 %d 项，应为 %d 项", len(summary.Amount), len(wantAmount))
+	}
+	for i, want := range wantAmount {
+		got := summary.Amount[i]
+		if got.Dimension != want.Dimension || got.Product != want.Product || got.Region != want.Region {
+			t.Errorf("Amount[%d] = %s/%s/%s，应为 %s/%s/%s", i, got.Dimension, got.Product, got.Region, want.Dimension, want.Product, want.Region)
+		}
+	}
+}
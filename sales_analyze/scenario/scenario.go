@@ -0,0 +1,168 @@
+// Package scenario 实现what-if情景模拟: 从情景文件读取对单价、销量或销售额的调整，
+// 应用到销售记录上。与基准的对比见 sales.Compare。
+package scenario
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"time"
+
+	"sales-analyzer/i18n"
+	"sales-analyzer/sales"
+)
+
+// 可调整的字段
+const (
+	FieldPrice    = "price"    // 单价，即 销售额/销量
+	FieldQuantity = "quantity" // 销量，单价不变，销售额随之变化
+	FieldAmount   = "amount"   // 销售额，销量不变，单价随之变化
+)
+
+// 调整方式
+const (
+	OpMultiply = "multiply" // 乘以 Value，例如 0.9 表示下降10%
+	OpAdd      = "add"      // 加上 Value，可以为负数
+)
+
+// Filter 调整的适用范围，空字段表示不限
+type Filter struct {
+	Product string `json:"product,omitempty"`
+	Region  string `json:"region,omitempty"`
+	From    string `json:"from,omitempty"` // 起始日期 (含)，格式 2006-01-02
+	To      string `json:"to,omitempty"`   // 结束日期 (含)
+}
+
+// Match 判断记录是否在适用范围内
+func (f Filter) Match(record sales.Record) bool {
+	if f.Product != "" && record.Product != f.Product {
+		return false
+	}
+	if f.Region != "" && record.Region != f.Region {
+		return false
+	}
+	if f.From != "" && record.Date < f.From {
+		return false
+	}
+	if f.To != "" && record.Date > f.To {
+		return false
+	}
+	return true
+}
+
+// Adjustment 一项调整
+type Adjustment struct {
+	Field  string  `json:"field"`
+	Op     string  `json:"op"`
+	Value  float64 `json:"value"`
+	Filter Filter  `json:"filter"`
+}
+
+// Scenario 一个情景，调整按顺序依次应用
+type Scenario struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Adjustments []Adjustment `json:"adjustments"`
+}
+
+// Load 从JSON文件加载情景并校验
+func Load(filename string) (*Scenario, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, i18n.Errorf("无法打开情景文件: %w", err)
+	}
+
+	var s Scenario
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, i18n.Errorf("解析情景文件失败: %w", err)
+	}
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Validate 校验调整项的字段、方式和日期范围
+func (s *Scenario) Validate() error {
+	if len(s.Adjustments) == 0 {
+		return i18n.Errorf("情景 %q 没有任何调整", s.Name)
+	}
+
+	for i, adj := range s.Adjustments {
+		switch adj.Field {
+		case FieldPrice, FieldQuantity, FieldAmount:
+		default:
+			return i18n.Errorf("第%d项调整的字段无效: %q (可选 price/quantity/amount)", i+1, adj.Field)
+		}
+
+		switch adj.Op {
+		case OpMultiply, OpAdd:
+		default:
+			return i18n.Errorf("第%d项调整的方式无效: %q (可选 multiply/add)", i+1, adj.Op)
+		}
+
+		for _, date := range []string{adj.Filter.From, adj.Filter.To} {
+			if date == "" {
+				continue
+			}
+			if _, err := time.Parse(sales.DateLayout, date); err != nil {
+				return i18n.Errorf("第%d项调整的日期格式错误: %q", i+1, date)
+			}
+		}
+	}
+	return nil
+}
+
+// Apply 返回应用情景后的新记录，原记录不变，同时返回受影响的记录数。
+// 单价由 销售额/销量 推得；调整后的销量立即四舍五入为整数，销售额按取整后的销量计算，
+// 单价因此保持不变。销售额最后保留两位小数。
+func (s *Scenario) Apply(records []sales.Record) ([]sales.Record, int) {
+	result := make([]sales.Record, len(records))
+	affected := 0
+
+	for i, record := range records {
+		quantity := float64(record.Quantity)
+		amount := record.Amount
+		changed := false
+
+		for _, adj := range s.Adjustments {
+			if !adj.Filter.Match(record) {
+				continue
+			}
+			changed = true
+
+			var price float64
+			if quantity != 0 {
+				price = amount / quantity
+			}
+
+			switch adj.Field {
+			case FieldPrice:
+				price = adj.apply(price)
+				amount = price * quantity
+			case FieldQuantity:
+				quantity = math.Round(math.Max(0, adj.apply(quantity)))
+				amount = price * quantity
+			case FieldAmount:
+				amount = adj.apply(amount)
+			}
+		}
+
+		if changed {
+			affected++
+			record.Quantity = int(quantity)
+			record.Amount = math.Round(amount*100) / 100
+		}
+		result[i] = record
+	}
+
+	return result, affected
+}
+
+func (a Adjustment) apply(value float64) float64 {
+	if a.Op == OpAdd {
+		return value + a.Value
+	}
+	return value * a.Value
+}
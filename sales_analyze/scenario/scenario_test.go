@@ -0,0 +1,158 @@
+package scenario
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sales-analyzer/sales"
+)
+
+func TestApply(t *testing.T) {
+	records := []sales.Record{
+		{Date: "2025-01-01", Product: "手机", Quantity: 3, Amount: 9000, Region: "华东"},
+		{Date: "2025-01-02", Product: "电脑", Quantity: 2, Amount: 12000, Region: "华南"},
+		{Date: "2025-01-03", Product: "手机", Quantity: 1, Amount: 3000, Region: "华南"},
+	}
+	phone := Filter{Product: "手机"}
+
+	tests := []struct {
+		name        string
+		adjustments []Adjustment
+		want        []sales.Record
+		affected    int
+	}{
+		{
+			name:        "单价下降10%",
+			adjustments: []Adjustment{{Field: FieldPrice, Op: OpMultiply, Value: 0.9, Filter: phone}},
+			want: []sales.Record{
+				{Date: "2025-01-01", Product: "手机", Quantity: 3, Amount: 8100, Region: "华东"},
+				records[1],
+				{Date: "2025-01-03", Product: "手机", Quantity: 1, Amount: 2700, Region: "华南"},
+			},
+			affected: 2,
+		},
+		{
+			name:        "单价加100",
+			adjustments: []Adjustment{{Field: FieldPrice, Op: OpAdd, Value: 100, Filter: Filter{Region: "华南"}}},
+			want: []sales.Record{
+				records[0],
+				{Date: "2025-01-02", Product: "电脑", Quantity: 2, Amount: 12200, Region: "华南"},
+				{Date: "2025-01-03", Product: "手机", Quantity: 1, Amount: 3100, Region: "华南"},
+			},
+			affected: 2,
+		},
+		{
+			name:        "销量翻倍，单价不变",
+			adjustments: []Adjustment{{Field: FieldQuantity, Op: OpMultiply, Value: 2, Filter: Filter{Product: "电脑"}}},
+			want: []sales.Record{
+				records[0],
+				{Date: "2025-01-02", Product: "电脑", Quantity: 4, Amount: 24000, Region: "华南"},
+				records[2],
+			},
+			affected: 1,
+		},
+		{
+			// 3件增加10%为3.3件，取整为3件，销售额不应随之增加
+			name:        "销量取整后计算销售额",
+			adjustments: []Adjustment{{Field: FieldQuantity, Op: OpMultiply, Value: 1.1, Filter: Filter{From: "2025-01-01", To: "2025-01-01"}}},
+			want:        records,
+			affected:    1,
+		},
+		{
+			name:        "销量减少到负数时为0",
+			adjustments: []Adjustment{{Field: FieldQuantity, Op: OpAdd, Value: -5, Filter: phone}},
+			want: []sales.Record{
+				{Date: "2025-01-01", Product: "手机", Quantity: 0, Amount: 0, Region: "华东"},
+				records[1],
+				{Date: "2025-01-03", Product: "手机", Quantity: 0, Amount: 0, Region: "华南"},
+			},
+			affected: 2,
+		},
+		{
+			name:        "销售额调整，销量不变",
+			adjustments: []Adjustment{{Field: FieldAmount, Op: OpMultiply, Value: 1.0 / 3}},
+			want: []sales.Record{
+				{Date: "2025-01-01", Product: "手机", Quantity: 3, Amount: 3000, Region: "华东"},
+				{Date: "2025-01-02", Product: "电脑", Quantity: 2, Amount: 4000, Region: "华南"},
+				{Date: "2025-01-03", Product: "手机", Quantity: 1, Amount: 1000, Region: "华南"},
+			},
+			affected: 3,
+		},
+		{
+			// 先降价再增加销量，第二项按降价后的单价计算
+			name: "依次应用多项调整",
+			adjustments: []Adjustment{
+				{Field: FieldPrice, Op: OpMultiply, Value: 0.5, Filter: phone},
+				{Field: FieldQuantity, Op: OpAdd, Value: 1, Filter: phone},
+			},
+			want: []sales.Record{
+				{Date: "2025-01-01", Product: "手机", Quantity: 4, Amount: 6000, Region: "华东"},
+				records[1],
+				{Date: "2025-01-03", Product: "手机", Quantity: 2, Amount: 3000, Region: "华南"},
+			},
+			affected: 2,
+		},
+		{
+			name:        "没有匹配的记录",
+			adjustments: []Adjustment{{Field: FieldPrice, Op: OpMultiply, Value: 2, Filter: Filter{Region: "华北"}}},
+			want:        records,
+			affected:    0,
+		},
+		{
+			name:        "日期范围",
+			adjustments: []Adjustment{{Field: FieldAmount, Op: OpAdd, Value: 0.005, Filter: Filter{From: "2025-01-02"}}},
+			want: []sales.Record{
+				records[0],
+				{Date: "2025-01-02", Product: "电脑", Quantity: 2, Amount: 12000.01, Region: "华南"},
+				{Date: "2025-01-03", Product: "手机", Quantity: 1, Amount: 3000.01, Region: "华南"},
+			},
+			affected: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := append([]sales.Record(nil), records...)
+			s := &Scenario{Name: tt.name, Adjustments: tt.adjustments}
+			got, affected := s.Apply(records)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %+v\n应为 %+v", got, tt.want)
+			}
+			if affected != tt.affected {
+				t.Errorf("受影响的记录数 = %d，应为 %d", affected, tt.affected)
+			}
+			if !reflect.DeepEqual(records, original) {
+				t.Error("Apply 修改了原记录")
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := Adjustment{Field: FieldPrice, Op: OpMultiply, Value: 0.9}
+	tests := []struct {
+		name        string
+		adjustments []Adjustment
+		err         string // 错误信息应包含的内容，为空时不应出错
+	}{
+		{"有效", []Adjustment{valid, {Field: FieldAmount, Op: OpAdd, Value: -100, Filter: Filter{From: "2025-01-01", To: "2025-01-31"}}}, ""},
+		{"没有调整", nil, "没有任何调整"},
+		{"字段无效", []Adjustment{valid, {Field: "cost", Op: OpAdd}}, "第2项调整的字段无效"},
+		{"方式无效", []Adjustment{{Field: FieldQuantity, Op: "divide"}}, "第1项调整的方式无效"},
+		{"日期格式错误", []Adjustment{{Field: FieldPrice, Op: OpAdd, Filter: Filter{To: "2025/01/31"}}}, "日期格式错误"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Scenario{Name: "测试", Adjustments: tt.adjustments}).Validate()
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("Validate() 错误: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Validate() 错误 = %v，应包含 %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"io/fs"
+	"net/http"
+
+	"sales-analyzer/i18n"
+)
+
+// web 看板页面和静态文件，编译进程序，运行时不需要任何额外文件
+//
+//go:embed web
+var web embed.FS
+
+var dashboardTemplate = template.Must(template.New("index.html").Funcs(template.FuncMap{
+	"t": i18n.T,
+}).ParseFS(web, "web/index.html"))
+
+// dashboardPage 页面中的文字按服务的语言区域翻译，数字格式也传给页面脚本
+type dashboardPage struct {
+	Lang     string
+	Currency string
+	Decimal  string
+	Group    string
+}
+
+// dashboard 看板首页。页面本身只有布局，数据由脚本从 /api/ 接口读取。
+// 页面先渲染到缓冲区，模板执行失败时返回 500 而不是半个页面。
+func (s *Server) dashboard(w http.ResponseWriter, r *http.Request) {
+	l := i18n.Current()
+	var page bytes.Buffer
+	err := dashboardTemplate.Execute(&page, dashboardPage{
+		Lang:     l.Tag,
+		Currency: l.Currency,
+		Decimal:  l.Decimal,
+		Group:    l.Group,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	page.WriteTo(w)
+}
+
+// staticFiles 看板的脚本和样式
+func staticFiles() http.Handler {
+	static, err := fs.Sub(web, "web/static")
+	if err != nil {
+		panic(err)
+	}
+	return http.StripPrefix("/static/", http.FileServerFS(static))
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDashboard(t *testing.T) {
+	w := httptest.NewRecorder()
+	new(Server).dashboard(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("状态码 = %d，应为 %d", w.Code, http.StatusOK)
+	}
+	if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/html") {
+		t.Errorf("Content-Type = %q，应为 text/html", got)
+	}
+	if !strings.Contains(w.Body.String(), "</html>") {
+		t.Errorf("页面不完整:\n%s", w.Body.String())
+	}
+}
+
+// TestDashboardTemplateError 模板执行失败时返回 500，不输出已渲染的部分页面
+func TestDashboardTemplateError(t *testing.T) {
+	saved := dashboardTemplate
+	defer func() { dashboardTemplate = saved }()
+	dashboardTemplate = template.Must(template.New("index.html").Parse(`<html lang="{{.Lang}}">{{.Missing}}</html>`))
+
+	w := httptest.NewRecorder()
+	new(Server).dashboard(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("状态码 = %d，应为 %d", w.Code, http.StatusInternalServerError)
+	}
+	if strings.Contains(w.Body.String(), "<html") {
+		t.Errorf("不应输出部分页面:\n%s", w.Body.String())
+	}
+}
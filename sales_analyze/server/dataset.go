@@ -0,0 +1,112 @@
+package server
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"sales-analyzer/sales"
+	"sales-analyzer/watch"
+)
+
+// Dataset 分析服务使用的数据: 从磁盘读取的销售记录。
+// 输入文件被修改、新增或删除后，下一次请求时自动重新读取，没有变化的文件不再重复读取。
+type Dataset struct {
+	Paths  []string     // CSV文件或目录，目录中的 *.csv 全部读取 (见 watch.Inputs)
+	Schema sales.Schema // CSV表头中各字段的列名
+	Filter sales.Filter // 读取后先按此条件过滤，请求中的筛选条件在此基础上进一步缩小
+	// UploadDir 保存上传数据的目录，其中的 *.csv 与 Paths 一起读取；为空时不接受上传
+	UploadDir string
+
+	// CheckInterval 两次检查文件是否变化的最短间隔，0 表示每次请求都检查
+	CheckInterval time.Duration
+	// OnReload 首次读取之后，每次因文件变化重新读取时调用；读取失败时 err 不为nil，
+	// snap 为继续使用的旧数据。调用时持有内部锁，不能在其中调用 Current。
+	OnReload func(snap *Snapshot, err error)
+
+	mu      sync.Mutex
+	inputs  *watch.Inputs // 按文件缓存，只重新读取变化的文件
+	current *Snapshot
+	checked time.Time
+
+	uploadMu sync.Mutex // 串行处理上传，同一条记录只保存一次
+}
+
+// Snapshot 某一时刻读取的全部数据，创建后不再修改，可以在并发的请求间共享
+type Snapshot struct {
+	Files    []string
+	Records  []sales.Record
+	Skipped  []sales.RowError // 读取时跳过的数据行
+	Version  string           // 文件名、大小和修改时间的摘要，文件变化时随之改变
+	LoadedAt time.Time
+
+	all []sales.Record // 按 Dataset.Filter 过滤前的全部记录，上传时据此判断重复的行
+}
+
+// Current 返回当前的数据。距上次检查超过 CheckInterval 时先检查文件，有变化则重新读取。
+// 重新读取失败时同时返回错误和之前的数据 (首次读取失败时为nil)，调用方可以继续使用旧数据。
+func (d *Dataset) Current() (*Snapshot, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	if d.current != nil && now.Sub(d.checked) < d.CheckInterval {
+		return d.current, nil
+	}
+
+	snap, err := d.reload()
+	if err != nil {
+		// 文件可能正在写入，下次请求时重试
+		if d.current != nil && d.OnReload != nil {
+			d.OnReload(d.current, err)
+		}
+		return d.current, err
+	}
+	d.checked = now
+	if snap != d.current {
+		first := d.current == nil
+		d.current = snap
+		if !first && d.OnReload != nil {
+			d.OnReload(snap, nil)
+		}
+	}
+	return d.current, nil
+}
+
+// reload 文件没有变化时返回当前数据，否则重新读取变化的文件
+func (d *Dataset) reload() (*Snapshot, error) {
+	if d.inputs == nil {
+		paths := d.Paths
+		if d.UploadDir != "" {
+			paths = append(slices.Clip(paths), d.UploadDir)
+		}
+		d.inputs = &watch.Inputs{Paths: paths, Schema: d.Schema}
+	}
+	version, err := d.inputs.Version()
+	if err != nil {
+		return nil, err
+	}
+	if d.current != nil && d.current.Version == version {
+		return d.current, nil
+	}
+
+	loaded, err := d.inputs.Load()
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{
+		Files:    loaded.Files,
+		Records:  d.Filter.Apply(loaded.Records),
+		Skipped:  loaded.Skipped,
+		Version:  loaded.Version,
+		LoadedAt: time.Now(),
+		all:      loaded.Records,
+	}, nil
+}
+
+// invalidate 下一次 Current 时立即检查文件，不等 CheckInterval
+func (d *Dataset) invalidate() {
+	d.mu.Lock()
+	d.checked = time.Time{}
+	d.mu.Unlock()
+}
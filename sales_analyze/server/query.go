@@ -0,0 +1,143 @@
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"sales-analyzer/diff"
+	"sales-analyzer/i18n"
+	"sales-analyzer/sales"
+)
+
+// 日期分组的粒度
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"  // ISO周，如 2025-W03
+	IntervalMonth = "month" // 如 2025-01
+)
+
+// Intervals 可用的日期分组粒度
+var Intervals = []string{IntervalDay, IntervalWeek, IntervalMonth}
+
+// query 请求中的查询参数:
+//
+//	product, region  只统计这些产品/地区，可以重复指定或用逗号分隔
+//	from, to         日期区间 (含)，格式 2006-01-02
+//	interval         日期的分组粒度: day (默认)、week、month
+//	by               /api/groups 的分组字段: date、product、region，逗号分隔
+type query struct {
+	filter   sales.Filter
+	interval string
+	by       []string
+}
+
+func parseQuery(values url.Values) (query, error) {
+	q := query{
+		filter: sales.Filter{
+			Products: list(values["product"]),
+			Regions:  list(values["region"]),
+			From:     values.Get("from"),
+			To:       values.Get("to"),
+		},
+		interval: values.Get("interval"),
+		by:       list(values["by"]),
+	}
+
+	for _, date := range []string{q.filter.From, q.filter.To} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(sales.DateLayout, date); err != nil {
+			return query{}, i18n.Errorf("日期格式错误: %q", date)
+		}
+	}
+	if q.filter.From != "" && q.filter.To != "" && q.filter.From > q.filter.To {
+		return query{}, i18n.Errorf("起始日期晚于结束日期: %s > %s", q.filter.From, q.filter.To)
+	}
+
+	switch q.interval {
+	case "":
+		q.interval = IntervalDay
+	case IntervalDay, IntervalWeek, IntervalMonth:
+	default:
+		return query{}, i18n.Errorf("无效的日期分组: %q (可选 %s)", q.interval, strings.Join(Intervals, "/"))
+	}
+
+	if len(q.by) > 0 {
+		by, err := diff.ParseKey(strings.Join(q.by, ","))
+		if err != nil {
+			return query{}, err
+		}
+		q.by = by
+	}
+	return q, nil
+}
+
+// list 合并重复指定、逗号分隔的参数值
+func list(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				result = append(result, v)
+			}
+		}
+	}
+	return result
+}
+
+// String 规范化的查询，写法不同但含义相同的请求得到相同的结果，用于缓存和ETag
+func (q query) String() string {
+	values := url.Values{}
+	if len(q.filter.Products) > 0 {
+		values.Set("product", strings.Join(q.filter.Products, ","))
+	}
+	if len(q.filter.Regions) > 0 {
+		values.Set("region", strings.Join(q.filter.Regions, ","))
+	}
+	if q.filter.From != "" {
+		values.Set("from", q.filter.From)
+	}
+	if q.filter.To != "" {
+		values.Set("to", q.filter.To)
+	}
+	if q.interval != IntervalDay {
+		values.Set("interval", q.interval)
+	}
+	if len(q.by) > 0 {
+		values.Set("by", strings.Join(q.by, ","))
+	}
+	return values.Encode()
+}
+
+// records 按查询筛选记录，并把日期换成所在的周或月
+func (q query) records(records []sales.Record) []sales.Record {
+	records = q.filter.Apply(records)
+	if q.interval == IntervalDay {
+		return records
+	}
+	grouped := make([]sales.Record, len(records))
+	for i, record := range records {
+		record.Date = bucket(record.Date, q.interval)
+		grouped[i] = record
+	}
+	return grouped
+}
+
+// bucket 日期所在的周或月，排序后与时间顺序一致。无法解析的日期原样返回。
+func bucket(date, interval string) string {
+	t, err := time.Parse(sales.DateLayout, date)
+	if err != nil {
+		return date
+	}
+	switch interval {
+	case IntervalWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case IntervalMonth:
+		return t.Format("2006-01")
+	}
+	return date
+}
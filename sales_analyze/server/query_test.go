@@ -0,0 +1,96 @@
+package server
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"sales-analyzer/sales"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    query
+		wantErr bool
+	}{
+		{"默认", "", query{interval: IntervalDay}, false},
+		{"逗号与重复合并", "product=手机, 电脑&product=平板&region=华东,",
+			query{filter: sales.Filter{Products: []string{"手机", "电脑", "平板"}, Regions: []string{"华东"}}, interval: IntervalDay}, false},
+		{"日期区间", "from=2025-01-01&to=2025-01-31&interval=week",
+			query{filter: sales.Filter{From: "2025-01-01", To: "2025-01-31"}, interval: IntervalWeek}, false},
+		{"同一天", "from=2025-01-01&to=2025-01-01",
+			query{filter: sales.Filter{From: "2025-01-01", To: "2025-01-01"}, interval: IntervalDay}, false},
+		{"分组字段", "by=region&by=date&interval=month", query{interval: IntervalMonth, by: []string{"region", "date"}}, false},
+		{"日期格式错误", "from=2025/01/01", query{}, true},
+		{"日期不存在", "to=2025-02-30", query{}, true},
+		{"起止颠倒", "from=2025-02-01&to=2025-01-01", query{}, true},
+		{"无效的粒度", "interval=year", query{}, true},
+		{"无效的分组字段", "by=amount", query{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := parseQuery(values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseQuery(%q) 错误 = %v，应为错误 %v", tt.raw, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseQuery(%q) = %+v，应为 %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestQueryString 写法不同但含义相同的查询规范化为同一个字符串
+func TestQueryString(t *testing.T) {
+	tests := []struct {
+		name string
+		raws []string
+		want string
+	}{
+		{"空查询", []string{"", "interval=day", "product=&region=,"}, ""},
+		{"逗号与重复", []string{"product=手机,电脑", "product=手机&product=电脑", "product= 手机 ,电脑,"},
+			"product=" + url.QueryEscape("手机,电脑")},
+		{"参数顺序", []string{"region=华东&from=2025-01-01&interval=month", "interval=month&from=2025-01-01&region=华东"},
+			"from=2025-01-01&interval=month&region=" + url.QueryEscape("华东")},
+		{"分组字段", []string{"by=region,date", "by=region&by=date"}, "by=" + url.QueryEscape("region,date")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, raw := range tt.raws {
+				values, _ := url.ParseQuery(raw)
+				q, err := parseQuery(values)
+				if err != nil {
+					t.Fatalf("parseQuery(%q): %v", raw, err)
+				}
+				if got := q.String(); got != tt.want {
+					t.Errorf("%q 规范化为 %q，应为 %q", raw, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBucket(t *testing.T) {
+	tests := []struct {
+		date, interval, want string
+	}{
+		{"2025-01-15", IntervalDay, "2025-01-15"},
+		{"2025-01-15", IntervalWeek, "2025-W03"},
+		{"2025-01-15", IntervalMonth, "2025-01"},
+		{"2024-12-30", IntervalWeek, "2025-W01"}, // ISO周跨年
+		{"2021-01-03", IntervalWeek, "2020-W53"},
+		{"2024-12-30", IntervalMonth, "2024-12"},
+		{"无效日期", IntervalMonth, "无效日期"},
+	}
+	for _, tt := range tests {
+		if got := bucket(tt.date, tt.interval); got != tt.want {
+			t.Errorf("bucket(%q, %q) = %q，应为 %q", tt.date, tt.interval, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+// Package server 以HTTP JSON接口提供销售分析结果，供其他工具直接取用
+// sales.AnalyzeOverall、AnalyzeByProduct、AnalyzeByRegion、AnalyzeByDate 计算的数据。
+// 首页是嵌入在程序中的网页看板 (KPI卡片、产品/地区/日期图表和筛选面板)，数据同样来自这些接口。
+//
+// 接口 (均为GET):
+//
+//	/api/dataset   当前数据: 文件、记录数、跳过的行、版本
+//	/api/overall   总体统计
+//	/api/products  按产品分析
+//	/api/regions   按地区分析
+//	/api/dates     按日期分析，interval=week/month 时按周或月合并
+//	/api/groups    按 by 指定的字段任意组合分组汇总，如 by=region,product
+//
+// 设置了 Dataset.UploadDir 时，POST /api/uploads 接受CSV或xlsx文件，逐行检查后
+// 保存通过的记录并返回每一行的问题，数据集中已有的记录不会重复计入 (见 upload)。
+//
+// 所有分析接口都接受 product、region、from、to 筛选条件 (见 query)。
+// 响应带有由数据版本和规范化查询计算的 ETag，客户端用 If-None-Match 重新请求时，
+// 数据文件没有变化则返回 304，不再重新计算。
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"sales-analyzer/diff"
+	"sales-analyzer/i18n"
+	"sales-analyzer/sales"
+)
+
+// maxCached 最多缓存的响应数，超出后清空重新缓存
+const maxCached = 256
+
+// Server 分析接口的 http.Handler
+type Server struct {
+	// Workers 并行汇总的工作协程数，0 表示CPU核数
+	Workers int
+
+	data *Dataset
+	mux  *http.ServeMux
+
+	mu      sync.Mutex
+	version string            // cache 对应的数据版本
+	cache   map[string][]byte // ETag → 响应
+}
+
+// New 创建提供 data 分析结果的服务
+func New(data *Dataset) *Server {
+	s := &Server{data: data, mux: http.NewServeMux(), cache: make(map[string][]byte)}
+	s.mux.HandleFunc("GET /api/dataset", s.handle(datasetInfo))
+	s.mux.HandleFunc("GET /api/overall", s.handle(overall))
+	s.mux.HandleFunc("GET /api/products", s.handle(products))
+	s.mux.HandleFunc("GET /api/regions", s.handle(regions))
+	s.mux.HandleFunc("GET /api/dates", s.handle(dates))
+	s.mux.HandleFunc("GET /api/groups", s.handle(groups))
+	if data.UploadDir != "" {
+		s.mux.HandleFunc("POST /api/uploads", s.upload)
+	}
+	s.mux.HandleFunc("GET /{$}", s.dashboard)
+	s.mux.Handle("GET /static/", staticFiles())
+	s.mux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, i18n.T("未知的接口: ")+r.URL.Path)
+	})
+	return s
+}
+
+// ServeHTTP 实现 http.Handler。启用了 debug 级别的日志时记录每个请求的状态码和用时。
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !slog.Default().Enabled(r.Context(), slog.LevelDebug) {
+		s.mux.ServeHTTP(w, r)
+		return
+	}
+	start := time.Now()
+	sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+	s.mux.ServeHTTP(sw, r)
+	elapsed := time.Since(start)
+	slog.Debug(fmt.Sprintf("%s %s %d %s", r.Method, r.URL.RequestURI(), sw.status, elapsed.Round(time.Microsecond)),
+		"method", r.Method, "path", r.URL.Path, "query", r.URL.RawQuery, "status", sw.status, "duration", elapsed)
+}
+
+// statusWriter 记录响应的状态码
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap 供 http.ResponseController 取得原始的 ResponseWriter
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// analysis 根据当前数据和查询计算一个接口的结果
+type analysis func(s *Server, snap *Snapshot, q query) any
+
+// handle 包装分析接口: 取得当前数据、解析查询、处理ETag和缓存
+func (s *Server) handle(analyze analysis) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 重新读取失败时继续使用旧数据，错误由 Dataset.OnReload 报告
+		snap, err := s.data.Current()
+		if snap == nil {
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+
+		q, err := parseQuery(r.URL.Query())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		tag := etag(snap.Version, r.URL.Path, q)
+		w.Header().Set("ETag", tag)
+		// 每次都向服务端确认，数据没有变化时由 304 省去传输
+		w.Header().Set("Cache-Control", "no-cache")
+		if matchETag(r.Header.Get("If-None-Match"), tag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		body, ok := s.cached(snap.Version, tag)
+		if !ok {
+			body, err = json.MarshalIndent(analyze(s, snap, q), "", "  ")
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			s.store(snap.Version, tag, body)
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write(body)
+		w.Write([]byte("\n"))
+	}
+}
+
+// cached 返回已缓存的响应。数据版本变化后旧的缓存全部作废。
+func (s *Server) cached(version, tag string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.version != version {
+		return nil, false
+	}
+	body, ok := s.cache[tag]
+	return body, ok
+}
+
+func (s *Server) store(version, tag string, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.version != version || len(s.cache) >= maxCached {
+		s.version = version
+		s.cache = make(map[string][]byte)
+	}
+	s.cache[tag] = body
+}
+
+// aggregate 按查询筛选后汇总
+func (s *Server) aggregate(snap *Snapshot, q query) *sales.Result {
+	return sales.Aggregate(q.records(snap.Records), sales.Options{Workers: s.Workers})
+}
+
+// etag 由数据版本、接口路径和规范化的查询计算
+func etag(version, path string, q query) string {
+	sum := sha256.Sum256([]byte(path + "?" + q.String()))
+	return `"` + version + "-" + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// matchETag If-None-Match 中是否包含 tag，按弱比较处理 W/ 前缀
+func matchETag(header, tag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == tag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeError 以JSON返回错误: {"error": "..."}
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// DatasetInfo /api/dataset 的响应
+type DatasetInfo struct {
+	Files    []string         `json:"files"`
+	Records  int              `json:"records"`
+	Skipped  []sales.RowError `json:"skipped"`
+	Version  string           `json:"version"`
+	LoadedAt time.Time        `json:"loaded_at"`
+}
+
+func datasetInfo(s *Server, snap *Snapshot, q query) any {
+	return DatasetInfo{
+		Files:    snap.Files,
+		Records:  len(snap.Records),
+		Skipped:  nonNil(snap.Skipped),
+		Version:  snap.Version,
+		LoadedAt: snap.LoadedAt,
+	}
+}
+
+func overall(s *Server, snap *Snapshot, q query) any {
+	return sales.AnalyzeOverall(s.aggregate(snap, q))
+}
+
+func products(s *Server, snap *Snapshot, q query) any {
+	return nonNil(sales.AnalyzeByProduct(s.aggregate(snap, q)))
+}
+
+func regions(s *Server, snap *Snapshot, q query) any {
+	return nonNil(sales.AnalyzeByRegion(s.aggregate(snap, q)))
+}
+
+func dates(s *Server, snap *Snapshot, q query) any {
+	trend := sales.AnalyzeByDate(s.aggregate(snap, q))
+	trend.Days = nonNil(trend.Days)
+	return trend
+}
+
+// Group /api/groups 中的一组，未参与分组的字段为空
+type Group struct {
+	Date    string `json:"date,omitempty"`
+	Product string `json:"product,omitempty"`
+	Region  string `json:"region,omitempty"`
+	sales.Summary
+}
+
+// groups 把最细粒度的汇总按 q.by 合并，按分组字段排序。未指定 by 时按产品分组。
+func groups(s *Server, snap *Snapshot, q query) any {
+	by := q.by
+	if len(by) == 0 {
+		by = []string{diff.FieldProduct}
+	}
+
+	merged := make(map[sales.Key]*Group)
+	for key, summary := range s.aggregate(snap, q).Cells {
+		var k sales.Key
+		for _, field := range by {
+			switch field {
+			case diff.FieldDate:
+				k.Date = key.Date
+			case diff.FieldProduct:
+				k.Product = key.Product
+			case diff.FieldRegion:
+				k.Region = key.Region
+			}
+		}
+		g, ok := merged[k]
+		if !ok {
+			g = &Group{Date: k.Date, Product: k.Product, Region: k.Region}
+			merged[k] = g
+		}
+		g.Quantity += summary.Quantity
+		g.Amount += summary.Amount
+		g.Count += summary.Count
+	}
+
+	result := make([]Group, 0, len(merged))
+	for _, g := range merged {
+		result = append(result, *g)
+	}
+	slices.SortFunc(result, func(a, b Group) int {
+		for _, field := range by {
+			var c int
+			switch field {
+			case diff.FieldDate:
+				c = strings.Compare(a.Date, b.Date)
+			case diff.FieldProduct:
+				c = strings.Compare(a.Product, b.Product)
+			case diff.FieldRegion:
+				c = strings.Compare(a.Region, b.Region)
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return result
+}
+
+// nonNil 空结果序列化为 [] 而不是 null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,209 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"sales-analyzer/sales"
+)
+
+const testCSV = `日期,产品,销量,销售额,地区
+2025-01-01,手机,10,29990,华东
+2025-01-02,电脑,2,13998,华南
+2025-01-31,手机,5,14995,华南
+2025-02-01,手机,1,2999,华东
+2025-02-03,电脑,1,6999,华东
+`
+
+// testServer 读取临时目录中 csv 内容的服务，每次请求都检查文件是否变化
+func testServer(t *testing.T, csv string) (*Server, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "sales.csv")
+	if err := os.WriteFile(path, []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return New(&Dataset{Paths: []string{path}}), path
+}
+
+// get 发送GET请求，header 依次为键和值
+func get(s *Server, target string, header ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("GET", target, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		r.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	return w
+}
+
+func TestMatchETag(t *testing.T) {
+	const tag = `"v1-abc"`
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{``, false},
+		{`"v1-abc"`, true},
+		{`W/"v1-abc"`, true},
+		{`"v0-abc"`, false},
+		{`"v0-abc", "v1-abc"`, true},
+		{`"v0-abc",W/"v1-abc"`, true},
+		{`"v0-abc", "v2-abc"`, false},
+		{`*`, true},
+		{`v1-abc`, false},
+	}
+	for _, tt := range tests {
+		if got := matchETag(tt.header, tag); got != tt.want {
+			t.Errorf("matchETag(%q) = %v，应为 %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+// TestETag 含义相同的查询得到相同的ETag，数据文件变化后旧的ETag失效
+func TestETag(t *testing.T) {
+	s, path := testServer(t, testCSV)
+
+	first := get(s, "/api/products?product=手机,电脑&region=华东")
+	if first.Code != http.StatusOK {
+		t.Fatalf("状态码 = %d，应为 %d: %s", first.Code, http.StatusOK, first.Body)
+	}
+	tag := first.Header().Get("ETag")
+	if tag == "" {
+		t.Fatal("响应没有ETag")
+	}
+	if got := first.Header().Get("Cache-Control"); got != "no-cache" {
+		t.Errorf("Cache-Control = %q，应为 no-cache", got)
+	}
+
+	for _, target := range []string{
+		"/api/products?region=华东&product=手机&product=电脑",
+		"/api/products?product=手机,%20电脑,&region=华东&interval=day",
+	} {
+		if got := get(s, target).Header().Get("ETag"); got != tag {
+			t.Errorf("%s 的ETag = %s，应与 %s 相同", target, got, tag)
+		}
+	}
+	for _, target := range []string{
+		"/api/regions?product=手机,电脑&region=华东",
+		"/api/products?product=手机&region=华东",
+		"/api/products?product=手机,电脑&region=华东&interval=week",
+	} {
+		if got := get(s, target).Header().Get("ETag"); got == tag {
+			t.Errorf("%s 的ETag不应与 %s 相同", target, tag)
+		}
+	}
+
+	t.Run("If-None-Match", func(t *testing.T) {
+		for _, header := range []string{tag, "W/" + tag, `"x", ` + tag, "*"} {
+			w := get(s, "/api/products?product=手机,电脑&region=华东", "If-None-Match", header)
+			if w.Code != http.StatusNotModified {
+				t.Errorf("If-None-Match: %s 的状态码 = %d，应为 %d", header, w.Code, http.StatusNotModified)
+			}
+			if w.Body.Len() != 0 {
+				t.Errorf("304 响应不应有内容: %s", w.Body)
+			}
+		}
+		w := get(s, "/api/products?product=手机,电脑&region=华东", "If-None-Match", `"x"`)
+		if w.Code != http.StatusOK {
+			t.Errorf("不匹配的ETag 状态码 = %d，应为 %d", w.Code, http.StatusOK)
+		}
+	})
+
+	t.Run("数据变化", func(t *testing.T) {
+		if err := os.WriteFile(path, []byte(testCSV+"2025-02-04,手机,1,2999,华东\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		w := get(s, "/api/products?product=手机,电脑&region=华东", "If-None-Match", tag)
+		if w.Code != http.StatusOK {
+			t.Fatalf("数据变化后状态码 = %d，应为 %d", w.Code, http.StatusOK)
+		}
+		if got := w.Header().Get("ETag"); got == tag {
+			t.Errorf("数据变化后ETag仍为 %s", got)
+		}
+		if w.Body.String() == first.Body.String() {
+			t.Errorf("数据变化后返回了缓存的旧结果:\n%s", w.Body)
+		}
+	})
+}
+
+func TestBadRequest(t *testing.T) {
+	s, _ := testServer(t, testCSV)
+	for _, target := range []string{
+		"/api/overall?from=2025/01/01",
+		"/api/overall?to=2025-13-01",
+		"/api/overall?from=2025-02-01&to=2025-01-01",
+		"/api/dates?interval=year",
+		"/api/groups?by=amount",
+	} {
+		w := get(s, target)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s 的状态码 = %d，应为 %d", target, w.Code, http.StatusBadRequest)
+			continue
+		}
+		var body map[string]string
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body["error"] == "" {
+			t.Errorf("%s 应返回 {\"error\": ...}，实际为 %s", target, w.Body)
+		}
+		if w.Header().Get("ETag") != "" {
+			t.Errorf("%s 的错误响应不应有ETag", target)
+		}
+	}
+}
+
+func TestGroups(t *testing.T) {
+	s, _ := testServer(t, testCSV)
+
+	tests := []struct {
+		target string
+		want   []Group
+	}{
+		{"/api/groups?by=region,date&interval=month", []Group{
+			{Date: "2025-01", Region: "华东", Summary: sales.Summary{Quantity: 10, Amount: 29990, Count: 1}},
+			{Date: "2025-02", Region: "华东", Summary: sales.Summary{Quantity: 2, Amount: 9998, Count: 2}},
+			{Date: "2025-01", Region: "华南", Summary: sales.Summary{Quantity: 7, Amount: 28993, Count: 2}},
+		}},
+		{"/api/groups?by=date,region&interval=month", []Group{
+			{Date: "2025-01", Region: "华东", Summary: sales.Summary{Quantity: 10, Amount: 29990, Count: 1}},
+			{Date: "2025-01", Region: "华南", Summary: sales.Summary{Quantity: 7, Amount: 28993, Count: 2}},
+			{Date: "2025-02", Region: "华东", Summary: sales.Summary{Quantity: 2, Amount: 9998, Count: 2}},
+		}},
+		{"/api/groups?from=2025-02-01", []Group{
+			{Product: "手机", Summary: sales.Summary{Quantity: 1, Amount: 2999, Count: 1}},
+			{Product: "电脑", Summary: sales.Summary{Quantity: 1, Amount: 6999, Count: 1}},
+		}},
+		{"/api/groups?product=不存在", []Group{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			w := get(s, tt.target)
+			if w.Code != http.StatusOK {
+				t.Fatalf("状态码 = %d，应为 %d: %s", w.Code, http.StatusOK, w.Body)
+			}
+			var got []Group
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("无法解析响应: %v\n%s", err, w.Body)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("分组 = %+v，应为 %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoadFailure 首次读取失败时返回 503
+func TestLoadFailure(t *testing.T) {
+	s := New(&Dataset{Paths: []string{filepath.Join(t.TempDir(), "missing.csv")}})
+	w := get(s, "/api/overall")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("状态码 = %d，应为 %d", w.Code, http.StatusServiceUnavailable)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body["error"] == "" {
+		t.Errorf("应返回 {\"error\": ...}，实际为 %s", w.Body)
+	}
+}
@@ -0,0 +1,284 @@
+package server
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"sales-analyzer/i18n"
+	"sales-analyzer/sales"
+)
+
+// maxUpload 单次上传的最大字节数
+const maxUpload = 32 << 20
+
+// xlsxType xlsx文件的MIME类型
+const xlsxType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// UploadResult 一次上传的处理结果: 多少行通过了检查，其中多少行是新的，每个被拒绝的行及原因
+type UploadResult struct {
+	ID         string           `json:"id,omitempty"` // 保存的新记录的摘要，没有新记录时为空
+	File       string           `json:"file"`         // 上传的文件名
+	Rows       int              `json:"rows"`         // 数据行数，不含表头
+	Accepted   int              `json:"accepted"`     // 通过检查的行数，即 New + Duplicates
+	New        int              `json:"new"`          // 写入数据集的新记录数
+	Duplicates int              `json:"duplicates"`   // 数据集中已有、没有再次写入的记录数
+	Rejected   []sales.RowError `json:"rejected"`     // 被拒绝的行，一行有多个问题时逐条列出
+	Duplicate  bool             `json:"duplicate"`    // 通过检查的记录全部已在数据集中，本次没有写入任何数据
+}
+
+// upload 处理 POST /api/uploads。请求体可以是 multipart 表单 (字段 file)，
+// 也可以直接是文件内容 (Content-Type 为xlsx的MIME类型时按xlsx读取，否则按CSV，文件名取 name 参数)。
+//
+// 每行按读取和 validate 命令相同的规则检查，通过的行与当前数据集 (输入文件和之前的上传，筛选之前)
+// 逐条比较: 数据集中已有的记录计为重复，不再写入；其余的新记录保存到 UploadDir 并立即计入数据集。
+// 记录按规范化的内容比较，与文件格式、列顺序、数字写法无关；同一条记录出现多次时按次数计算，
+// 数据集中有 n 条相同的记录时，上传中的前 n 条计为重复。
+//
+// 有新记录时返回 201；通过检查的记录全部重复时返回 200 (duplicate 为 true)；
+// 没有一行通过时返回 422，不保存任何数据。
+func (s *Server) upload(w http.ResponseWriter, r *http.Request) {
+	name, data, err := readUpload(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.data.uploadMu.Lock()
+	defer s.data.uploadMu.Unlock()
+
+	if strings.EqualFold(filepath.Ext(name), ".xlsx") {
+		if data, err = xlsxToCSV(data, cmp.Or(s.data.Schema.Date, sales.Columns[0]), s.data.Schema.IsZero()); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	result := &UploadResult{File: name, Rejected: []sales.RowError{}}
+	var accepted []sales.Record
+	skipped, err := s.data.Schema.Scan(bytes.NewReader(data), func(line int, record sales.Record) {
+		result.Rows++
+		problems := sales.Validate(record)
+		for _, problem := range problems {
+			result.Rejected = append(result.Rejected, sales.RowError{File: name, Line: line, Message: problem})
+		}
+		if len(problems) == 0 {
+			accepted = append(accepted, record)
+		}
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	result.Rows += len(skipped)
+	for _, e := range skipped {
+		e.File = name
+		result.Rejected = append(result.Rejected, e)
+	}
+	// 按行号列出
+	sortRowErrors(result.Rejected)
+	result.Accepted = len(accepted)
+
+	if result.Accepted == 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, result)
+		return
+	}
+
+	snap, err := s.data.Current()
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	fresh := newRecords(snap.all, accepted)
+	result.New = len(fresh)
+	result.Duplicates = result.Accepted - result.New
+	if result.New == 0 {
+		result.Duplicate = true
+		writeJSON(w, http.StatusOK, result)
+		return
+	}
+	if err := s.data.store(fresh, result); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, result)
+}
+
+// newRecords 返回 uploaded 中不在 existing 里的记录，按上传的顺序。
+// 记录按 normalize 之后的内容比较，相同的记录按出现次数抵消。
+func newRecords(existing, uploaded []sales.Record) []sales.Record {
+	counts := make(map[sales.Record]int, len(existing))
+	for _, record := range existing {
+		counts[normalize(record)]++
+	}
+	var fresh []sales.Record
+	for _, record := range uploaded {
+		key := normalize(record)
+		if counts[key] > 0 {
+			counts[key]--
+			continue
+		}
+		fresh = append(fresh, record)
+	}
+	return fresh
+}
+
+// normalize 去掉文本字段首尾的空白，用作比较记录的键。
+// 数字已经解析为数值，"5998"、"5998.00" 和xlsx中的数字单元格得到相同的键。
+func normalize(record sales.Record) sales.Record {
+	record.Date = strings.TrimSpace(record.Date)
+	record.Product = strings.TrimSpace(record.Product)
+	record.Region = strings.TrimSpace(record.Region)
+	return record
+}
+
+// readUpload 读取上传的文件名和内容
+func readUpload(w http.ResponseWriter, r *http.Request) (string, []byte, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUpload)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			return "", nil, i18n.Errorf("读取上传文件失败: %w", err)
+		}
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return "", nil, i18n.Errorf("读取上传文件失败: %w", err)
+		}
+		return filepath.Base(header.Filename), data, nil
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", nil, i18n.Errorf("读取上传文件失败: %w", err)
+	}
+	name := filepath.Base(cmp.Or(r.URL.Query().Get("name"), "upload"))
+	if mediaType == xlsxType && !strings.EqualFold(filepath.Ext(name), ".xlsx") {
+		name += ".xlsx"
+	}
+	return name, data, nil
+}
+
+// store 把新记录保存为 UploadDir 中的 <id>.csv，处理结果保存为 <id>.json，
+// id 为保存内容的摘要，同时写入 result.ID。
+// 两个文件都先写临时文件再改名，结果文件最后写入，存在即表示这次上传已经完整保存。
+func (d *Dataset) store(records []sales.Record, result *UploadResult) error {
+	var data bytes.Buffer
+	if err := d.Schema.WriteCSV(&data, records); err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data.Bytes())
+	id := hex.EncodeToString(sum[:])[:16]
+	result.ID = id
+	if err := writeFile(filepath.Join(d.UploadDir, id+".csv"), data.Bytes()); err != nil {
+		return err
+	}
+	report, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(d.UploadDir, id+".json"), report); err != nil {
+		return err
+	}
+	d.invalidate()
+	return nil
+}
+
+// writeFile 原子地写入文件，读取方不会看到写了一半的内容
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return i18n.Errorf("保存上传数据失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return i18n.Errorf("保存上传数据失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return i18n.Errorf("保存上传数据失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return i18n.Errorf("保存上传数据失败: %w", err)
+	}
+	return nil
+}
+
+// xlsxToCSV 把工作簿的第一个工作表转换为CSV，之后与CSV上传走相同的检查。
+// 单元格取原始值，避免千位分隔等数字格式影响解析；日期列中的Excel日期序号转换为 DateLayout。
+// positional 为 true 时按列顺序读取，日期在第一列，否则按表头中名为 dateColumn 的列。
+func xlsxToCSV(data []byte, dateColumn string, positional bool) ([]byte, error) {
+	file, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, i18n.Errorf("无法读取xlsx文件: %w", err)
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, i18n.Errorf("xlsx文件没有工作表")
+	}
+	rows, err := file.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, i18n.Errorf("无法读取xlsx文件: %w", err)
+	}
+
+	dateIndex := 0
+	width := len(sales.Columns)
+	if len(rows) > 0 && !positional {
+		width = len(rows[0])
+		for i, name := range rows[0] {
+			if strings.TrimSpace(name) == dateColumn {
+				dateIndex = i
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	writer := csv.NewWriter(&out)
+	for i, row := range rows {
+		// GetRows 省略行尾的空单元格，补齐后空字段由检查报告
+		for len(row) < width {
+			row = append(row, "")
+		}
+		if i > 0 && dateIndex < len(row) {
+			if serial, err := strconv.ParseFloat(row[dateIndex], 64); err == nil {
+				if t, err := excelize.ExcelDateToTime(serial, false); err == nil {
+					row[dateIndex] = t.Format(sales.DateLayout)
+				}
+			}
+		}
+		writer.Write(row)
+	}
+	writer.Flush()
+	return out.Bytes(), writer.Error()
+}
+
+// sortRowErrors 按行号排序，同一行的问题保持原有顺序
+func sortRowErrors(errs []sales.RowError) {
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Line < errs[j].Line })
+}
+
+// writeJSON 以 status 返回JSON响应
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
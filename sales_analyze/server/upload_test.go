@@ -0,0 +1,251 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+
+	"sales-analyzer/sales"
+)
+
+func TestNewRecords(t *testing.T) {
+	phone := sales.Record{Date: "2025-01-01", Product: "手机", Quantity: 1, Amount: 2999, Region: "华东"}
+	laptop := sales.Record{Date: "2025-01-01", Product: "电脑", Quantity: 1, Amount: 6999, Region: "华南"}
+	padded := sales.Record{Date: "2025-01-01", Product: " 手机 ", Quantity: 1, Amount: 2999.00, Region: "华东 "}
+
+	tests := []struct {
+		name               string
+		existing, uploaded []sales.Record
+		want               []sales.Record
+	}{
+		{"空数据集", nil, []sales.Record{phone, laptop}, []sales.Record{phone, laptop}},
+		{"全部重复", []sales.Record{phone, laptop}, []sales.Record{laptop, phone}, nil},
+		{"部分重复", []sales.Record{phone}, []sales.Record{phone, laptop}, []sales.Record{laptop}},
+		{"空白不同", []sales.Record{phone}, []sales.Record{padded}, nil},
+		{"按次数抵消", []sales.Record{phone}, []sales.Record{phone, phone, phone}, []sales.Record{phone, phone}},
+		{"上传中的相同记录", nil, []sales.Record{phone, phone}, []sales.Record{phone, phone}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newRecords(tt.existing, tt.uploaded); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newRecords() = %+v，应为 %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// uploadServer 按 schema 读取 testCSV、接受上传的服务，返回保存上传的目录
+func uploadServer(t *testing.T, schema sales.Schema) (*Server, string) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sales.csv")
+	if err := os.WriteFile(path, []byte(testCSV), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	uploads := filepath.Join(dir, "uploads")
+	if err := os.Mkdir(uploads, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return New(&Dataset{Paths: []string{path}, Schema: schema, UploadDir: uploads}), uploads
+}
+
+// postFile 以 multipart 表单上传名为 name 的文件
+func postFile(t *testing.T, s *Server, name string, data []byte) (*httptest.ResponseRecorder, UploadResult) {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(data)
+	form.Close()
+
+	r := httptest.NewRequest("POST", "/api/uploads", &body)
+	r.Header.Set("Content-Type", form.FormDataContentType())
+	return serveUpload(t, s, r)
+}
+
+func serveUpload(t *testing.T, s *Server, r *http.Request) (*httptest.ResponseRecorder, UploadResult) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	var result UploadResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("无法解析响应: %v\n%s", err, w.Body)
+	}
+	return w, result
+}
+
+// uploaded 上传目录中的文件名
+func uploaded(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestUpload(t *testing.T) {
+	s, dir := uploadServer(t, sales.Schema{})
+	upload := []byte(`日期,产品,销量,销售额,地区
+2025-01-01,手机,10,29990.00,华东
+2025-02-05,平板,3,8997,华南
+2025-02-06,平板,-1,2999,华南
+2025-02-07,平板,x,2999,华南
+`)
+
+	w, result := postFile(t, s, "../二月.csv", upload)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("状态码 = %d，应为 %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	if result.File != "二月.csv" || result.Rows != 4 || result.Accepted != 2 || result.New != 1 || result.Duplicates != 1 || result.Duplicate {
+		t.Errorf("结果 = %+v，应为 4 行中 2 行通过，1 行新记录、1 行重复", result)
+	}
+	var lines []int
+	for _, e := range result.Rejected {
+		lines = append(lines, e.Line)
+	}
+	if want := []int{4, 5}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("被拒绝的行 = %v，应为 %v", lines, want)
+	}
+
+	want := []string{result.ID + ".csv", result.ID + ".json"}
+	if got := uploaded(t, dir); result.ID == "" || !reflect.DeepEqual(got, want) {
+		t.Fatalf("上传目录中的文件 = %v，应为 %v", got, want)
+	}
+	saved, err := os.ReadFile(filepath.Join(dir, result.ID+".csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(saved), "2025-02-05,平板,3,8997,华南") || strings.Contains(string(saved), "手机") {
+		t.Errorf("保存的内容应只有新记录:\n%s", saved)
+	}
+
+	var info DatasetInfo
+	if err := json.Unmarshal(get(s, "/api/dataset").Body.Bytes(), &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.Records != 6 {
+		t.Errorf("上传后记录数 = %d，应为 6", info.Records)
+	}
+
+	t.Run("重复上传", func(t *testing.T) {
+		w, again := postFile(t, s, "二月.csv", upload)
+		if w.Code != http.StatusOK {
+			t.Fatalf("状态码 = %d，应为 %d: %s", w.Code, http.StatusOK, w.Body)
+		}
+		if !again.Duplicate || again.New != 0 || again.Duplicates != 2 || again.ID != "" {
+			t.Errorf("结果 = %+v，应全部重复", again)
+		}
+		if got := uploaded(t, dir); !reflect.DeepEqual(got, want) {
+			t.Errorf("重复上传后目录中的文件 = %v，应为 %v", got, want)
+		}
+	})
+
+	t.Run("全部无效", func(t *testing.T) {
+		w, invalid := postFile(t, s, "无效.csv", []byte("日期,产品,销量,销售额,地区\n2025/02/05,平板,3,8997,华南\n2025-02-05,,3,8997,华南\n"))
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("状态码 = %d，应为 %d: %s", w.Code, http.StatusUnprocessableEntity, w.Body)
+		}
+		if invalid.Rows != 2 || invalid.Accepted != 0 || len(invalid.Rejected) != 2 {
+			t.Errorf("结果 = %+v，应为 2 行全部被拒绝", invalid)
+		}
+		if got := uploaded(t, dir); !reflect.DeepEqual(got, want) {
+			t.Errorf("无效的上传不应保存，目录中的文件 = %v", got)
+		}
+	})
+
+	t.Run("请求体为文件内容", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/api/uploads?name=三月.csv", strings.NewReader("日期,产品,销量,销售额,地区\n2025-03-01,平板,1,2999,华东\n"))
+		r.Header.Set("Content-Type", "text/csv")
+		w, result := serveUpload(t, s, r)
+		if w.Code != http.StatusCreated || result.File != "三月.csv" || result.New != 1 {
+			t.Errorf("状态码 = %d，结果 = %+v，应保存 1 条新记录", w.Code, result)
+		}
+	})
+}
+
+// TestUploadXLSX xlsx中的Excel日期序号转换为日期，与相同内容的CSV视为重复。
+// 按列名读取，日期不在第一列。
+func TestUploadXLSX(t *testing.T) {
+	s, _ := uploadServer(t, sales.DefaultSchema)
+
+	file := excelize.NewFile()
+	defer file.Close()
+	rows := [][]any{
+		{"地区", "日期", "产品", "销量", "销售额"},
+		{"华东", 45658, "手机", 10, 29990}, // 2025-01-01，已在数据集中
+		{"华北", 45689, "平板", 2, 5998},   // 2025-02-01
+		{"华北", "2025-02-02", "平板", 1},  // 缺少销售额
+	}
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := file.SetSheetRow("Sheet1", cell, &row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var data bytes.Buffer
+	if err := file.Write(&data); err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest("POST", "/api/uploads?name=二月", &data)
+	r.Header.Set("Content-Type", xlsxType)
+	w, result := serveUpload(t, s, r)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("状态码 = %d，应为 %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	if result.File != "二月.xlsx" || result.Rows != 3 || result.New != 1 || result.Duplicates != 1 || len(result.Rejected) != 1 {
+		t.Errorf("结果 = %+v，应为 1 条新记录、1 条重复、1 行被拒绝", result)
+	}
+
+	var groups []Group
+	json.Unmarshal(get(s, "/api/groups?by=date,region&from=2025-02-01&to=2025-02-01").Body.Bytes(), &groups)
+	want := []Group{
+		{Date: "2025-02-01", Region: "华东", Summary: sales.Summary{Quantity: 1, Amount: 2999, Count: 1}},
+		{Date: "2025-02-01", Region: "华北", Summary: sales.Summary{Quantity: 2, Amount: 5998, Count: 1}},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("上传后的分组 = %+v，应为 %+v", groups, want)
+	}
+}
+
+func TestXLSXToCSV(t *testing.T) {
+	file := excelize.NewFile()
+	defer file.Close()
+	file.SetSheetRow("Sheet1", "A1", &[]any{45658, "手机", 1, 1234.5, "华东"})
+	file.SetSheetRow("Sheet1", "A2", &[]any{45689.75, "电脑"})
+	var data bytes.Buffer
+	if err := file.Write(&data); err != nil {
+		t.Fatal(err)
+	}
+
+	// 按列顺序读取时第一行也是数据，但只转换第一行之后的日期
+	got, err := xlsxToCSV(data.Bytes(), "日期", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "45658,手机,1,1234.5,华东\n2025-02-01,电脑,,,\n"
+	if string(got) != want {
+		t.Errorf("xlsxToCSV() = %q，应为 %q", got, want)
+	}
+
+	if _, err := xlsxToCSV([]byte("日期,产品\n"), "日期", false); err == nil {
+		t.Error("不是xlsx文件应返回错误")
+	}
+}
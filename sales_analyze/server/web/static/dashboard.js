@@ -0,0 +1,183 @@
+// 销售看板: 所有数据来自同一服务的 /api/ 接口。
+// 筛选条件变化时重新取数；定期检查 /api/dataset 的版本，数据文件更新后自动刷新。
+// 接口响应带有 ETag，浏览器重新请求时数据没有变化只会得到 304。
+(() => {
+  "use strict";
+
+  // 与 report/svg.go 相同的调色板
+  const palette = ["#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7"];
+  const page = document.body.dataset;
+  const $ = (id) => document.getElementById(id);
+
+  const state = { products: new Set(), regions: new Set(), version: "" };
+
+  // 数字格式与 i18n.Number 相同: 千位分隔符和小数点由服务端的语言区域决定
+  function number(value, decimals) {
+    const [integer, fraction] = Math.abs(value).toFixed(decimals).split(".");
+    const grouped = integer.replace(/\B(?=(\d{3})+(?!\d))/g, page.group);
+    const sign = value < 0 && Number(Math.abs(value).toFixed(decimals)) !== 0 ? "-" : "";
+    return sign + grouped + (fraction ? page.decimal + fraction : "");
+  }
+  const money = (value) => page.currency.replace("%s", number(value, 2));
+  const percent = (value) => number(value, 1) + "%";
+
+  function params() {
+    const p = new URLSearchParams();
+    if (state.products.size) p.set("product", [...state.products].join(","));
+    if (state.regions.size) p.set("region", [...state.regions].join(","));
+    if ($("from").value) p.set("from", $("from").value);
+    if ($("to").value) p.set("to", $("to").value);
+    if ($("interval").value !== "day") p.set("interval", $("interval").value);
+    return p;
+  }
+
+  async function get(path, query) {
+    const response = await fetch(query ? path + "?" + query : path);
+    const body = await response.json();
+    if (!response.ok) throw new Error(body.error || response.statusText);
+    return body;
+  }
+
+  function showError(err) {
+    $("error").hidden = !err;
+    $("error").textContent = err ? "⚠️ " + err.message : "";
+  }
+
+  // 筛选项: 全部产品和地区，不受当前筛选影响
+  async function loadOptions() {
+    const [products, regions] = await Promise.all([get("/api/products"), get("/api/regions")]);
+    renderOptions($("products"), products.map((p) => p.product), state.products);
+    renderOptions($("regions"), regions.map((r) => r.region), state.regions);
+  }
+
+  function renderOptions(container, names, selected) {
+    container.replaceChildren(...names.map((name) => {
+      const box = document.createElement("input");
+      box.type = "checkbox";
+      box.checked = selected.has(name);
+      box.addEventListener("change", () => {
+        box.checked ? selected.add(name) : selected.delete(name);
+        refresh();
+      });
+      const label = document.createElement("label");
+      label.append(box, name);
+      return label;
+    }));
+  }
+
+  async function refresh() {
+    const query = params().toString();
+    try {
+      const [overall, products, regions, dates] = await Promise.all([
+        get("/api/overall", query),
+        get("/api/products", query),
+        get("/api/regions", query),
+        get("/api/dates", query),
+      ]);
+      showError(null);
+      $("kpi-amount").textContent = money(overall.total_amount);
+      $("kpi-quantity").textContent = number(overall.total_quantity, 0) + " " + page.units;
+      $("kpi-average").textContent = money(overall.avg_amount);
+      $("kpi-orders").textContent = number(overall.orders, 0);
+      barChart($("product-chart"), products.map((p) => ({ label: p.product, value: p.total_amount, text: money(p.total_amount) })));
+      barChart($("region-chart"), regions.map((r) => ({
+        label: r.region, value: r.total_amount, text: money(r.total_amount) + " (" + percent(r.market_share) + ")",
+      })));
+      lineChart($("date-chart"), dates.days.map((d) => ({ label: d.date, value: d.total_amount })));
+    } catch (err) {
+      showError(err);
+    }
+  }
+
+  // 数据文件变化后版本改变，重新读取筛选项并刷新
+  async function poll() {
+    try {
+      const info = await get("/api/dataset");
+      const time = new Date(info.loaded_at).toLocaleString(document.documentElement.lang);
+      $("status").textContent = page.status.replace("%d", number(info.records, 0)).replace("%s", time);
+      if (info.version !== state.version) {
+        state.version = info.version;
+        await loadOptions();
+        await refresh();
+      }
+    } catch (err) {
+      showError(err);
+    }
+  }
+
+  function svg(tag, attrs, text) {
+    const el = document.createElementNS("http://www.w3.org/2000/svg", tag);
+    for (const [key, value] of Object.entries(attrs)) el.setAttribute(key, value);
+    if (text !== undefined) el.textContent = text;
+    return el;
+  }
+
+  function empty(container) {
+    const div = document.createElement("div");
+    div.className = "empty";
+    div.textContent = page.empty;
+    container.replaceChildren(div);
+  }
+
+  // barChart 横向条形图，数值标在条形右侧
+  function barChart(container, items) {
+    if (!items.length) return empty(container);
+    const width = 720, row = 28, labelWidth = 110, textWidth = 200;
+    const max = Math.max(...items.map((i) => i.value), 0) || 1;
+    const chart = svg("svg", { viewBox: `0 0 ${width} ${items.length * row + 8}`, role: "img" });
+    items.forEach((item, i) => {
+      const y = i * row + 4;
+      const length = Math.max(item.value / max, 0) * (width - labelWidth - textWidth);
+      chart.append(
+        svg("text", { x: labelWidth - 8, y: y + 17, "text-anchor": "end" }, item.label),
+        svg("rect", { x: labelWidth, y: y + 4, width: length, height: row - 10, rx: 3, fill: palette[i % palette.length] }),
+        svg("text", { x: labelWidth + length + 6, y: y + 17 }, item.text),
+      );
+    });
+    container.replaceChildren(chart);
+  }
+
+  // lineChart 折线图，点较多时只标注部分日期
+  function lineChart(container, points) {
+    if (!points.length) return empty(container);
+    const width = 720, height = 240, left = 90, right = 20, top = 12, bottom = 28;
+    const max = Math.max(...points.map((p) => p.value), 0) || 1;
+    const x = (i) => left + (points.length === 1 ? (width - left - right) / 2 : i * (width - left - right) / (points.length - 1));
+    const y = (v) => top + (1 - v / max) * (height - top - bottom);
+    const chart = svg("svg", { viewBox: `0 0 ${width} ${height}`, role: "img" });
+
+    for (let step = 0; step <= 4; step++) {
+      const value = max * step / 4;
+      chart.append(
+        svg("line", { class: "grid", x1: left, x2: width - right, y1: y(value), y2: y(value) }),
+        svg("text", { class: "axis", x: left - 6, y: y(value) + 4, "text-anchor": "end" }, number(value, 0)),
+      );
+    }
+    chart.append(svg("polyline", {
+      points: points.map((p, i) => `${x(i)},${y(p.value)}`).join(" "),
+      fill: "none", stroke: palette[0], "stroke-width": 2,
+    }));
+    const every = Math.ceil(points.length / 8);
+    points.forEach((p, i) => {
+      const dot = svg("circle", { cx: x(i), cy: y(p.value), r: 3.5, fill: palette[0] });
+      dot.append(svg("title", {}, p.label + ": " + money(p.value)));
+      chart.append(dot);
+      if (i % every === 0 || i === points.length - 1) {
+        chart.append(svg("text", { class: "axis", x: x(i), y: height - 8, "text-anchor": "middle" }, p.label));
+      }
+    });
+    container.replaceChildren(chart);
+  }
+
+  for (const id of ["from", "to", "interval"]) $(id).addEventListener("change", refresh);
+  $("reset").addEventListener("click", () => {
+    state.products.clear();
+    state.regions.clear();
+    $("from").value = $("to").value = "";
+    $("interval").value = "day";
+    loadOptions().then(refresh, showError);
+  });
+
+  poll();
+  setInterval(poll, 10000);
+})();
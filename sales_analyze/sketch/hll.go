@@ -0,0 +1,120 @@
+// Package sketch 提供可合并的近似统计结构，用于在流式处理大数据量时
+// 以固定内存估算去重计数 (HyperLogLog) 和分位数 (KLL)。
+// 两种结构都可以按分片分别构建后再合并，误差上限在创建时指定。
+package sketch
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+const (
+	minPrecision = 4
+	maxPrecision = 18
+)
+
+// HyperLogLog 去重计数草图
+type HyperLogLog struct {
+	precision uint8
+	registers []uint8
+}
+
+// NewHyperLogLog 按相对标准误差创建草图，例如 0.01 表示约1%的误差。
+// 寄存器数 m 满足 1.04/√m ≤ relErr，精度限制在 2^4 到 2^18 个寄存器之间。
+func NewHyperLogLog(relErr float64) *HyperLogLog {
+	if relErr <= 0 || relErr >= 1 {
+		relErr = 0.01
+	}
+
+	m := math.Pow(1.04/relErr, 2)
+	p := int(math.Ceil(math.Log2(m)))
+	p = max(minPrecision, min(maxPrecision, p))
+
+	return &HyperLogLog{
+		precision: uint8(p),
+		registers: make([]uint8, 1<<p),
+	}
+}
+
+// RelativeError 返回草图的理论相对标准误差
+func (h *HyperLogLog) RelativeError() float64 {
+	return 1.04 / math.Sqrt(float64(len(h.registers)))
+}
+
+// Add 记录一个值
+func (h *HyperLogLog) Add(value string) {
+	x := hash64(value)
+	index := x >> (64 - h.precision)
+	// 剩余位中第一个1出现的位置，低位补1防止全零
+	rest := x<<h.precision | 1<<(h.precision-1)
+	rank := uint8(bits.LeadingZeros64(rest)) + 1
+	if rank > h.registers[index] {
+		h.registers[index] = rank
+	}
+}
+
+// Merge 合并另一个同精度的草图
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if h.precision != other.precision {
+		return fmt.Errorf("HyperLogLog精度不一致: %d != %d", h.precision, other.precision)
+	}
+	for i, rank := range other.registers {
+		if rank > h.registers[i] {
+			h.registers[i] = rank
+		}
+	}
+	return nil
+}
+
+// Count 返回去重计数的估计值
+func (h *HyperLogLog) Count() uint64 {
+	m := float64(len(h.registers))
+
+	var sum float64
+	zeros := 0
+	for _, rank := range h.registers {
+		sum += 1 / float64(uint64(1)<<rank)
+		if rank == 0 {
+			zeros++
+		}
+	}
+
+	estimate := alpha(len(h.registers)) * m * m / sum
+
+	// 基数较小时改用线性计数，误差更低
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+
+	return uint64(estimate + 0.5)
+}
+
+func alpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(m))
+	}
+}
+
+// hash64 使用FNV-1a并做一次位混合，保证不同进程中同一值的哈希一致，
+// 这样分别构建的草图才能合并
+func hash64(value string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(value))
+	x := h.Sum64()
+
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
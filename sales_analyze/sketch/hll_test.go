@@ -0,0 +1,75 @@
+package sketch
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestHyperLogLogRelativeError(t *testing.T) {
+	for _, relErr := range []float64{0.05, 0.01} {
+		for _, n := range []int{10, 1000, 50000, 200000} {
+			t.Run(fmt.Sprintf("%g/%d", relErr, n), func(t *testing.T) {
+				h := NewHyperLogLog(relErr)
+				if got := h.RelativeError(); got > relErr {
+					t.Fatalf("RelativeError() = %g，超过要求的 %g", got, relErr)
+				}
+				// 每个值重复出现，去重后恰好 n 个
+				for i := range 3 * n {
+					h.Add(fmt.Sprintf("product-%d", i%n))
+				}
+				// 允许3倍标准误差，对固定的输入结果是确定的
+				got := float64(h.Count())
+				if err := math.Abs(got-float64(n)) / float64(n); err > 3*h.RelativeError() {
+					t.Errorf("Count() = %.0f，精确值 %d，相对误差 %.4f 超过 %.4f", got, n, err, 3*h.RelativeError())
+				}
+			})
+		}
+	}
+}
+
+func TestHyperLogLogMergeEqualsSinglePass(t *testing.T) {
+	const n, shards = 30000, 7
+
+	single := NewHyperLogLog(0.01)
+	parts := make([]*HyperLogLog, shards)
+	for i := range parts {
+		parts[i] = NewHyperLogLog(0.01)
+	}
+	for i := range n {
+		value := fmt.Sprintf("region-%d", i)
+		single.Add(value)
+		parts[i%shards].Add(value)
+		// 同一个值出现在多个分片中，合并后仍只计一次
+		parts[(i+1)%shards].Add(value)
+	}
+
+	merged := NewHyperLogLog(0.01)
+	for _, part := range parts {
+		if err := merged.Merge(part); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !slices.Equal(merged.registers, single.registers) {
+		t.Error("分片合并后的寄存器与一次性构建的不同")
+	}
+	if merged.Count() != single.Count() {
+		t.Errorf("合并后 Count() = %d，一次性构建为 %d", merged.Count(), single.Count())
+	}
+}
+
+func TestHyperLogLogMergePrecisionMismatch(t *testing.T) {
+	h := NewHyperLogLog(0.01)
+	h.Add("手机")
+	before := h.Count()
+
+	other := NewHyperLogLog(0.1)
+	other.Add("电脑")
+	if err := h.Merge(other); err == nil {
+		t.Fatal("合并精度不同的草图应返回错误")
+	}
+	if h.Count() != before {
+		t.Errorf("合并失败后 Count() = %d，应保持 %d", h.Count(), before)
+	}
+}
@@ -0,0 +1,170 @@
+package sketch
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	minK = 8
+	// 相邻层容量的衰减系数
+	capacityDecay = 2.0 / 3.0
+)
+
+// KLL 分位数草图。
+// 每一层是一个压缩器，满了之后排序并隔一个取一个提升到上一层，
+// 第 h 层中的每个值代表 2^h 个原始值。
+type KLL struct {
+	k          int
+	compactors [][]float64
+	count      uint64
+	min, max   float64
+	// 压缩时选择奇偶位置的伪随机状态，固定种子使结果可复现
+	coin uint64
+}
+
+// NewKLL 按归一化秩误差创建草图，例如 0.01 表示返回值的秩与真实秩相差约1%。
+// 参数 k 由经验公式 ε ≈ 2.296 / k^0.9723 反推得到。
+func NewKLL(rankErr float64) *KLL {
+	if rankErr <= 0 || rankErr >= 1 {
+		rankErr = 0.01
+	}
+
+	k := int(math.Ceil(math.Pow(2.296/rankErr, 1/0.9723)))
+	k = max(minK, k)
+
+	return &KLL{
+		k:          k,
+		compactors: [][]float64{nil},
+		min:        math.Inf(1),
+		max:        math.Inf(-1),
+		coin:       0x9e3779b97f4a7c15,
+	}
+}
+
+// RankError 返回草图的理论归一化秩误差
+func (s *KLL) RankError() float64 {
+	return 2.296 / math.Pow(float64(s.k), 0.9723)
+}
+
+// Count 返回已记录的值的个数
+func (s *KLL) Count() uint64 {
+	return s.count
+}
+
+// Add 记录一个值
+func (s *KLL) Add(value float64) {
+	s.compactors[0] = append(s.compactors[0], value)
+	s.count++
+	s.min = math.Min(s.min, value)
+	s.max = math.Max(s.max, value)
+	s.compress()
+}
+
+// Merge 合并另一个相同参数的草图
+func (s *KLL) Merge(other *KLL) error {
+	if s.k != other.k {
+		return fmt.Errorf("KLL参数不一致: %d != %d", s.k, other.k)
+	}
+	if other.count == 0 {
+		return nil
+	}
+
+	for len(s.compactors) < len(other.compactors) {
+		s.compactors = append(s.compactors, nil)
+	}
+	for h, items := range other.compactors {
+		s.compactors[h] = append(s.compactors[h], items...)
+	}
+
+	s.count += other.count
+	s.min = math.Min(s.min, other.min)
+	s.max = math.Max(s.max, other.max)
+	s.compress()
+	return nil
+}
+
+// Quantile 返回分位数 q (0~1) 的估计值，没有数据时返回 NaN
+func (s *KLL) Quantile(q float64) float64 {
+	if s.count == 0 {
+		return math.NaN()
+	}
+	if q <= 0 {
+		return s.min
+	}
+	if q >= 1 {
+		return s.max
+	}
+
+	type weighted struct {
+		value  float64
+		weight uint64
+	}
+
+	var items []weighted
+	var total uint64
+	for h, compactor := range s.compactors {
+		weight := uint64(1) << h
+		for _, value := range compactor {
+			items = append(items, weighted{value, weight})
+			total += weight
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].value < items[j].value
+	})
+
+	target := q * float64(total)
+	var cumulative uint64
+	for _, item := range items {
+		cumulative += item.weight
+		if float64(cumulative) >= target {
+			return item.value
+		}
+	}
+	return s.max
+}
+
+// capacity 返回第 h 层的容量，越低的层容量越小
+func (s *KLL) capacity(h int) int {
+	depth := len(s.compactors) - 1 - h
+	return max(2, int(math.Ceil(float64(s.k)*math.Pow(capacityDecay, float64(depth)))))
+}
+
+// compress 从低到高压缩所有超出容量的层
+func (s *KLL) compress() {
+	for h := 0; h < len(s.compactors); h++ {
+		if len(s.compactors[h]) < s.capacity(h) {
+			continue
+		}
+		if h+1 == len(s.compactors) {
+			s.compactors = append(s.compactors, nil)
+		}
+
+		items := s.compactors[h]
+		sort.Float64s(items)
+
+		// 元素个数为奇数时保留一个在本层，其余成对压缩
+		var keep []float64
+		if len(items)%2 == 1 {
+			keep = []float64{items[len(items)-1]}
+			items = items[:len(items)-1]
+		}
+
+		offset := s.flip()
+		for i := offset; i < len(items); i += 2 {
+			s.compactors[h+1] = append(s.compactors[h+1], items[i])
+		}
+		s.compactors[h] = append(items[:0], keep...)
+	}
+}
+
+// flip 返回伪随机的0或1 (xorshift64)
+func (s *KLL) flip() int {
+	s.coin ^= s.coin << 13
+	s.coin ^= s.coin >> 7
+	s.coin ^= s.coin << 17
+	return int(s.coin & 1)
+}
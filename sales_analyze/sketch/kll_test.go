@@ -0,0 +1,121 @@
+package sketch
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"testing"
+)
+
+// shuffled 返回 1..n 的固定顺序的随机排列
+func shuffled(n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	r := rand.New(rand.NewPCG(1, 2))
+	r.Shuffle(n, func(i, j int) { values[i], values[j] = values[j], values[i] })
+	return values
+}
+
+// rankError 返回 estimate 在 sorted 中的归一化秩与 q 的差
+func rankError(sorted []float64, q, estimate float64) float64 {
+	rank := sort.SearchFloat64s(sorted, estimate)
+	return math.Abs(float64(rank)/float64(len(sorted)) - q)
+}
+
+var quantiles = []float64{0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99}
+
+func TestKLLRankError(t *testing.T) {
+	for _, rankErr := range []float64{0.05, 0.01} {
+		for _, n := range []int{100, 10000, 200000} {
+			t.Run(fmt.Sprintf("%g/%d", rankErr, n), func(t *testing.T) {
+				s := NewKLL(rankErr)
+				if got := s.RankError(); got > rankErr {
+					t.Fatalf("RankError() = %g，超过要求的 %g", got, rankErr)
+				}
+				values := shuffled(n)
+				for _, v := range values {
+					s.Add(v)
+				}
+				sort.Float64s(values)
+
+				if s.Count() != uint64(n) {
+					t.Errorf("Count() = %d，应为 %d", s.Count(), n)
+				}
+				if s.Quantile(0) != 1 || s.Quantile(1) != float64(n) {
+					t.Errorf("Quantile(0), Quantile(1) = %g, %g，应为 1, %d", s.Quantile(0), s.Quantile(1), n)
+				}
+				for _, q := range quantiles {
+					if err := rankError(values, q, s.Quantile(q)); err > 2*s.RankError() {
+						t.Errorf("Quantile(%g) = %g，秩误差 %.4f 超过 %.4f", q, s.Quantile(q), err, 2*s.RankError())
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestKLLMergeMatchesSinglePass(t *testing.T) {
+	const n, shards = 100000, 8
+
+	values := shuffled(n)
+	single := NewKLL(0.01)
+	parts := make([]*KLL, shards)
+	for i := range parts {
+		parts[i] = NewKLL(0.01)
+	}
+	for i, v := range values {
+		single.Add(v)
+		parts[i%shards].Add(v)
+	}
+
+	merged := NewKLL(0.01)
+	for _, part := range parts {
+		if err := merged.Merge(part); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 压缩时丢弃的元素不同，合并结果不会与一次性构建逐个相同，
+	// 但计数、最值必须一致，分位数都在误差范围内
+	if merged.Count() != single.Count() {
+		t.Errorf("合并后 Count() = %d，一次性构建为 %d", merged.Count(), single.Count())
+	}
+	if merged.min != single.min || merged.max != single.max {
+		t.Errorf("合并后最小、最大值为 %g, %g，一次性构建为 %g, %g", merged.min, merged.max, single.min, single.max)
+	}
+	sort.Float64s(values)
+	for _, q := range quantiles {
+		m, s := merged.Quantile(q), single.Quantile(q)
+		if err := rankError(values, q, m); err > 2*merged.RankError() {
+			t.Errorf("合并后 Quantile(%g) = %g，秩误差 %.4f 超过 %.4f", q, m, err, 2*merged.RankError())
+		}
+		if diff := math.Abs(m-s) / n; diff > 2*merged.RankError() {
+			t.Errorf("Quantile(%g): 合并后 %g，一次性构建 %g，相差过大", q, m, s)
+		}
+	}
+}
+
+func TestKLLMergeEmpty(t *testing.T) {
+	s := NewKLL(0.01)
+	if err := s.Merge(NewKLL(0.01)); err != nil {
+		t.Fatal(err)
+	}
+	if s.Count() != 0 || !math.IsNaN(s.Quantile(0.5)) {
+		t.Errorf("合并两个空草图后 Count() = %d, Quantile(0.5) = %g", s.Count(), s.Quantile(0.5))
+	}
+}
+
+func TestKLLMergeParameterMismatch(t *testing.T) {
+	s := NewKLL(0.01)
+	s.Add(1)
+	other := NewKLL(0.1)
+	other.Add(2)
+	if err := s.Merge(other); err == nil {
+		t.Fatal("合并参数不同的草图应返回错误")
+	}
+	if s.Count() != 1 {
+		t.Errorf("合并失败后 Count() = %d，应保持 1", s.Count())
+	}
+}
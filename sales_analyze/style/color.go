@@ -0,0 +1,116 @@
+package style
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Basic 16色终端的颜色编号 (前景色 30-37，亮色 90-97)
+type Basic int
+
+const (
+	Black Basic = 30 + iota
+	Red
+	Green
+	Yellow
+	Blue
+	Magenta
+	Cyan
+	White
+)
+
+const (
+	BrightBlack Basic = 90 + iota
+	BrightRed
+	BrightGreen
+	BrightYellow
+	BrightBlue
+	BrightMagenta
+	BrightCyan
+	BrightWhite
+)
+
+// Spec 一种文字样式。Color 为 "#rrggbb"，用于256色和真彩色终端；
+// 16色终端使用 Basic，二者都为空时只应用粗体、反色等属性。
+type Spec struct {
+	Color   string
+	Basic   Basic
+	Bold    bool
+	Reverse bool // 前景色和背景色互换，用于高亮选中行
+}
+
+// sequence 返回该样式在指定颜色能力下的起始控制序列，不需要样式时返回空串
+func (s Spec) sequence(p Profile) string {
+	if p == NoColor {
+		return ""
+	}
+
+	var codes []string
+	if s.Bold {
+		codes = append(codes, "1")
+	}
+	if s.Reverse {
+		codes = append(codes, "7")
+	}
+	r, g, b, ok := parseHex(s.Color)
+	switch {
+	case p == TrueColor && ok:
+		codes = append(codes, fmt.Sprintf("38;2;%d;%d;%d", r, g, b))
+	case p == ANSI256 && ok:
+		codes = append(codes, fmt.Sprintf("38;5;%d", xterm256(r, g, b)))
+	case s.Basic != 0:
+		codes = append(codes, strconv.Itoa(int(s.Basic)))
+	}
+
+	if len(codes) == 0 {
+		return ""
+	}
+	return "\033[" + strings.Join(codes, ";") + "m"
+}
+
+const reset = "\033[0m"
+
+func parseHex(hex string) (r, g, b int, ok bool) {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) != 6 {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return int(v >> 16 & 0xff), int(v >> 8 & 0xff), int(v & 0xff), true
+}
+
+// xterm256 将RGB颜色映射到xterm 256色表中最接近的颜色 (6×6×6色块或24级灰阶)
+func xterm256(r, g, b int) int {
+	level := func(v int) int {
+		if v < 48 {
+			return 0
+		}
+		if v < 115 {
+			return 1
+		}
+		return (v - 35) / 40
+	}
+	steps := []int{0, 95, 135, 175, 215, 255}
+	cr, cg, cb := level(r), level(g), level(b)
+	cube := 16 + 36*cr + 6*cg + cb
+
+	// 灰色接近时使用灰阶，精度更高
+	gray := (r + g + b) / 3
+	grayIndex := 23
+	if gray < 238 {
+		grayIndex = max(0, (gray-3)/10)
+	}
+	grayValue := 8 + grayIndex*10
+
+	distance := func(x, y, z int) int {
+		return (r-x)*(r-x) + (g-y)*(g-y) + (b-z)*(b-z)
+	}
+	if distance(grayValue, grayValue, grayValue) < distance(steps[cr], steps[cg], steps[cb]) {
+		return 232 + grayIndex
+	}
+	return cube
+}
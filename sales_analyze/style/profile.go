@@ -0,0 +1,113 @@
+package style
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"strings"
+
+	"sales-analyzer/i18n"
+)
+
+// Profile 终端的颜色能力
+type Profile int
+
+const (
+	NoColor   Profile = iota // 不输出任何控制序列
+	ANSI16                   // 16色
+	ANSI256                  // 256色
+	TrueColor                // 24位真彩色
+)
+
+func (p Profile) String() string {
+	switch p {
+	case ANSI16:
+		return "16"
+	case ANSI256:
+		return "256"
+	case TrueColor:
+		return "truecolor"
+	default:
+		return "none"
+	}
+}
+
+// Mode 颜色输出模式，通常来自 -color 参数
+type Mode int
+
+const (
+	ModeAuto   Mode = iota // 按终端能力自动检测，输出不是终端时不使用颜色
+	ModeNever              // 从不使用颜色
+	ModeAlways             // 即使输出不是终端也使用颜色 (仍按 TERM/COLORTERM 判断色深)
+)
+
+// Modes 支持的模式名称
+var Modes = []string{"auto", "never", "always"}
+
+// ParseMode 解析模式名称
+func ParseMode(name string) (Mode, error) {
+	switch strings.ToLower(name) {
+	case "auto", "":
+		return ModeAuto, nil
+	case "never", "none", "off":
+		return ModeNever, nil
+	case "always", "on":
+		return ModeAlways, nil
+	}
+	return ModeAuto, i18n.Errorf("不支持的颜色模式: %q (可选 %s)", name, strings.Join(Modes, "/"))
+}
+
+// Detect 检测输出目标 w 的颜色能力:
+//   - 设置了 NO_COLOR 时不使用颜色；设置了 FORCE_COLOR 或 CLICOLOR_FORCE (非0) 时视同 ModeAlways
+//   - ModeAuto 下 w 不是终端 (重定向到文件、管道) 时不使用颜色
+//   - TERM=dumb 不使用颜色；COLORTERM=truecolor/24bit 为真彩色；TERM 含 256color 为256色；其余为16色
+func Detect(w io.Writer, mode Mode) Profile {
+	return detect(mode, IsTerminal(w), os.Getenv)
+}
+
+// detect 按输出是否为终端和环境变量判断颜色能力，getenv 通常为 os.Getenv
+func detect(mode Mode, terminal bool, getenv func(string) string) Profile {
+	if mode == ModeNever || getenv("NO_COLOR") != "" {
+		return NoColor
+	}
+	if forced(getenv("FORCE_COLOR")) || forced(getenv("CLICOLOR_FORCE")) {
+		mode = ModeAlways
+	}
+	if mode == ModeAuto && !terminal {
+		return NoColor
+	}
+
+	term := strings.ToLower(getenv("TERM"))
+	colorTerm := strings.ToLower(getenv("COLORTERM"))
+	switch {
+	case term == "dumb":
+		return NoColor
+	case colorTerm == "truecolor" || colorTerm == "24bit",
+		strings.Contains(term, "truecolor"), strings.Contains(term, "direct"):
+		return TrueColor
+	case strings.Contains(term, "256color"):
+		return ANSI256
+	case runtime.GOOS == "windows" && getenv("WT_SESSION") != "":
+		// Windows Terminal 支持真彩色，但不设置 COLORTERM
+		return TrueColor
+	}
+	return ANSI16
+}
+
+// forced 环境变量的值是否表示强制使用颜色 (非空且不为0)
+func forced(value string) bool {
+	return value != "" && value != "0"
+}
+
+// IsTerminal 判断 w 是否为终端 (字符设备)
+func IsTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,113 @@
+package style
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     Mode
+		terminal bool
+		env      map[string]string
+		want     Profile
+	}{
+		{"终端", ModeAuto, true, map[string]string{"TERM": "xterm"}, ANSI16},
+		{"没有TERM", ModeAuto, true, nil, ANSI16},
+		{"256色", ModeAuto, true, map[string]string{"TERM": "xterm-256color"}, ANSI256},
+		{"真彩色", ModeAuto, true, map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, TrueColor},
+		{"24bit", ModeAuto, true, map[string]string{"COLORTERM": "24BIT"}, TrueColor},
+		{"TERM含direct", ModeAuto, true, map[string]string{"TERM": "xterm-direct"}, TrueColor},
+		{"不是终端", ModeAuto, false, map[string]string{"TERM": "xterm-256color"}, NoColor},
+		{"不是终端但 always", ModeAlways, false, map[string]string{"TERM": "xterm-256color"}, ANSI256},
+		{"never", ModeNever, true, map[string]string{"TERM": "xterm-256color", "FORCE_COLOR": "1"}, NoColor},
+		{"TERM=dumb", ModeAuto, true, map[string]string{"TERM": "dumb", "COLORTERM": "truecolor"}, NoColor},
+		{"TERM=dumb 且 always", ModeAlways, false, map[string]string{"TERM": "dumb"}, NoColor},
+		{"NO_COLOR", ModeAuto, true, map[string]string{"TERM": "xterm", "NO_COLOR": "1"}, NoColor},
+		{"NO_COLOR 优先于 always", ModeAlways, true, map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "1"}, NoColor},
+		{"FORCE_COLOR", ModeAuto, false, map[string]string{"TERM": "xterm", "FORCE_COLOR": "1"}, ANSI16},
+		{"FORCE_COLOR=0", ModeAuto, false, map[string]string{"TERM": "xterm", "FORCE_COLOR": "0"}, NoColor},
+		{"CLICOLOR_FORCE", ModeAuto, false, map[string]string{"COLORTERM": "truecolor", "CLICOLOR_FORCE": "1"}, TrueColor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(name string) string { return tt.env[name] }
+			if got := detect(tt.mode, tt.terminal, getenv); got != tt.want {
+				t.Errorf("detect() = %s，应为 %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	if IsTerminal(new(bytes.Buffer)) {
+		t.Error("bytes.Buffer 不是终端")
+	}
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if IsTerminal(file) {
+		t.Error("普通文件不是终端")
+	}
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+	if got := Detect(file, ModeAuto); got != NoColor {
+		t.Errorf("输出到文件时 Detect() = %s，应为 %s", got, NoColor)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	red := Spec{Color: "#ff0000", Basic: Red}
+	tests := []struct {
+		name    string
+		spec    Spec
+		profile Profile
+		want    string
+	}{
+		{"真彩色", red, TrueColor, "\033[38;2;255;0;0m"},
+		{"256色", red, ANSI256, "\033[38;5;196m"},
+		{"16色", red, ANSI16, "\033[31m"},
+		{"无颜色", red, NoColor, ""},
+		{"粗体反色", Spec{Color: "#5f87af", Bold: true, Reverse: true}, ANSI256, "\033[1;7;38;5;67m"},
+		{"只有RGB的16色", Spec{Color: "#5f87af"}, ANSI16, ""},
+		{"无效的颜色", Spec{Color: "#xyz", Basic: BrightCyan}, TrueColor, "\033[96m"},
+		{"只有属性", Spec{Bold: true}, ANSI16, "\033[1m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.spec.sequence(tt.profile); got != tt.want {
+				t.Errorf("sequence(%s) = %q，应为 %q", tt.profile, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestXterm256 真彩色降级为256色: 色块和灰阶中最接近的颜色
+func TestXterm256(t *testing.T) {
+	tests := []struct {
+		r, g, b int
+		want    int
+	}{
+		{0, 0, 0, 16},
+		{255, 255, 255, 231},
+		{255, 0, 0, 196},
+		{0, 255, 0, 46},
+		{95, 135, 175, 67},
+		{8, 8, 8, 232},
+		{128, 128, 128, 244},
+		{238, 238, 238, 255},
+		{130, 128, 126, 244}, // 接近灰色时使用灰阶
+	}
+	for _, tt := range tests {
+		if got := xterm256(tt.r, tt.g, tt.b); got != tt.want {
+			t.Errorf("xterm256(%d, %d, %d) = %d，应为 %d", tt.r, tt.g, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Package style 统一终端输出的颜色和样式。
+//
+// 代码按语义角色 (标题、成功、警告、错误、提示等) 而不是具体颜色输出文字，
+// 由主题决定各角色的样式，再按终端的颜色能力 (无颜色、16色、256色、真彩色)
+// 生成控制序列。输出重定向到文件或管道、或设置了 NO_COLOR 时不输出任何控制序列。
+//
+// 主题和颜色模式是进程级的设置，由命令行在启动时通过 Configure 选定。
+package style
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+var (
+	mu           sync.RWMutex
+	currentTheme = &ThemeDefault
+	currentMode  = ModeAuto
+)
+
+// Configure 设置当前主题和颜色模式
+func Configure(theme *Theme, mode Mode) {
+	mu.Lock()
+	defer mu.Unlock()
+	currentTheme, currentMode = theme, mode
+}
+
+// Styler 按主题和颜色能力为文字加样式
+type Styler struct {
+	theme   *Theme
+	profile Profile
+}
+
+// New 创建指定主题和颜色能力的 Styler
+func New(theme *Theme, profile Profile) *Styler {
+	return &Styler{theme: theme, profile: profile}
+}
+
+// For 按当前主题和颜色模式，为输出目标 w 创建 Styler
+func For(w io.Writer) *Styler {
+	mu.RLock()
+	defer mu.RUnlock()
+	return New(currentTheme, Detect(w, currentMode))
+}
+
+// Profile 颜色能力
+func (s *Styler) Profile() Profile {
+	return s.profile
+}
+
+// Enabled 是否会输出控制序列
+func (s *Styler) Enabled() bool {
+	return s.profile != NoColor
+}
+
+// Paint 按角色为文字加样式
+func (s *Styler) Paint(role Role, text string) string {
+	return s.apply(s.theme.Roles[role], text)
+}
+
+// Sprintf 格式化后按角色加样式
+func (s *Styler) Sprintf(role Role, format string, args ...any) string {
+	return s.Paint(role, fmt.Sprintf(format, args...))
+}
+
+// Series 使用调色板中的第 i 个颜色，用于图表的数据系列
+func (s *Styler) Series(i int, text string) string {
+	if len(s.theme.Palette) == 0 {
+		return text
+	}
+	return s.apply(s.theme.Palette[i%len(s.theme.Palette)], text)
+}
+
+// Fprintf 格式化后按角色加样式写到 w
+func (s *Styler) Fprintf(w io.Writer, role Role, format string, args ...any) (int, error) {
+	return io.WriteString(w, s.Sprintf(role, format, args...))
+}
+
+func (s *Styler) apply(spec Spec, text string) string {
+	seq := spec.sequence(s.profile)
+	if seq == "" || text == "" {
+		return text
+	}
+	return seq + text + reset
+}
@@ -0,0 +1,117 @@
+package style
+
+import (
+	"sort"
+	"strings"
+
+	"sales-analyzer/i18n"
+)
+
+// Role 文字的语义角色，主题为每个角色指定样式
+type Role int
+
+const (
+	Plain    Role = iota // 不加样式
+	Title                // 报表标题
+	Header               // 章节标题
+	Success              // 正面结果
+	Warning              // 需要关注
+	Error                // 错误
+	Info                 // 一般提示
+	Muted                // 次要信息，如图表坐标
+	Selected             // 交互界面中选中的行
+)
+
+// Theme 一套配色: 各语义角色的样式，以及图表按顺序使用的调色板
+type Theme struct {
+	Name    string
+	Roles   map[Role]Spec
+	Palette []Spec
+}
+
+// 内置主题
+var (
+	// ThemeDefault 适合深色背景的默认主题
+	ThemeDefault = Theme{
+		Name: "default",
+		Roles: map[Role]Spec{
+			Title:    {Color: "#56b6c2", Basic: Cyan, Bold: true},
+			Header:   {Color: "#e5c07b", Basic: Yellow, Bold: true},
+			Success:  {Color: "#98c379", Basic: Green},
+			Warning:  {Color: "#e5c07b", Basic: Yellow},
+			Error:    {Color: "#e06c75", Basic: Red},
+			Info:     {Color: "#61afef", Basic: Blue},
+			Muted:    {Color: "#7f848e", Basic: BrightBlack},
+			Selected: {Color: "#61afef", Basic: Blue, Reverse: true},
+		},
+		Palette: []Spec{
+			{Color: "#56b6c2", Basic: Cyan},
+			{Color: "#c678dd", Basic: Magenta},
+			{Color: "#61afef", Basic: Blue},
+			{Color: "#98c379", Basic: Green},
+			{Color: "#e5c07b", Basic: Yellow},
+			{Color: "#e06c75", Basic: Red},
+		},
+	}
+	// ThemeLight 适合浅色背景，颜色更深
+	ThemeLight = Theme{
+		Name: "light",
+		Roles: map[Role]Spec{
+			Title:    {Color: "#0e7490", Basic: Cyan, Bold: true},
+			Header:   {Color: "#1d4ed8", Basic: Blue, Bold: true},
+			Success:  {Color: "#15803d", Basic: Green},
+			Warning:  {Color: "#b45309", Basic: Yellow},
+			Error:    {Color: "#b91c1c", Basic: Red},
+			Info:     {Color: "#1d4ed8", Basic: Blue},
+			Muted:    {Color: "#6b7280", Basic: BrightBlack},
+			Selected: {Color: "#1d4ed8", Basic: Blue, Reverse: true},
+		},
+		Palette: []Spec{
+			{Color: "#4e79a7", Basic: Blue},
+			{Color: "#f28e2b", Basic: Yellow},
+			{Color: "#e15759", Basic: Red},
+			{Color: "#76b7b2", Basic: Cyan},
+			{Color: "#59a14f", Basic: Green},
+			{Color: "#b07aa1", Basic: Magenta},
+		},
+	}
+	// ThemeMono 不使用颜色，只用粗体突出标题和错误
+	ThemeMono = Theme{
+		Name: "mono",
+		Roles: map[Role]Spec{
+			Title:    {Bold: true},
+			Header:   {Bold: true},
+			Error:    {Bold: true},
+			Selected: {Reverse: true},
+		},
+	}
+)
+
+// Themes 按名称索引的内置主题
+var Themes = map[string]*Theme{
+	ThemeDefault.Name: &ThemeDefault,
+	ThemeLight.Name:   &ThemeLight,
+	ThemeMono.Name:    &ThemeMono,
+}
+
+// ThemeNames 返回内置主题的名称
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ThemeByName 按名称查找主题，空名称返回默认主题
+func ThemeByName(name string) (*Theme, error) {
+	if name == "" {
+		return &ThemeDefault, nil
+	}
+	theme, ok := Themes[strings.ToLower(name)]
+	if !ok {
+		return nil, i18n.Errorf("不支持的主题: %q (可选 %s)", name, strings.Join(ThemeNames(), "/"))
+	}
+	return theme, nil
+}
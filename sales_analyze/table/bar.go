@@ -0,0 +1,38 @@
+package table
+
+import (
+	"math"
+	"strings"
+)
+
+// 条形使用的块字符，报表的终端条形图和进度条共用
+const (
+	barFull  = "█"
+	barEmpty = "░"
+)
+
+// barEighths 不足一格的部分按 1/8 精度绘制
+var barEighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// Bar 绘制显示宽度为 width 的条形，其中长度为 ratio×width 的部分用实心块，剩余部分用浅色块补齐。
+// ratio 超出 [0, 1] 时取最近的端点，NaN 按0处理。
+func Bar(ratio float64, width int) string {
+	if math.IsNaN(ratio) {
+		ratio = 0
+	}
+	ratio = max(0, min(1, ratio))
+	width = max(0, width)
+
+	eighths := int(math.Round(ratio * float64(width*8)))
+	full, rest := eighths/8, eighths%8
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat(barFull, full))
+	used := full
+	if rest > 0 {
+		b.WriteString(barEighths[rest])
+		used++
+	}
+	b.WriteString(strings.Repeat(barEmpty, width-used))
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package table
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestBar(t *testing.T) {
+	tests := []struct {
+		ratio float64
+		width int
+		want  string
+	}{
+		{0, 4, "░░░░"},
+		{1, 4, "████"},
+		{0.5, 4, "██░░"},
+		{0.3, 4, "█▎░░"},
+		{0.999, 4, "████"},
+		{-0.5, 4, "░░░░"},
+		{1.5, 4, "████"},
+		{math.NaN(), 4, "░░░░"},
+		{math.Inf(1), 4, "████"},
+		{math.Inf(-1), 4, "░░░░"},
+		{0.5, 0, ""},
+		{0.5, -3, ""},
+	}
+	for _, tt := range tests {
+		got := Bar(tt.ratio, tt.width)
+		if got != tt.want {
+			t.Errorf("Bar(%v, %d) = %q，应为 %q", tt.ratio, tt.width, got, tt.want)
+		}
+		if width := Width(got); width != max(0, tt.width) {
+			t.Errorf("Bar(%v, %d) 的显示宽度为 %d", tt.ratio, tt.width, width)
+		}
+	}
+}
+
+func TestBarEighths(t *testing.T) {
+	// 宽度为1时 1/8 到 7/8 各对应一个部分块
+	for i := 1; i < 8; i++ {
+		got := Bar(float64(i)/8, 1)
+		if got != barEighths[i] {
+			t.Errorf("Bar(%d/8, 1) = %q，应为 %q", i, got, barEighths[i])
+		}
+		if strings.Contains(got, barEmpty) {
+			t.Errorf("Bar(%d/8, 1) = %q 不应补浅色块", i, got)
+		}
+	}
+}
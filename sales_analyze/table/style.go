@@ -0,0 +1,107 @@
+package table
+
+import (
+	"sort"
+	"strings"
+
+	"sales-analyzer/i18n"
+)
+
+// Line 一条水平分隔线，Fill 为空表示不画这条线
+type Line struct {
+	Left, Fill, Cross, Right string
+}
+
+// Style 表格的边框样式
+type Style struct {
+	Top, Header, Bottom    Line   // 顶部、表头下方、底部的分隔线
+	Left, Separator, Right string // 每行的行首、列间、行尾字符
+}
+
+// 内置边框样式
+var (
+	// StyleASCII 只用ASCII字符，任何终端都能正确显示
+	StyleASCII = Style{
+		Top:    Line{"+", "-", "+", "+"},
+		Header: Line{"+", "-", "+", "+"},
+		Bottom: Line{"+", "-", "+", "+"},
+		Left:   "|", Separator: "|", Right: "|",
+	}
+	// StyleLight 细线制表符
+	StyleLight = Style{
+		Top:    Line{"┌", "─", "┬", "┐"},
+		Header: Line{"├", "─", "┼", "┤"},
+		Bottom: Line{"└", "─", "┴", "┘"},
+		Left:   "│", Separator: "│", Right: "│",
+	}
+	// StyleRounded 圆角细线
+	StyleRounded = Style{
+		Top:    Line{"╭", "─", "┬", "╮"},
+		Header: Line{"├", "─", "┼", "┤"},
+		Bottom: Line{"╰", "─", "┴", "╯"},
+		Left:   "│", Separator: "│", Right: "│",
+	}
+	// StyleDouble 双线外框
+	StyleDouble = Style{
+		Top:    Line{"╔", "═", "╤", "╗"},
+		Header: Line{"╟", "─", "┼", "╢"},
+		Bottom: Line{"╚", "═", "╧", "╝"},
+		Left:   "║", Separator: "│", Right: "║",
+	}
+	// StylePlain 无边框，只在表头下方画一条线
+	StylePlain = Style{
+		Header: Line{"", "─", "─", ""},
+	}
+)
+
+// Styles 按名称索引的内置样式
+var Styles = map[string]*Style{
+	"ascii":   &StyleASCII,
+	"light":   &StyleLight,
+	"rounded": &StyleRounded,
+	"double":  &StyleDouble,
+	"plain":   &StylePlain,
+}
+
+// StyleNames 返回内置样式的名称
+func StyleNames() []string {
+	names := make([]string, 0, len(Styles))
+	for name := range Styles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StyleByName 按名称查找内置样式，空名称返回默认的 StyleASCII
+func StyleByName(name string) (*Style, error) {
+	if name == "" {
+		return &StyleASCII, nil
+	}
+	style, ok := Styles[strings.ToLower(name)]
+	if !ok {
+		return nil, i18n.Errorf("不支持的表格样式: %q (可选 %s)", name, strings.Join(StyleNames(), "/"))
+	}
+	return style, nil
+}
+
+// overhead 边框和内边距占用的宽度
+func (s *Style) overhead(columns int) int {
+	return Width(s.Left) + Width(s.Right) + Width(s.Separator)*(columns-1) + 2*columns
+}
+
+// write 按列宽画出分隔线
+func (l Line) write(b *strings.Builder, widths []int) {
+	if l.Fill == "" {
+		return
+	}
+	b.WriteString(l.Left)
+	for i, width := range widths {
+		if i > 0 {
+			b.WriteString(l.Cross)
+		}
+		b.WriteString(strings.Repeat(l.Fill, width+2))
+	}
+	b.WriteString(l.Right)
+	b.WriteString("\n")
+}
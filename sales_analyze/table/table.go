@@ -0,0 +1,190 @@
+// Package table 在终端中绘制对齐的文本表格。
+//
+// 列宽按显示宽度计算 (见 Width)，中日韩文字、emoji 和带ANSI颜色的单元格都能正确对齐。
+// 支持按列对齐、多种边框样式，以及在表格超出终端宽度时对文本列折行或截断。
+package table
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Align 列的对齐方式
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// Overflow 表格超出 MaxWidth 时，被压缩的列如何处理过长的内容
+type Overflow int
+
+const (
+	OverflowWrap     Overflow = iota // 折成多行
+	OverflowTruncate                 // 截断并以 "…" 结尾
+)
+
+// minColumnWidth 压缩列宽时每列至少保留的宽度
+const minColumnWidth = 4
+
+// Table 一张文本表格。零值可用，默认使用 StyleASCII。
+type Table struct {
+	Header   []string
+	Rows     [][]string
+	Align    []Align // 各列的对齐方式，未设置的列左对齐
+	Style    *Style  // 边框样式，nil 表示 StyleASCII
+	MaxWidth int     // 表格的最大显示宽度，0 表示不限制
+	Overflow Overflow
+}
+
+// New 创建带表头的表格
+func New(header ...string) *Table {
+	return &Table{Header: header}
+}
+
+// Append 追加一行
+func (t *Table) Append(row ...string) {
+	t.Rows = append(t.Rows, row)
+}
+
+// SetAlign 设置第 col 列的对齐方式
+func (t *Table) SetAlign(col int, align Align) {
+	for len(t.Align) <= col {
+		t.Align = append(t.Align, AlignLeft)
+	}
+	t.Align[col] = align
+}
+
+// String 返回绘制好的表格
+func (t *Table) String() string {
+	var b strings.Builder
+	t.Render(&b)
+	return b.String()
+}
+
+// Render 将表格写到 w
+func (t *Table) Render(w io.Writer) error {
+	style := t.Style
+	if style == nil {
+		style = &StyleASCII
+	}
+
+	widths := t.fit(style)
+	var b strings.Builder
+
+	style.Top.write(&b, widths)
+	if len(t.Header) > 0 {
+		t.writeRow(&b, style, t.Header, widths)
+		style.Header.write(&b, widths)
+	}
+	for _, row := range t.Rows {
+		t.writeRow(&b, style, row, widths)
+	}
+	style.Bottom.write(&b, widths)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (t *Table) columns() int {
+	n := len(t.Header)
+	for _, row := range t.Rows {
+		n = max(n, len(row))
+	}
+	return n
+}
+
+func (t *Table) align(col int) Align {
+	if col < len(t.Align) {
+		return t.Align[col]
+	}
+	return AlignLeft
+}
+
+// fit 计算各列宽度。超出 MaxWidth 时，从最宽的文本列开始逐列压缩；
+// 右对齐的数值列只有在文本列都压缩到最小后才会压缩。
+func (t *Table) fit(style *Style) []int {
+	n := t.columns()
+	widths := make([]int, n)
+	measure := func(row []string) {
+		for i, cell := range row {
+			for _, line := range strings.Split(cell, "\n") {
+				widths[i] = max(widths[i], Width(line))
+			}
+		}
+	}
+	measure(t.Header)
+	for _, row := range t.Rows {
+		measure(row)
+	}
+
+	if t.MaxWidth <= 0 || n == 0 {
+		return widths
+	}
+
+	available := t.MaxWidth - style.overhead(n)
+	total := 0
+	for _, w := range widths {
+		total += w
+	}
+
+	// 先压缩文本列，仍然放不下再压缩右对齐的列
+	for _, numeric := range []bool{false, true} {
+		for total > available {
+			widest := -1
+			for i := range n {
+				if (t.align(i) == AlignRight) != numeric || widths[i] <= minColumnWidth {
+					continue
+				}
+				if widest < 0 || widths[i] > widths[widest] {
+					widest = i
+				}
+			}
+			if widest < 0 {
+				break
+			}
+			widths[widest]--
+			total--
+		}
+	}
+	return widths
+}
+
+// writeRow 绘制一行；单元格折行后，这一行占多行
+func (t *Table) writeRow(b *strings.Builder, style *Style, row []string, widths []int) {
+	cells := make([][]string, len(widths))
+	height := 1
+	for i := range widths {
+		var cell string
+		if i < len(row) {
+			cell = row[i]
+		}
+		if t.Overflow == OverflowTruncate {
+			for _, line := range strings.Split(cell, "\n") {
+				cells[i] = append(cells[i], Truncate(line, widths[i]))
+			}
+		} else {
+			cells[i] = Wrap(cell, widths[i])
+		}
+		height = max(height, len(cells[i]))
+	}
+
+	for line := range height {
+		b.WriteString(style.Left)
+		for i, width := range widths {
+			if i > 0 {
+				b.WriteString(style.Separator)
+			}
+			var text string
+			if line < len(cells[i]) {
+				text = cells[i][line]
+			}
+			fmt.Fprintf(b, " %s ", Pad(text, width, t.align(i)))
+		}
+		b.WriteString(style.Right)
+		b.WriteString("\n")
+	}
+}
@@ -0,0 +1,24 @@
+package table
+
+import (
+	"io"
+	"os"
+	"strconv"
+)
+
+// TerminalWidth 返回输出终端的列数，用作 Table.MaxWidth。
+// w 不是终端时返回0 (不限制宽度)，因此输出重定向到文件或管道时表格不会被折行，
+// 即使设置了 COLUMNS 环境变量。w 是终端时优先读取窗口大小，无法读取时使用 COLUMNS。
+func TerminalWidth(w io.Writer) int {
+	columns, ok := terminalColumns(w)
+	if !ok {
+		return 0
+	}
+	if columns > 0 {
+		return columns
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return 0
+}
@@ -0,0 +1,21 @@
+//go:build !unix
+
+package table
+
+import (
+	"io"
+	"os"
+)
+
+// terminalColumns 非unix平台无法读取窗口大小，w 是终端 (字符设备) 时只依赖 COLUMNS 环境变量
+func terminalColumns(w io.Writer) (columns int, ok bool) {
+	file, isFile := w.(*os.File)
+	if !isFile {
+		return 0, false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return 0, false
+	}
+	return 0, info.Mode()&os.ModeCharDevice != 0
+}
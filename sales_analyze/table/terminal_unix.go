@@ -0,0 +1,24 @@
+//go:build unix
+
+package table
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminalColumns 通过 ioctl 读取终端窗口的列数。w 不是终端时 ok 为 false；
+// 是终端但无法得知大小 (如串口终端) 时 columns 为0。
+func terminalColumns(w io.Writer) (columns int, ok bool) {
+	file, isFile := w.(*os.File)
+	if !isFile {
+		return 0, false
+	}
+	size, err := unix.IoctlGetWinsize(int(file.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, false
+	}
+	return int(size.Col), true
+}
@@ -0,0 +1,156 @@
+package table
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
+)
+
+// ansiPattern 匹配ANSI控制序列 (颜色、光标移动等)，它们不占显示宽度
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+// emojiPresentation 变体选择符 U+FE0F，要求前一个字符以emoji样式 (双宽) 显示
+const emojiPresentation = '\uFE0F'
+
+// StripANSI 去掉字符串中的ANSI控制序列
+func StripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+// Width 返回字符串在终端中的显示宽度。
+// 按字素簇计算: 中日韩文字和emoji占2列，组合字符不占列，ANSI控制序列忽略。
+func Width(s string) int {
+	width := 0
+	g := uniseg.NewGraphemes(StripANSI(s))
+	for g.Next() {
+		width += clusterWidth(g.Runes())
+	}
+	return width
+}
+
+// regionalIndicator 判断是否为区域指示符号 (U+1F1E6~U+1F1FF)，两个组成一面国旗
+func regionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// clusterWidth 一个字素簇的显示宽度。国旗 (一对区域指示符号) 在终端中占2列。
+func clusterWidth(runes []rune) int {
+	if len(runes) >= 2 && regionalIndicator(runes[0]) && regionalIndicator(runes[1]) {
+		return 2
+	}
+	width := 0
+	for _, r := range runes {
+		if r == emojiPresentation {
+			return 2
+		}
+		if width == 0 {
+			width = runewidth.RuneWidth(r)
+		}
+	}
+	return width
+}
+
+// Truncate 将字符串截断到 width 列以内，被截断时以 "…" 结尾。
+// 截断后的字符串不保留ANSI控制序列。
+func Truncate(s string, width int) string {
+	if Width(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+
+	const tail = "…"
+	limit := width - Width(tail)
+	var b strings.Builder
+	used := 0
+	g := uniseg.NewGraphemes(StripANSI(s))
+	for g.Next() {
+		w := clusterWidth(g.Runes())
+		if used+w > limit {
+			break
+		}
+		b.WriteString(g.Str())
+		used += w
+	}
+	b.WriteString(tail)
+	return b.String()
+}
+
+// Wrap 将字符串按 width 列折成多行。
+// 有空格时优先在空格处换行，中日韩文字等没有空格的文本在任意字符间换行；
+// 原有的换行符保留。需要折行时不保留ANSI控制序列。
+func Wrap(s string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		if width <= 0 || Width(paragraph) <= width {
+			lines = append(lines, paragraph)
+			continue
+		}
+		lines = append(lines, wrapLine(StripANSI(paragraph), width)...)
+	}
+	return lines
+}
+
+func wrapLine(s string, width int) []string {
+	var lines []string
+	var line []string // 当前行的字素簇
+	used := 0
+	lastSpace := -1 // 当前行中最后一个空格的位置
+
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		cluster := g.Str()
+		w := clusterWidth(g.Runes())
+
+		if used+w > width && len(line) > 0 {
+			if cluster == " " {
+				// 行尾的空格直接丢弃
+				lines = append(lines, strings.Join(line, ""))
+				line, used, lastSpace = nil, 0, -1
+				continue
+			}
+			if lastSpace > 0 {
+				// 在最后一个空格处断开，空格之后的部分移到下一行
+				lines = append(lines, strings.Join(line[:lastSpace], ""))
+				line = append([]string(nil), line[lastSpace+1:]...)
+			} else {
+				lines = append(lines, strings.Join(line, ""))
+				line = nil
+			}
+			used, lastSpace = 0, -1
+			for _, c := range line {
+				used += Width(c)
+			}
+		}
+
+		if cluster == " " {
+			lastSpace = len(line)
+		}
+		line = append(line, cluster)
+		used += w
+	}
+	return append(lines, strings.Join(line, ""))
+}
+
+// Pad 按对齐方式用空格将字符串补齐到 width 列
+func Pad(s string, width int, align Align) string {
+	gap := width - Width(s)
+	if gap <= 0 {
+		return s
+	}
+	switch align {
+	case AlignRight:
+		return strings.Repeat(" ", gap) + s
+	case AlignCenter:
+		left := gap / 2
+		return strings.Repeat(" ", left) + s + strings.Repeat(" ", gap-left)
+	default:
+		return s + strings.Repeat(" ", gap)
+	}
+}
@@ -0,0 +1,123 @@
+package table
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{"空字符串", "", 0},
+		{"ASCII", "sales", 5},
+		{"中文", "华东地区", 8},
+		{"中英混合", "iPhone 手机", 11},
+		{"全角标点", "（合计）", 8},
+		{"组合字符", "café", 4},
+		{"多个组合字符", "à́̂", 1},
+		{"emoji", "📊", 2},
+		{"ZWJ序列", "👨‍👩‍👧", 2},
+		{"VS16", "❤️", 2},
+		{"VS16与文字", "⚠️ 警告", 7},
+		{"肤色", "👍🏽", 2},
+		{"键帽", "1️⃣", 2},
+		{"国旗", "🇨🇳", 2},
+		{"两面国旗", "🇨🇳🇺🇸", 4},
+		{"ANSI颜色", "\x1b[31m红色\x1b[0m", 4},
+	}
+	for _, tt := range tests {
+		if got := Width(tt.s); got != tt.want {
+			t.Errorf("%s: Width(%q) = %d，应为 %d", tt.name, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		align Align
+		want  string
+	}{
+		{"ab", 5, AlignLeft, "ab   "},
+		{"ab", 5, AlignRight, "   ab"},
+		{"ab", 5, AlignCenter, " ab  "},
+		{"华东", 6, AlignLeft, "华东  "},
+		{"华东", 6, AlignRight, "  华东"},
+		{"🇨🇳", 4, AlignLeft, "🇨🇳  "},
+		{"café", 5, AlignRight, " café"},
+		{"\x1b[1m华\x1b[0m", 4, AlignLeft, "\x1b[1m华\x1b[0m  "},
+		{"超过宽度", 4, AlignLeft, "超过宽度"},
+	}
+	for _, tt := range tests {
+		got := Pad(tt.s, tt.width, tt.align)
+		if got != tt.want {
+			t.Errorf("Pad(%q, %d, %v) = %q，应为 %q", tt.s, tt.width, tt.align, got, tt.want)
+		}
+		if Width(got) < tt.width {
+			t.Errorf("Pad(%q, %d, %v) 的宽度为 %d", tt.s, tt.width, tt.align, Width(got))
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"sales", 5, "sales"},
+		{"sales report", 8, "sales r…"},
+		{"华东地区销售", 7, "华东地…"},
+		{"华东地区销售", 8, "华东地…"}, // 第4个汉字放不下，截断后不足8列
+		{"📊📈📉", 5, "📊📈…"},
+		{"👨‍👩‍👧家庭", 4, "👨‍👩‍👧…"},
+		{"🇨🇳🇺🇸🇯🇵", 5, "🇨🇳🇺🇸…"},
+		{"café au lait", 5, "café…"},
+		{"\x1b[31m红色文字\x1b[0m", 5, "红色…"},
+		{"abc", 0, ""},
+		{"abc", -1, ""},
+	}
+	for _, tt := range tests {
+		got := Truncate(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q，应为 %q", tt.s, tt.width, got, tt.want)
+		}
+		if Width(got) > max(tt.width, 0) {
+			t.Errorf("Truncate(%q, %d) 的宽度为 %d", tt.s, tt.width, Width(got))
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  []string
+	}{
+		{"short", 10, []string{"short"}},
+		{"the quick brown fox", 10, []string{"the quick", "brown fox"}},
+		{"a verylongword", 5, []string{"a", "veryl", "ongwo", "rd"}},
+		{"华东地区销售额最高", 8, []string{"华东地区", "销售额最", "高"}},
+		{"华东 地区", 5, []string{"华东", "地区"}},
+		{"第一行\n第二行", 20, []string{"第一行", "第二行"}},
+		{"⚠️ 下降20%", 6, []string{"⚠️", "下降20", "%"}},
+		{"🇨🇳🇺🇸🇯🇵", 4, []string{"🇨🇳🇺🇸", "🇯🇵"}},
+		{"cafécafé", 4, []string{"café", "café"}},
+		{"anything", 0, []string{"anything"}},
+	}
+	for _, tt := range tests {
+		got := Wrap(tt.s, tt.width)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Wrap(%q, %d) = %q，应为 %q", tt.s, tt.width, got, tt.want)
+		}
+		for _, line := range got {
+			if tt.width > 0 && Width(line) > tt.width {
+				t.Errorf("Wrap(%q, %d) 中的行 %q 宽度为 %d", tt.s, tt.width, line, Width(line))
+			}
+		}
+	}
+}
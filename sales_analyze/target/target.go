@@ -0,0 +1,279 @@
+// Package target 加载按地区、产品设定的月度销售目标，
+// 并与实际销售额对比，计算达成率、目标差距和当期的run-rate预测。
+package target
+
+import (
+	"encoding/csv"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sales-analyzer/i18n"
+	"sales-analyzer/sales"
+)
+
+// MonthLayout 目标文件中月份列的格式
+const MonthLayout = "2006-01"
+
+// 目标文件的列名
+var columns = []string{"月份", "地区", "产品", "目标销售额"}
+
+// Target 某月某地区某产品的销售目标
+type Target struct {
+	Month   string
+	Region  string
+	Product string
+	Amount  float64
+}
+
+// Actual 一条实际销售额，通常由销售记录转换而来
+type Actual struct {
+	Date    string
+	Region  string
+	Product string
+	Amount  float64
+}
+
+// Attainment 目标与实际的对比结果
+type Attainment struct {
+	Month   string `json:"month"`
+	Region  string `json:"region"`
+	Product string `json:"product,omitempty"` // 地区汇总时为空
+
+	Target     float64 `json:"target"`
+	Actual     float64 `json:"actual"`
+	Attainment float64 `json:"attainment"` // 达成率 (%)
+	Gap        float64 `json:"gap"`        // 距离目标的差额，负数表示已超额
+
+	ElapsedDays int     `json:"elapsed_days"` // 当期已过天数
+	TotalDays   int     `json:"total_days"`   // 当期总天数
+	Projection  float64 `json:"projection"`
+	// ProjectedAttainment 按当前节奏预计的期末达成率 (%)
+	ProjectedAttainment float64 `json:"projected_attainment"`
+	BehindPace          bool    `json:"behind_pace"`
+}
+
+// Report 目标达成分析结果
+type Report struct {
+	AsOf    string       `json:"as_of"`   // 数据截止日期
+	Regions []Attainment `json:"regions"` // 按地区汇总
+	Details []Attainment `json:"details"` // 按地区+产品
+}
+
+// Analyze 以汇总结果中的最后销售日为截止日期，对比目标与实际
+func Analyze(targets []Target, result *sales.Result) (Report, error) {
+	// 汇总结果的最细粒度已经是 日期+产品+地区，无需回到原始记录
+	var actuals []Actual
+	var lastDate string
+	for _, key := range result.CellKeys() {
+		actuals = append(actuals, Actual{
+			Date:    key.Date,
+			Region:  key.Region,
+			Product: key.Product,
+			Amount:  result.Cells[key].Amount,
+		})
+		lastDate = max(lastDate, key.Date)
+	}
+
+	asOf, err := time.Parse(sales.DateLayout, lastDate)
+	if err != nil {
+		return Report{}, i18n.Errorf("无法识别截止日期 %q: %w", lastDate, err)
+	}
+
+	details := Compare(targets, actuals, asOf)
+	return Report{
+		AsOf:    lastDate,
+		Regions: ByRegion(details),
+		Details: details,
+	}, nil
+}
+
+// Current 是否为尚未结束的当期
+func (a Attainment) Current() bool {
+	return a.ElapsedDays < a.TotalDays
+}
+
+// Load 从CSV文件加载销售目标，表头须包含 月份,地区,产品,目标销售额。
+// 目标销售额须为有限的正数；同一 月份+地区+产品 只能有一行，重复时返回带两处行号的错误。
+func Load(filename string) ([]Target, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, i18n.Errorf("无法打开目标文件: %w", err)
+	}
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, i18n.Errorf("读取目标文件失败: %w", err)
+	}
+
+	if len(rows) < 2 {
+		return nil, i18n.Errorf("目标文件没有数据行")
+	}
+
+	// 按列名定位，允许列的顺序与示例不同
+	index := make(map[string]int)
+	for i, name := range rows[0] {
+		index[strings.TrimSpace(name)] = i
+	}
+	for _, name := range columns {
+		if _, ok := index[name]; !ok {
+			return nil, i18n.Errorf("目标文件缺少列: %s", name)
+		}
+	}
+
+	var targets []Target
+	seen := make(map[key]int) // 每个组合首次出现的行号
+	for i, row := range rows[1:] {
+		line := i + 2
+		month := strings.TrimSpace(row[index["月份"]])
+		if _, err := time.Parse(MonthLayout, month); err != nil {
+			return nil, i18n.Errorf("第%d行月份格式错误: %q", line, month)
+		}
+
+		raw := strings.TrimSpace(row[index["目标销售额"]])
+		amount, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, i18n.Errorf("第%d行目标销售额错误: %w", line, err)
+		}
+		// ParseFloat 接受 NaN 和 Inf，它们会让达成率等派生指标无法计算和输出
+		if math.IsNaN(amount) || math.IsInf(amount, 0) || amount <= 0 {
+			return nil, i18n.Errorf("第%d行目标销售额必须为正数: %q", line, raw)
+		}
+
+		t := Target{
+			Month:   month,
+			Region:  strings.TrimSpace(row[index["地区"]]),
+			Product: strings.TrimSpace(row[index["产品"]]),
+			Amount:  amount,
+		}
+		k := key{t.Month, t.Region, t.Product}
+		if first, ok := seen[k]; ok {
+			return nil, i18n.Errorf("第%d行与第%d行重复: %s %s %s", line, first, t.Month, t.Region, t.Product)
+		}
+		seen[k] = line
+		targets = append(targets, t)
+	}
+
+	return targets, nil
+}
+
+type key struct {
+	month, region, product string
+}
+
+// Compare 将目标与实际销售额按 月份+地区+产品 关联。
+// asOf 为数据截止日期，它所在的月份视为当期，按已过天数推算期末销售额；
+// 已结束的月份预测值即为实际值。只统计有目标的组合。
+func Compare(targets []Target, actuals []Actual, asOf time.Time) []Attainment {
+	actualMap := make(map[key]float64)
+	for _, actual := range actuals {
+		date, err := time.Parse(sales.DateLayout, actual.Date)
+		if err != nil {
+			continue
+		}
+		k := key{date.Format(MonthLayout), actual.Region, actual.Product}
+		actualMap[k] += actual.Amount
+	}
+
+	var results []Attainment
+	for _, t := range targets {
+		a := Attainment{
+			Month:   t.Month,
+			Region:  t.Region,
+			Product: t.Product,
+			Target:  t.Amount,
+			Actual:  actualMap[key{t.Month, t.Region, t.Product}],
+		}
+		a.ElapsedDays, a.TotalDays = progress(t.Month, asOf)
+		evaluate(&a)
+		results = append(results, a)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Month != results[j].Month {
+			return results[i].Month < results[j].Month
+		}
+		if results[i].Region != results[j].Region {
+			return results[i].Region < results[j].Region
+		}
+		return results[i].Product < results[j].Product
+	})
+
+	return results
+}
+
+// ByRegion 将产品级对比结果按 月份+地区 汇总
+func ByRegion(items []Attainment) []Attainment {
+	regionMap := make(map[key]*Attainment)
+	var keys []key
+
+	for _, item := range items {
+		k := key{month: item.Month, region: item.Region}
+		if summary, exists := regionMap[k]; exists {
+			summary.Target += item.Target
+			summary.Actual += item.Actual
+		} else {
+			regionMap[k] = &Attainment{
+				Month:       item.Month,
+				Region:      item.Region,
+				Target:      item.Target,
+				Actual:      item.Actual,
+				ElapsedDays: item.ElapsedDays,
+				TotalDays:   item.TotalDays,
+			}
+			keys = append(keys, k)
+		}
+	}
+
+	var results []Attainment
+	for _, k := range keys {
+		summary := regionMap[k]
+		evaluate(summary)
+		results = append(results, *summary)
+	}
+
+	return results
+}
+
+// progress 返回月份在 asOf 时已过的天数和总天数。
+// 未来月份已过天数为0，已结束月份两者相等。
+func progress(month string, asOf time.Time) (elapsed, total int) {
+	start, err := time.Parse(MonthLayout, month)
+	if err != nil {
+		return 0, 0
+	}
+	end := start.AddDate(0, 1, 0)
+	total = int(end.Sub(start).Hours() / 24)
+
+	day := time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	switch {
+	case day.Before(start):
+		return 0, total
+	case !day.Before(end):
+		return total, total
+	default:
+		return day.Day(), total
+	}
+}
+
+// evaluate 根据目标、实际和期间进度计算派生指标
+func evaluate(a *Attainment) {
+	a.Gap = a.Target - a.Actual
+
+	a.Projection = a.Actual
+	if a.ElapsedDays > 0 && a.ElapsedDays < a.TotalDays {
+		a.Projection = a.Actual / float64(a.ElapsedDays) * float64(a.TotalDays)
+	}
+
+	if a.Target > 0 {
+		a.Attainment = a.Actual / a.Target * 100
+		a.ProjectedAttainment = a.Projection / a.Target * 100
+	}
+
+	// 尚未开始的月份不判断进度
+	a.BehindPace = a.ElapsedDays > 0 && a.Projection < a.Target
+}
@@ -0,0 +1,217 @@
+package target
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTargets(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "targets.csv")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		count   int
+		err     string // 错误信息应包含的内容，为空时不应出错
+	}{
+		{
+			name:    "正常",
+			content: "月份,地区,产品,目标销售额\n2025-01,华北,手机,1800000\n2025-01,华北,平板,1200000\n",
+			count:   2,
+		},
+		{
+			name:    "列顺序不同",
+			content: "产品,目标销售额,月份,地区\n手机,1800000,2025-01,华北\n",
+			count:   1,
+		},
+		{
+			name:    "重复行",
+			content: "月份,地区,产品,目标销售额\n2025-01,华北,手机,1800000\n2025-01,华东,手机,1500000\n2025-01, 华北 ,手机,900000\n",
+			err:     "第4行与第2行重复",
+		},
+		{
+			name:    "目标为负数",
+			content: "月份,地区,产品,目标销售额\n2025-01,华北,手机,-100\n",
+			err:     "第2行目标销售额必须为正数",
+		},
+		{
+			name:    "目标为0",
+			content: "月份,地区,产品,目标销售额\n2025-01,华北,手机,0\n",
+			err:     "第2行目标销售额必须为正数",
+		},
+		{
+			name:    "目标为NaN",
+			content: "月份,地区,产品,目标销售额\n2025-01,华北,手机,100\n2025-01,华东,手机,NaN\n",
+			err:     "第3行目标销售额必须为正数",
+		},
+		{
+			name:    "目标为Inf",
+			content: "月份,地区,产品,目标销售额\n2025-01,华北,手机,+Inf\n",
+			err:     "第2行目标销售额必须为正数",
+		},
+		{
+			name:    "目标不是数字",
+			content: "月份,地区,产品,目标销售额\n2025-01,华北,手机,很多\n",
+			err:     "第2行目标销售额错误",
+		},
+		{
+			name:    "月份格式错误",
+			content: "月份,地区,产品,目标销售额\n2025/01,华北,手机,100\n",
+			err:     "第2行月份格式错误",
+		},
+		{
+			name:    "缺少列",
+			content: "月份,地区,目标销售额\n2025-01,华北,1800000\n",
+			err:     "缺少列: 产品",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, err := Load(writeTargets(t, tt.content))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Load() 错误 = %v，应包含 %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() 错误: %v", err)
+			}
+			if len(targets) != tt.count {
+				t.Errorf("len(targets) = %d，应为 %d", len(targets), tt.count)
+			}
+		})
+	}
+}
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestProgress(t *testing.T) {
+	tests := []struct {
+		month          string
+		asOf           string
+		elapsed, total int
+	}{
+		{"2025-01", "2024-12-31", 0, 31}, // 尚未开始
+		{"2025-01", "2025-01-01", 1, 31},
+		{"2025-01", "2025-01-15", 15, 31},
+		{"2025-01", "2025-01-31", 31, 31}, // 最后一天，当月已完整
+		{"2025-01", "2025-02-01", 31, 31}, // 已结束
+		{"2025-02", "2025-02-10", 10, 28},
+		{"2024-02", "2024-02-29", 29, 29}, // 闰年
+		{"2024-12", "2025-01-01", 31, 31}, // 跨年
+		{"2025-13", "2025-01-01", 0, 0},   // 无效月份
+	}
+	for _, tt := range tests {
+		elapsed, total := progress(tt.month, date(tt.asOf))
+		if elapsed != tt.elapsed || total != tt.total {
+			t.Errorf("progress(%s, %s) = %d/%d，应为 %d/%d", tt.month, tt.asOf, elapsed, total, tt.elapsed, tt.total)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name                  string
+		target, actual        float64
+		elapsed, total        int
+		projection            float64
+		attainment, projected float64
+		behind                bool
+	}{
+		// 已过10天完成1000，按节奏31天为3100，正好达成
+		{"按节奏达成", 3100, 1000, 10, 31, 3100, 1000.0 / 3100 * 100, 100, false},
+		{"落后于节奏", 3100, 900, 10, 31, 2790, 900.0 / 3100 * 100, 90, true},
+		{"超前于节奏", 1000, 600, 15, 30, 1200, 60, 120, false},
+		{"尚未开始", 1000, 0, 0, 31, 0, 0, 0, false},
+		{"最后一天未达成", 1000, 800, 31, 31, 800, 80, 80, true},
+		{"已结束并超额", 1000, 1500, 28, 28, 1500, 150, 150, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := Attainment{Target: tt.target, Actual: tt.actual, ElapsedDays: tt.elapsed, TotalDays: tt.total}
+			evaluate(&a)
+			if !near(a.Projection, tt.projection) {
+				t.Errorf("Projection = %v，应为 %v", a.Projection, tt.projection)
+			}
+			if !near(a.Attainment, tt.attainment) {
+				t.Errorf("Attainment = %v，应为 %v", a.Attainment, tt.attainment)
+			}
+			if !near(a.ProjectedAttainment, tt.projected) {
+				t.Errorf("ProjectedAttainment = %v，应为 %v", a.ProjectedAttainment, tt.projected)
+			}
+			if a.Gap != tt.target-tt.actual {
+				t.Errorf("Gap = %v，应为 %v", a.Gap, tt.target-tt.actual)
+			}
+			if a.BehindPace != tt.behind {
+				t.Errorf("BehindPace = %v，应为 %v", a.BehindPace, tt.behind)
+			}
+		})
+	}
+}
+
+// rate 与 evaluate 相同的运算顺序计算百分比，常量表达式按精确值计算，结果可能与之相差一个ulp
+func rate(actual, target float64) float64 {
+	return actual / target * 100
+}
+
+func near(a, b float64) bool {
+	return a-b < 1e-9 && b-a < 1e-9
+}
+
+func TestCompare(t *testing.T) {
+	targets := []Target{
+		{Month: "2025-02", Region: "华北", Product: "手机", Amount: 2800},
+		{Month: "2025-01", Region: "华北", Product: "手机", Amount: 3100},
+		{Month: "2025-01", Region: "华北", Product: "电脑", Amount: 1000},
+		{Month: "2025-03", Region: "华北", Product: "手机", Amount: 1000},
+	}
+	actuals := []Actual{
+		{Date: "2025-01-05", Region: "华北", Product: "手机", Amount: 2000},
+		{Date: "2025-01-20", Region: "华北", Product: "手机", Amount: 1500},
+		{Date: "2025-02-01", Region: "华北", Product: "手机", Amount: 300},
+		{Date: "2025-02-10", Region: "华北", Product: "手机", Amount: 700},
+		{Date: "2025-02-10", Region: "华东", Product: "手机", Amount: 999}, // 没有目标，不统计
+		{Date: "坏日期", Region: "华北", Product: "手机", Amount: 999},        // 无法识别，跳过
+	}
+
+	got := Compare(targets, actuals, date("2025-02-10"))
+	want := []Attainment{
+		{Month: "2025-01", Region: "华北", Product: "手机", Target: 3100, Actual: 3500, Gap: -400,
+			ElapsedDays: 31, TotalDays: 31, Projection: 3500, Attainment: rate(3500, 3100), ProjectedAttainment: rate(3500, 3100)},
+		{Month: "2025-01", Region: "华北", Product: "电脑", Target: 1000, Actual: 0, Gap: 1000,
+			ElapsedDays: 31, TotalDays: 31, Projection: 0, BehindPace: true},
+		// 当期按10天1000的节奏推算28天为2800
+		{Month: "2025-02", Region: "华北", Product: "手机", Target: 2800, Actual: 1000, Gap: 1800,
+			ElapsedDays: 10, TotalDays: 28, Projection: 2800, Attainment: rate(1000, 2800), ProjectedAttainment: 100},
+		{Month: "2025-03", Region: "华北", Product: "手机", Target: 1000, Gap: 1000, TotalDays: 31},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare() =\n%+v\n应为\n%+v", got, want)
+	}
+
+	regions := ByRegion(got)
+	if len(regions) != 3 {
+		t.Fatalf("ByRegion() 有 %d 项，应为 3 项", len(regions))
+	}
+	if january := regions[0]; january.Target != 4100 || january.Actual != 3500 || !january.BehindPace {
+		t.Errorf("1月华北汇总 = %+v，应为目标 4100、实际 3500、落后于节奏", january)
+	}
+}
@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"strings"
+
+	"sales-analyzer/diff"
+	"sales-analyzer/i18n"
+	"sales-analyzer/sales"
+)
+
+// filter 筛选栏中输入的条件。
+//
+// 条件由空格分隔，形如 "product:A region:华东 date:2025-01-01..2025-01-02"：
+// 指定字段的条件匹配该字段 (产品、地区按子串，日期按区间或前缀)，
+// 同一字段的多个条件满足其一即可，不同字段之间需要同时满足；
+// 不带字段的词在产品、地区、日期中任意一处出现即匹配，多个词需要同时满足。
+type filter struct {
+	text    string
+	fields  map[string][]string // 字段名 → 条件
+	periods []diff.Period       // 日期区间条件
+	terms   []string            // 不带字段的词
+}
+
+// 筛选条件的字段名，中英文都可以使用
+var filterFields = map[string]string{
+	"product": diff.FieldProduct,
+	"产品":      diff.FieldProduct,
+	"region":  diff.FieldRegion,
+	"地区":      diff.FieldRegion,
+	"date":    diff.FieldDate,
+	"日期":      diff.FieldDate,
+}
+
+// parseFilter 解析筛选条件，空文本表示不筛选
+func parseFilter(text string) (*filter, error) {
+	f := &filter{text: strings.TrimSpace(text), fields: make(map[string][]string)}
+	for _, word := range strings.Fields(f.text) {
+		name, value, found := strings.Cut(word, ":")
+		if !found {
+			f.terms = append(f.terms, strings.ToLower(word))
+			continue
+		}
+
+		field, ok := filterFields[strings.ToLower(name)]
+		if !ok {
+			return nil, i18n.Errorf("不支持的筛选字段: %q", name)
+		}
+		if value == "" {
+			return nil, i18n.Errorf("筛选条件缺少值: %q", word)
+		}
+		// 完整的日期或日期区间按区间比较，其余按前缀匹配，如 2025-01
+		if field == diff.FieldDate {
+			if period, err := diff.ParsePeriod(value); err == nil {
+				f.periods = append(f.periods, period)
+				continue
+			}
+		}
+		f.fields[field] = append(f.fields[field], strings.ToLower(value))
+	}
+	return f, nil
+}
+
+// empty 是否没有任何条件
+func (f *filter) empty() bool {
+	return len(f.fields) == 0 && len(f.periods) == 0 && len(f.terms) == 0
+}
+
+// apply 返回满足条件的记录
+func (f *filter) apply(records []sales.Record) []sales.Record {
+	if f.empty() {
+		return records
+	}
+	var matched []sales.Record
+	for _, record := range records {
+		if f.match(record) {
+			matched = append(matched, record)
+		}
+	}
+	return matched
+}
+
+func (f *filter) match(record sales.Record) bool {
+	product := strings.ToLower(record.Product)
+	region := strings.ToLower(record.Region)
+
+	if values, ok := f.fields[diff.FieldProduct]; ok && !anyOf(values, func(v string) bool {
+		return strings.Contains(product, v)
+	}) {
+		return false
+	}
+	if values, ok := f.fields[diff.FieldRegion]; ok && !anyOf(values, func(v string) bool {
+		return strings.Contains(region, v)
+	}) {
+		return false
+	}
+
+	// 日期的区间条件和前缀条件合在一起，满足其一即可
+	prefixes := f.fields[diff.FieldDate]
+	if len(prefixes) > 0 || len(f.periods) > 0 {
+		inPeriod := anyOf(f.periods, func(p diff.Period) bool {
+			return record.Date >= p.From && record.Date <= p.To
+		})
+		if !inPeriod && !anyOf(prefixes, func(v string) bool {
+			return strings.HasPrefix(record.Date, v)
+		}) {
+			return false
+		}
+	}
+
+	for _, term := range f.terms {
+		if !strings.Contains(product, term) && !strings.Contains(region, term) && !strings.Contains(record.Date, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func anyOf[T any](values []T, match func(T) bool) bool {
+	for _, v := range values {
+		if match(v) {
+			return true
+		}
+	}
+	return false
+}
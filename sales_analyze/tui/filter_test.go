@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+
+	"sales-analyzer/diff"
+	"sales-analyzer/sales"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    *filter
+		wantErr bool
+	}{
+		{"", &filter{fields: map[string][]string{}}, false},
+		{"  product:手机   region:华东 ", &filter{text: "product:手机   region:华东",
+			fields: map[string][]string{"product": {"手机"}, "region": {"华东"}}}, false},
+		{"产品:Phone 产品:电脑", &filter{text: "产品:Phone 产品:电脑",
+			fields: map[string][]string{"product": {"phone", "电脑"}}}, false},
+		{"DATE:2025-01-01..2025-01-31 日期:2025-02-03", &filter{text: "DATE:2025-01-01..2025-01-31 日期:2025-02-03",
+			fields:  map[string][]string{},
+			periods: []diff.Period{{From: "2025-01-01", To: "2025-01-31"}, {From: "2025-02-03", To: "2025-02-03"}}}, false},
+		{"date:2025-01", &filter{text: "date:2025-01", fields: map[string][]string{"date": {"2025-01"}}}, false},
+		{"Phone 华东", &filter{text: "Phone 华东", fields: map[string][]string{}, terms: []string{"phone", "华东"}}, false},
+		{"price:100", nil, true},
+		{"product:", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := parseFilter(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFilter(%q) 错误 = %v，应为错误 %v", tt.text, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFilter(%q) = %+v，应为 %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	records := []sales.Record{
+		{Date: "2025-01-01", Product: "Phone Pro", Region: "华东"},
+		{Date: "2025-01-15", Product: "电脑", Region: "华南"},
+		{Date: "2025-02-01", Product: "Phone", Region: "华南"},
+		{Date: "2025-02-10", Product: "平板", Region: "华北"},
+	}
+	tests := []struct {
+		text string
+		want []int // 匹配的记录序号
+	}{
+		{"", []int{0, 1, 2, 3}},
+		{"product:phone", []int{0, 2}},
+		{"product:PHONE region:华南", []int{2}},
+		{"region:华东 region:华北", []int{0, 3}},
+		{"date:2025-01", []int{0, 1}},
+		{"date:2025-01-10..2025-02-01", []int{1, 2}},
+		{"date:2025-01-01 date:2025-02", []int{0, 2, 3}},
+		{"华南", []int{1, 2}},
+		{"phone 华南", []int{2}},
+		{"02-10", []int{3}},
+		{"product:手表", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			f, err := parseFilter(tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int
+			for i, record := range records {
+				if f.match(record) {
+					got = append(got, i)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q 匹配 %v，应为 %v", tt.text, got, tt.want)
+			}
+			if n := len(f.apply(records)); n != len(tt.want) {
+				t.Errorf("apply() 返回 %d 条记录，应为 %d", n, len(tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+
+	"sales-analyzer/report"
+	"sales-analyzer/style"
+	"sales-analyzer/table"
+)
+
+// 表头中的排序标记
+const (
+	sortAsc  = " ▲"
+	sortDesc = " ▼"
+)
+
+// 选中行前的光标标记，没有颜色时也能看出选中的行
+const (
+	cursorMark = "▶ "
+	cursorNone = "  "
+)
+
+// grid 一张可排序、可滚动的表格，数据来自 report.Table
+type grid struct {
+	title   string
+	columns []string
+	rows    [][]report.Cell
+	order   []int // 排序后的行号，未排序时为原始顺序
+
+	sortCol int  // 排序列，-1 表示保持报表原有顺序
+	desc    bool // 是否降序
+	cursor  int  // 选中行在 order 中的位置
+	offset  int  // 第一行可见行在 order 中的位置
+}
+
+// newGrid 创建空表格，数据由 load 填入
+func newGrid() *grid {
+	return &grid{sortCol: -1}
+}
+
+// load 替换表格数据，保留排序方式并把光标限制在有效范围内
+func (g *grid) load(t report.Table) {
+	g.title, g.columns, g.rows = t.Title, t.Columns, t.Rows
+	g.sort()
+	g.move(0)
+}
+
+// selected 返回选中行，没有数据时返回nil
+func (g *grid) selected() []report.Cell {
+	if len(g.order) == 0 {
+		return nil
+	}
+	return g.rows[g.order[g.cursor]]
+}
+
+// move 移动光标 delta 行
+func (g *grid) move(delta int) {
+	g.cursor = max(0, min(g.cursor+delta, len(g.order)-1))
+}
+
+// nextSort 切换到下一个 (step=1) 或上一个 (step=-1) 排序列，
+// 依次经过各列和 "不排序"；数值列默认降序，文本列默认升序
+func (g *grid) nextSort(step int) {
+	n := len(g.columns) + 1
+	g.sortCol = (g.sortCol+1+step+n)%n - 1
+	g.desc = g.sortCol >= 0 && g.numeric(g.sortCol)
+	g.sort()
+}
+
+// reverse 反转当前的排序方向
+func (g *grid) reverse() {
+	if g.sortCol < 0 {
+		return
+	}
+	g.desc = !g.desc
+	g.sort()
+}
+
+func (g *grid) sort() {
+	g.order = make([]int, len(g.rows))
+	for i := range g.order {
+		g.order[i] = i
+	}
+	if g.sortCol < 0 {
+		return
+	}
+	col := g.sortCol
+	sort.SliceStable(g.order, func(i, j int) bool {
+		a, b := g.rows[g.order[i]][col], g.rows[g.order[j]][col]
+		if g.desc {
+			return less(b, a)
+		}
+		return less(a, b)
+	})
+}
+
+// numeric 第 col 列是否为数值列
+func (g *grid) numeric(col int) bool {
+	for _, row := range g.rows {
+		if row[col].Value != nil {
+			return row[col].Kind.Numeric()
+		}
+	}
+	return false
+}
+
+// less 比较两个单元格: 数值按大小，文本按字符串，没有数据的单元格排在最前
+func less(a, b report.Cell) bool {
+	if a.Value == nil || b.Value == nil {
+		return a.Value == nil && b.Value != nil
+	}
+	x, xok := number(a.Value)
+	y, yok := number(b.Value)
+	if xok && yok {
+		return x < y
+	}
+	return a.String() < b.String()
+}
+
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// render 绘制表头和最多 height 行数据，宽度不超过 width。
+// 列宽按全部数据计算，滚动时不会跳动；选中行在 active 时高亮。
+// 没有列 (章节中没有表格) 时不绘制任何内容。
+func (g *grid) render(s *style.Styler, width, height int, active bool) []string {
+	if len(g.columns) == 0 {
+		return nil
+	}
+	header := make([]string, len(g.columns))
+	for i, column := range g.columns {
+		header[i] = column
+		switch {
+		case i != g.sortCol:
+		case g.desc:
+			header[i] += sortDesc
+		default:
+			header[i] += sortAsc
+		}
+	}
+
+	tw := table.New(header...)
+	tw.Style = &table.StylePlain
+	tw.Overflow = table.OverflowTruncate
+	tw.MaxWidth = width - table.Width(cursorMark)
+	for i := range g.columns {
+		if g.numeric(i) {
+			tw.SetAlign(i, table.AlignRight)
+		}
+	}
+	for _, index := range g.order {
+		cells := make([]string, len(g.rows[index]))
+		for i, cell := range g.rows[index] {
+			cells[i] = cell.String()
+		}
+		tw.Append(cells...)
+	}
+
+	// 表头和分隔线各一行，之后每行数据一行
+	lines := strings.Split(strings.TrimSuffix(tw.String(), "\n"), "\n")
+	if len(lines) < 2 {
+		return nil
+	}
+	out := []string{
+		cursorNone + s.Paint(style.Header, lines[0]),
+		cursorNone + s.Paint(style.Muted, lines[1]),
+	}
+	body := lines[2:]
+
+	height = max(1, height)
+	if g.cursor < g.offset {
+		g.offset = g.cursor
+	}
+	if g.cursor >= g.offset+height {
+		g.offset = g.cursor - height + 1
+	}
+	g.offset = max(0, min(g.offset, len(body)-height))
+
+	for i := g.offset; i < min(len(body), g.offset+height); i++ {
+		if active && i == g.cursor {
+			out = append(out, cursorMark+s.Paint(style.Selected, body[i]))
+			continue
+		}
+		out = append(out, cursorNone+body[i])
+	}
+	return out
+}
@@ -0,0 +1,337 @@
+// Package tui 实现浏览销售数据的全屏终端界面。
+//
+// 界面分为总览、产品、地区、日期四个标签页，表格可以按任意列排序、用键盘滚动；
+// 筛选栏中输入的条件即时生效 (见 filter)；在产品、地区或日期上按回车，
+// 可以查看它在其他维度上的明细。所有数据在内存中重新汇总，不需要重新运行程序。
+//
+// 表格内容直接复用 report 包的分析章节，文本和数字格式随当前语言变化，
+// 颜色随 style 包的当前主题变化。
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sales-analyzer/i18n"
+	"sales-analyzer/report"
+	"sales-analyzer/sales"
+	"sales-analyzer/style"
+	"sales-analyzer/table"
+)
+
+// 没有收到终端尺寸时使用的默认大小
+const (
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+// levelRoles 说明文字的级别对应的样式角色
+var levelRoles = map[report.Level]style.Role{
+	report.LevelInfo:    style.Info,
+	report.LevelSuccess: style.Success,
+	report.LevelWarning: style.Warning,
+}
+
+// Run 在全屏界面中浏览 records，source 为数据来源，显示在标题栏。
+// 用户按 q 或 Ctrl+C 退出后返回。
+func Run(records []sales.Record, source string) error {
+	m := newModel(records, source, style.For(os.Stdout))
+	if _, err := tea.NewProgram(m, tea.WithAltScreen()).Run(); err != nil {
+		return i18n.Errorf("交互界面运行失败: %w", err)
+	}
+	return nil
+}
+
+type model struct {
+	records []sales.Record
+	source  string
+	style   *style.Styler
+
+	filter *filter
+	shown  int // 满足筛选条件的记录数
+	tabs   []*tab
+	active int
+	drill  *drill // 下钻视图，nil 表示在主界面
+
+	// 筛选栏编辑状态
+	editing bool
+	input   []rune
+	saved   string // 开始编辑前的条件，按 Esc 时恢复
+	err     error  // 输入的条件无法解析
+
+	width, height int
+}
+
+func newModel(records []sales.Record, source string, s *style.Styler) *model {
+	m := &model{
+		records: records,
+		source:  source,
+		style:   s,
+		filter:  &filter{},
+		tabs:    mainTabs(),
+		width:   defaultWidth,
+		height:  defaultHeight,
+	}
+	m.refresh()
+	return m
+}
+
+// refresh 按当前筛选条件重新汇总，更新所有标签页和下钻视图
+func (m *model) refresh() {
+	records := m.filter.apply(m.records)
+	m.shown = len(records)
+
+	result := sales.Aggregate(records, sales.Options{})
+	for _, t := range m.tabs {
+		t.refresh(result)
+	}
+	if m.drill != nil {
+		detail := sales.Aggregate(m.drill.apply(records), sales.Options{})
+		for _, t := range m.drill.tabs {
+			t.refresh(detail)
+		}
+	}
+}
+
+// current 返回正在显示的标签页
+func (m *model) current() *tab {
+	if m.drill != nil {
+		return m.drill.tabs[m.drill.active]
+	}
+	return m.tabs[m.active]
+}
+
+// switchTab 切换到第 i 个标签页，超出范围时循环
+func (m *model) switchTab(i int) {
+	if m.drill != nil {
+		n := len(m.drill.tabs)
+		m.drill.active = (i%n + n) % n
+		return
+	}
+	n := len(m.tabs)
+	m.active = (i%n + n) % n
+}
+
+func (m *model) activeIndex() int {
+	if m.drill != nil {
+		return m.drill.active
+	}
+	return m.active
+}
+
+// Init 实现 tea.Model
+func (m *model) Init() tea.Cmd {
+	return nil
+}
+
+// Update 实现 tea.Model
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.editing {
+			m.editKey(msg)
+			return m, nil
+		}
+		return m, m.key(msg)
+	}
+	return m, nil
+}
+
+// key 处理浏览状态下的按键
+func (m *model) key(msg tea.KeyMsg) tea.Cmd {
+	g := m.current().grid
+	switch key := msg.String(); key {
+	case "q":
+		return tea.Quit
+	case "tab", "right", "l":
+		m.switchTab(m.activeIndex() + 1)
+	case "shift+tab", "left", "h":
+		m.switchTab(m.activeIndex() - 1)
+	case "1", "2", "3", "4":
+		m.switchTab(int(key[0] - '1'))
+	case "up", "k":
+		g.move(-1)
+	case "down", "j":
+		g.move(1)
+	case "pgup":
+		g.move(-m.pageSize())
+	case "pgdown", " ":
+		g.move(m.pageSize())
+	case "home", "g":
+		g.move(-len(g.order))
+	case "end", "G":
+		g.move(len(g.order))
+	case "s":
+		g.nextSort(1)
+	case "S":
+		g.nextSort(-1)
+	case "r":
+		g.reverse()
+	case "/":
+		m.editing, m.saved, m.input = true, m.filter.text, []rune(m.filter.text)
+	case "enter":
+		m.drillDown()
+	case "esc", "backspace":
+		// 先退出下钻视图，在主界面时清除筛选条件
+		if m.drill != nil {
+			m.drill = nil
+		} else if !m.filter.empty() {
+			m.setFilter("")
+		}
+	}
+	return nil
+}
+
+// editKey 处理编辑筛选栏时的按键，条件随输入即时生效
+func (m *model) editKey(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.editing = false
+		if m.err != nil {
+			m.setFilter(m.saved)
+		}
+		return
+	case tea.KeyEsc:
+		m.editing = false
+		m.setFilter(m.saved)
+		return
+	case tea.KeyBackspace:
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	case tea.KeyCtrlU:
+		m.input = nil
+	case tea.KeySpace:
+		m.input = append(m.input, ' ')
+	case tea.KeyRunes:
+		m.input = append(m.input, msg.Runes...)
+	default:
+		return
+	}
+	m.setFilter(string(m.input))
+}
+
+// setFilter 解析并应用筛选条件，无法解析时保留原来的条件并显示错误
+func (m *model) setFilter(text string) {
+	f, err := parseFilter(text)
+	m.err = err
+	if err != nil {
+		return
+	}
+	m.filter = f
+	m.refresh()
+}
+
+// drillDown 查看选中行在其他维度上的明细
+func (m *model) drillDown() {
+	t := m.current()
+	row := t.grid.selected()
+	if m.drill != nil || t.field == "" || row == nil {
+		return
+	}
+	m.drill = newDrill(t.field, row[0].String())
+	m.refresh()
+}
+
+// 标题栏、标签栏、筛选栏、空行、表头两行和状态栏占用的行数
+const chromeLines = 7
+
+// pageSize 表格可以显示的数据行数
+func (m *model) pageSize() int {
+	notes := len(m.current().notes)
+	if notes > 0 {
+		notes++ // 表格和说明之间空一行
+	}
+	return max(1, m.height-chromeLines-notes)
+}
+
+// View 实现 tea.Model
+func (m *model) View() string {
+	s := m.style
+	t := m.current()
+
+	var lines []string
+	lines = append(lines, m.fit(s.Paint(style.Title, i18n.T("📊 销售数据浏览")), i18n.T("数据来源: ")+m.source))
+	lines = append(lines, m.tabBar())
+	lines = append(lines, m.filterBar(), "")
+	lines = append(lines, t.grid.render(s, m.width, m.pageSize(), true)...)
+
+	// 数据行不足一页时补足空行，说明和状态栏固定在底部
+	for len(lines) < chromeLines-1+m.pageSize() {
+		lines = append(lines, "")
+	}
+	if len(t.notes) > 0 {
+		lines = append(lines, "")
+		for _, note := range t.notes {
+			lines = append(lines, s.Paint(levelRoles[note.Level], table.Truncate(note.Text, m.width)))
+		}
+	}
+	lines = append(lines, m.statusBar())
+	return strings.Join(lines, "\n")
+}
+
+// fit 拼接已着色的标题和普通文本，超出宽度的部分截掉普通文本
+func (m *model) fit(title, text string) string {
+	room := m.width - table.Width(title) - 2
+	if room <= 0 {
+		return title
+	}
+	return title + "  " + m.style.Paint(style.Muted, table.Truncate(text, room))
+}
+
+// tabBar 标签栏，下钻时先显示下钻对象
+func (m *model) tabBar() string {
+	tabs, active := m.tabs, m.active
+	var b strings.Builder
+	if m.drill != nil {
+		tabs, active = m.drill.tabs, m.drill.active
+		b.WriteString(m.style.Paint(style.Header, m.drill.title) + " › ")
+	}
+	for i, t := range tabs {
+		// 选中的标签页加方括号，没有颜色时也能分辨
+		label := fmt.Sprintf(" %d %s ", i+1, t.title)
+		if i == active {
+			label = m.style.Paint(style.Selected, fmt.Sprintf("[%d %s]", i+1, t.title))
+		}
+		b.WriteString(label)
+	}
+	return b.String()
+}
+
+// filterBar 筛选栏: 编辑时显示光标，有错误时显示错误，未筛选时显示提示
+func (m *model) filterBar() string {
+	label := "🔍 " + i18n.T("筛选: ")
+	switch {
+	case m.editing && m.err != nil:
+		return label + string(m.input) + "█  " + m.style.Paint(style.Error, m.err.Error())
+	case m.editing:
+		return label + string(m.input) + "█"
+	case m.filter.empty():
+		return label + m.style.Paint(style.Muted, table.Truncate(i18n.T("按 / 输入条件，如 product:手机 region:华东 date:2025-01-01..2025-01-02"), m.width-table.Width(label)))
+	default:
+		return label + m.style.Paint(style.Info, m.filter.text)
+	}
+}
+
+// statusBar 状态栏: 记录数和按键说明
+func (m *model) statusBar() string {
+	count := i18n.Sprintf("显示 %d / %d 条记录", m.shown, len(m.records))
+	var help string
+	switch {
+	case m.editing:
+		help = i18n.T("回车 确定  Esc 取消  Ctrl+U 清空")
+	case m.drill != nil:
+		help = i18n.T("←/→ 切换  ↑/↓ 选择  s 排序  r 反向  / 筛选  Esc 返回  q 退出")
+	default:
+		help = i18n.T("←/→ 切换  ↑/↓ 选择  s 排序  r 反向  / 筛选  回车 明细  q 退出")
+	}
+	return m.style.Paint(style.Muted, table.Truncate(count+"  "+help, m.width))
+}
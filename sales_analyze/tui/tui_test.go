@@ -0,0 +1,322 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sales-analyzer/report"
+	"sales-analyzer/sales"
+	"sales-analyzer/style"
+)
+
+var testRecords = []sales.Record{
+	{Date: "2025-01-01", Product: "手机", Quantity: 10, Amount: 29990, Region: "华东"},
+	{Date: "2025-01-01", Product: "电脑", Quantity: 2, Amount: 13998, Region: "华南"},
+	{Date: "2025-01-02", Product: "手机", Quantity: 5, Amount: 14995, Region: "华南"},
+	{Date: "2025-01-03", Product: "平板", Quantity: 1, Amount: 1999, Region: "华北"},
+}
+
+func testModel() *model {
+	return newModel(testRecords, "sales.csv", style.New(&style.ThemeDefault, style.NoColor))
+}
+
+// specialKeys 按键名称对应的按键类型，其余名称按输入的字符处理
+var specialKeys = map[string]tea.KeyType{
+	"enter":     tea.KeyEnter,
+	"esc":       tea.KeyEsc,
+	"backspace": tea.KeyBackspace,
+	"tab":       tea.KeyTab,
+	"shift+tab": tea.KeyShiftTab,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"home":      tea.KeyHome,
+	"end":       tea.KeyEnd,
+	"pgdown":    tea.KeyPgDown,
+	"ctrl+c":    tea.KeyCtrlC,
+	"ctrl+u":    tea.KeyCtrlU,
+	" ":         tea.KeySpace,
+}
+
+// press 依次按下 keys，返回最后一个按键的命令
+func press(m *model, keys ...string) tea.Cmd {
+	var cmd tea.Cmd
+	for _, key := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		if t, ok := specialKeys[key]; ok {
+			msg = tea.KeyMsg{Type: t}
+		}
+		_, cmd = m.Update(msg)
+	}
+	return cmd
+}
+
+// typeText 逐个字符输入 text
+func typeText(m *model, text string) {
+	for _, r := range text {
+		press(m, string(r))
+	}
+}
+
+// column 当前表格第 col 列按显示顺序排列的内容
+func column(m *model, col int) []string {
+	g := m.current().grid
+	var values []string
+	for _, index := range g.order {
+		values = append(values, g.rows[index][col].String())
+	}
+	return values
+}
+
+func selected(m *model) string {
+	row := m.current().grid.selected()
+	if row == nil {
+		return ""
+	}
+	return row[0].String()
+}
+
+func TestGridSort(t *testing.T) {
+	g := newGrid()
+	g.load(report.Table{
+		Columns: []string{"名称", "数量"},
+		Rows: [][]report.Cell{
+			{report.Text("乙"), report.Int(5)},
+			{report.Text("甲"), {Kind: report.KindInt}},
+			{report.Text("丙"), report.Int(20)},
+		},
+	})
+	names := func() string {
+		var s []string
+		for _, index := range g.order {
+			s = append(s, g.rows[index][0].String())
+		}
+		return strings.Join(s, "")
+	}
+
+	steps := []struct {
+		name    string
+		action  func()
+		sortCol int
+		desc    bool
+		want    string
+	}{
+		{"原有顺序", func() {}, -1, false, "乙甲丙"},
+		{"文本列升序", func() { g.nextSort(1) }, 0, false, "丙乙甲"},
+		{"数值列降序", func() { g.nextSort(1) }, 1, true, "丙乙甲"},
+		{"反向，空单元格在最前", func() { g.reverse() }, 1, false, "甲乙丙"},
+		{"回到原有顺序", func() { g.nextSort(1) }, -1, false, "乙甲丙"},
+		{"原有顺序不能反向", func() { g.reverse() }, -1, false, "乙甲丙"},
+		{"向前切换到最后一列", func() { g.nextSort(-1) }, 1, true, "丙乙甲"},
+	}
+	for _, step := range steps {
+		step.action()
+		if g.sortCol != step.sortCol || g.desc != step.desc || names() != step.want {
+			t.Errorf("%s: 排序列 %d 降序 %v 顺序 %s，应为 %d %v %s",
+				step.name, g.sortCol, g.desc, names(), step.sortCol, step.desc, step.want)
+		}
+	}
+
+	// 重新载入数据时保留排序方式，光标限制在有效范围内
+	g.move(10)
+	g.load(report.Table{Columns: g.columns, Rows: g.rows[:2]})
+	if g.sortCol != 1 || g.cursor != 1 || names() != "乙甲" {
+		t.Errorf("重新载入后 排序列 %d 光标 %d 顺序 %s，应为 1 1 乙甲", g.sortCol, g.cursor, names())
+	}
+	g.load(report.Table{})
+	if g.cursor != 0 || g.selected() != nil {
+		t.Errorf("没有数据时 光标 %d 选中 %v，应为 0 nil", g.cursor, g.selected())
+	}
+}
+
+func TestGridRender(t *testing.T) {
+	s := style.New(&style.ThemeDefault, style.NoColor)
+
+	t.Run("没有列", func(t *testing.T) {
+		g := newGrid()
+		g.load(report.Table{})
+		if lines := g.render(s, 80, 10, true); len(lines) != 0 {
+			t.Errorf("render() = %q，应为空", lines)
+		}
+	})
+
+	t.Run("滚动", func(t *testing.T) {
+		g := newGrid()
+		table := report.Table{Columns: []string{"序号"}}
+		for _, name := range []string{"一", "二", "三", "四", "五"} {
+			table.Rows = append(table.Rows, []report.Cell{report.Text(name)})
+		}
+		g.load(table)
+		g.move(3)
+		lines := g.render(s, 80, 2, true)
+		if len(lines) != 4 {
+			t.Fatalf("render() = %q，应为表头两行和数据两行", lines)
+		}
+		if !strings.HasPrefix(lines[3], cursorMark) || !strings.Contains(lines[3], "四") || !strings.Contains(lines[2], "三") {
+			t.Errorf("选中第四行时应显示第三、四行并标记第四行: %q", lines)
+		}
+		if lines = g.render(s, 80, 2, false); strings.Contains(strings.Join(lines, "\n"), cursorMark) {
+			t.Errorf("不活动时不应标记选中行: %q", lines)
+		}
+	})
+}
+
+func TestModelNavigation(t *testing.T) {
+	m := testModel()
+
+	press(m, "2")
+	if m.active != 1 {
+		t.Fatalf("按 2 后标签页为 %d，应为 1", m.active)
+	}
+	if got, want := column(m, 0), []string{"手机", "电脑", "平板"}; !slices.Equal(got, want) {
+		t.Errorf("产品 = %v，应为 %v", got, want)
+	}
+
+	moves := []struct {
+		key, want string
+	}{
+		{"down", "电脑"},
+		{"j", "平板"},
+		{"down", "平板"},
+		{"home", "手机"},
+		{"up", "手机"},
+		{"end", "平板"},
+		{"k", "电脑"},
+		{"g", "手机"},
+		{"pgdown", "平板"},
+	}
+	for _, move := range moves {
+		press(m, move.key)
+		if got := selected(m); got != move.want {
+			t.Errorf("按 %s 后选中 %s，应为 %s", move.key, got, move.want)
+		}
+	}
+
+	press(m, "s")
+	if got, want := column(m, 0), []string{"平板", "手机", "电脑"}; !slices.Equal(got, want) {
+		t.Errorf("按产品名排序后 = %v，应为 %v", got, want)
+	}
+	press(m, "r")
+	if got, want := column(m, 0), []string{"电脑", "手机", "平板"}; !slices.Equal(got, want) {
+		t.Errorf("反向后 = %v，应为 %v", got, want)
+	}
+
+	tabs := []struct {
+		key  string
+		want int
+	}{
+		{"tab", 2}, {"right", 3}, {"l", 0}, {"shift+tab", 3}, {"left", 2}, {"h", 1}, {"4", 3}, {"1", 0},
+	}
+	for _, tt := range tabs {
+		press(m, tt.key)
+		if m.active != tt.want {
+			t.Errorf("按 %s 后标签页为 %d，应为 %d", tt.key, m.active, tt.want)
+		}
+	}
+
+	if press(m, "q") == nil {
+		t.Error("按 q 应退出")
+	}
+	if press(m, "ctrl+c") == nil {
+		t.Error("按 Ctrl+C 应退出")
+	}
+}
+
+func TestModelDrillDown(t *testing.T) {
+	m := testModel()
+
+	press(m, "enter")
+	if m.drill != nil {
+		t.Fatal("总览不能下钻")
+	}
+
+	press(m, "2", "down", "enter")
+	if m.drill == nil || m.drill.value != "电脑" {
+		t.Fatalf("下钻到 %+v，应为产品 电脑", m.drill)
+	}
+	if got, want := column(m, 0), []string{"华南"}; !slices.Equal(got, want) {
+		t.Errorf("电脑的地区 = %v，应为 %v", got, want)
+	}
+	if !strings.Contains(m.View(), m.drill.title) {
+		t.Errorf("标签栏中应显示下钻对象 %s", m.drill.title)
+	}
+
+	press(m, "tab")
+	if m.drill.active != 1 || m.active != 1 {
+		t.Errorf("下钻时切换的应是下钻视图的标签页: 下钻 %d 主界面 %d", m.drill.active, m.active)
+	}
+	press(m, "enter")
+	if m.drill.value != "电脑" {
+		t.Error("下钻视图中不能再下钻")
+	}
+
+	// 下钻视图随筛选条件更新
+	press(m, "/")
+	typeText(m, "date:2025-01-02")
+	press(m, "enter")
+	if got := column(m, 0); len(got) != 0 {
+		t.Errorf("筛选后电脑的日期 = %v，应为空", got)
+	}
+
+	press(m, "esc")
+	if m.drill != nil {
+		t.Fatal("按 Esc 应退出下钻视图")
+	}
+	if m.filter.empty() {
+		t.Error("退出下钻视图时不应清除筛选条件")
+	}
+	press(m, "esc")
+	if !m.filter.empty() || m.shown != len(testRecords) {
+		t.Errorf("再按 Esc 应清除筛选条件，显示 %d 条记录", m.shown)
+	}
+}
+
+func TestModelFilter(t *testing.T) {
+	m := testModel()
+	press(m, "3")
+
+	press(m, "/")
+	typeText(m, "region:华南")
+	if !m.editing || m.shown != 2 {
+		t.Fatalf("输入时条件应即时生效: 编辑 %v 显示 %d 条", m.editing, m.shown)
+	}
+	press(m, "enter")
+	if m.editing || m.filter.text != "region:华南" {
+		t.Fatalf("回车后条件为 %q，应为 region:华南", m.filter.text)
+	}
+	if got, want := column(m, 0), []string{"华南"}; !slices.Equal(got, want) {
+		t.Errorf("筛选后地区 = %v，应为 %v", got, want)
+	}
+
+	// 无法解析的条件显示错误，回车后恢复原来的条件
+	press(m, "/", " ")
+	typeText(m, "price:1")
+	if m.err == nil || !strings.Contains(m.View(), m.err.Error()) {
+		t.Errorf("无法解析的条件应显示错误: %v", m.err)
+	}
+	press(m, "enter")
+	if m.err != nil || m.filter.text != "region:华南" {
+		t.Errorf("回车后条件为 %q (错误 %v)，应恢复为 region:华南", m.filter.text, m.err)
+	}
+
+	// Esc 取消编辑，恢复开始编辑前的条件
+	press(m, "/", "ctrl+u")
+	typeText(m, "手表")
+	if m.shown != 0 {
+		t.Errorf("没有匹配的记录时显示 %d 条", m.shown)
+	}
+	m.View()
+	press(m, "esc")
+	if m.editing || m.filter.text != "region:华南" || m.shown != 2 {
+		t.Errorf("Esc 后条件为 %q，显示 %d 条，应恢复为 region:华南", m.filter.text, m.shown)
+	}
+
+	press(m, "/", "backspace", "backspace", "backspace", "enter")
+	if m.filter.text != "region" || m.shown != 0 {
+		t.Errorf("删除后条件为 %q，显示 %d 条", m.filter.text, m.shown)
+	}
+}
@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"sales-analyzer/diff"
+	"sales-analyzer/i18n"
+	"sales-analyzer/report"
+	"sales-analyzer/sales"
+)
+
+// tab 界面中的一个标签页: 一张可排序的表格和表格下方的说明。
+// 内容由 section 从汇总结果生成，筛选条件变化时重新生成，排序方式和光标保留。
+type tab struct {
+	title   string
+	field   string // 表格第一列对应的字段，可以按选中行下钻；为空表示不能下钻
+	section func(*sales.Result) report.Section
+	grid    *grid
+	notes   []report.Note
+}
+
+func newTab(title, field string, section func(*sales.Result) report.Section) *tab {
+	return &tab{title: title, field: field, section: section, grid: newGrid()}
+}
+
+// refresh 按新的汇总结果更新表格和说明
+func (t *tab) refresh(result *sales.Result) {
+	s := t.section(result)
+	var data report.Table
+	if len(s.Tables) > 0 {
+		data = s.Tables[0]
+	}
+	t.grid.load(data)
+	t.notes = s.Notes
+}
+
+// 各维度的分析章节
+func productSection(r *sales.Result) report.Section {
+	return report.ProductSection(sales.AnalyzeByProduct(r))
+}
+
+func regionSection(r *sales.Result) report.Section {
+	return report.RegionSection(sales.AnalyzeByRegion(r))
+}
+
+func dateSection(r *sales.Result) report.Section {
+	return report.DateSection(sales.AnalyzeByDate(r))
+}
+
+// overviewSection 总体指标，附上产品、地区、日期分析的洞察
+func overviewSection(r *sales.Result) report.Section {
+	s := report.OverallSection(sales.AnalyzeOverall(r))
+	for _, section := range []report.Section{productSection(r), regionSection(r), dateSection(r)} {
+		s.Notes = append(s.Notes, section.Notes...)
+	}
+	return s
+}
+
+// mainTabs 主界面的标签页: 总览、产品、地区、日期
+func mainTabs() []*tab {
+	return []*tab{
+		newTab(i18n.T("总览"), "", overviewSection),
+		newTab(i18n.T("产品"), diff.FieldProduct, productSection),
+		newTab(i18n.T("地区"), diff.FieldRegion, regionSection),
+		newTab(i18n.T("日期"), diff.FieldDate, dateSection),
+	}
+}
+
+// drill 下钻视图: 一个产品、地区或日期在其他两个维度上的明细，其中的表格不能再下钻
+type drill struct {
+	field  string
+	value  string
+	title  string
+	tabs   []*tab
+	active int
+}
+
+func newDrill(field, value string) *drill {
+	d := &drill{field: field, value: value}
+	switch field {
+	case diff.FieldProduct:
+		d.title = i18n.Sprintf("产品 %s", value)
+		d.tabs = []*tab{
+			newTab(i18n.T("地区"), "", regionSection),
+			newTab(i18n.T("日期"), "", dateSection),
+		}
+	case diff.FieldRegion:
+		d.title = i18n.Sprintf("地区 %s", value)
+		d.tabs = []*tab{
+			newTab(i18n.T("产品"), "", productSection),
+			newTab(i18n.T("日期"), "", dateSection),
+		}
+	default:
+		d.title = i18n.Sprintf("日期 %s", value)
+		d.tabs = []*tab{
+			newTab(i18n.T("产品"), "", productSection),
+			newTab(i18n.T("地区"), "", regionSection),
+		}
+	}
+	return d
+}
+
+// apply 返回属于下钻对象的记录
+func (d *drill) apply(records []sales.Record) []sales.Record {
+	var matched []sales.Record
+	for _, record := range records {
+		if fieldValue(record, d.field) == d.value {
+			matched = append(matched, record)
+		}
+	}
+	return matched
+}
+
+func fieldValue(record sales.Record, field string) string {
+	switch field {
+	case diff.FieldProduct:
+		return record.Product
+	case diff.FieldRegion:
+		return record.Region
+	default:
+		return record.Date
+	}
+}
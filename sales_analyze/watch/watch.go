@@ -0,0 +1,234 @@
+// Package watch 管理一组输入文件或目录: 按文件缓存读取结果，文件变化后只重新读取变化的文件，
+// 并可以轮询监视变化，在变化停止一段时间 (去抖) 后通知调用方。
+//
+//	inputs := &watch.Inputs{Paths: []string{"data/"}, Schema: sales.DefaultSchema}
+//	snap, err := inputs.Load()
+//	...
+//	inputs.Watch(ctx, time.Second, 300*time.Millisecond, func() {
+//		snap, err := inputs.Load() // 只重新读取变化的文件
+//		...
+//	})
+//
+// 监视通过定期比较文件的大小和修改时间实现，不依赖操作系统的文件通知，
+// 在网络文件系统和容器挂载目录上同样可用。
+package watch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"sales-analyzer/i18n"
+	"sales-analyzer/progress"
+	"sales-analyzer/sales"
+)
+
+// Inputs 一组输入。Paths 中可以是CSV文件或目录，目录中的 *.csv 全部读取，同一文件只读一次。
+// Inputs 不是并发安全的，由调用方串行使用。
+type Inputs struct {
+	Paths  []string
+	Schema sales.Schema
+	// Progress 设置后，每个需要读取的文件显示一个按字节计算的进度条
+	Progress *progress.Progress
+
+	cache  map[string]*file
+	loaded string // 上次成功 Load 时的版本
+}
+
+// file 一个文件的读取结果，大小和修改时间不变时直接复用
+type file struct {
+	size    int64
+	modTime time.Time
+	records []sales.Record
+	skipped []sales.RowError
+}
+
+// Snapshot 一次 Load 的结果
+type Snapshot struct {
+	Files    []string
+	Records  []sales.Record   // 按文件顺序合并的全部记录
+	Skipped  []sales.RowError // 读取时跳过的数据行
+	Reloaded []string         // 本次实际读取的文件，其余文件使用缓存
+	Version  string           // 同 Inputs.Version
+}
+
+// Files 展开 Paths 中的目录，返回要读取的全部文件
+func (in *Inputs) Files() ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(file string) {
+		if abs, err := filepath.Abs(file); err == nil && !seen[abs] {
+			seen[abs] = true
+			files = append(files, file)
+		}
+	}
+	for _, path := range in.Paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, i18n.Errorf("无法打开文件: %w", err)
+		}
+		if !info.IsDir() {
+			add(path)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(path, "*.csv"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			add(match)
+		}
+	}
+	if len(files) == 0 {
+		return nil, i18n.Errorf("没有找到输入文件: %v", in.Paths)
+	}
+	return files, nil
+}
+
+// Version 根据文件名、大小和修改时间计算的版本号，不读取文件内容。
+// 文件被修改、新增或删除时版本随之改变。
+func (in *Inputs) Version() (string, error) {
+	_, _, version, err := in.stat()
+	return version, err
+}
+
+// stat 返回全部文件、各文件的状态和版本号
+func (in *Inputs) stat() ([]string, []os.FileInfo, string, error) {
+	files, err := in.Files()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	infos := make([]os.FileInfo, len(files))
+	h := sha256.New()
+	for i, file := range files {
+		if infos[i], err = os.Stat(file); err != nil {
+			return nil, nil, "", i18n.Errorf("无法打开文件: %w", err)
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00%d\n", file, infos[i].Size(), infos[i].ModTime().UnixNano())
+	}
+	return files, infos, hex.EncodeToString(h.Sum(nil))[:16], nil
+}
+
+// Load 读取全部输入。大小和修改时间与上次相同的文件使用缓存，只重新读取变化的文件，
+// 多个变化的文件并行读取；已经不在输入中的文件从缓存中移除。
+// 任一文件读取失败时返回错误 (多个失败时为排在最前的文件)，缓存保持不变。
+func (in *Inputs) Load() (*Snapshot, error) {
+	files, infos, version, err := in.stat()
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := make([]*file, len(files))
+	errs := make([]error, len(files))
+	var reload []int
+	for i, name := range files {
+		f, ok := in.cache[name]
+		if ok && f.size == infos[i].Size() && f.modTime.Equal(infos[i].ModTime()) {
+			loaded[i] = f
+		} else {
+			reload = append(reload, i)
+		}
+	}
+
+	var wg sync.WaitGroup
+	limit := make(chan struct{}, runtime.GOMAXPROCS(0))
+	for _, i := range reload {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+			loaded[i], errs[i] = in.read(files[i], infos[i])
+		}()
+	}
+	wg.Wait()
+
+	cache := make(map[string]*file, len(files))
+	snap := &Snapshot{Files: files, Version: version}
+	for _, i := range reload {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("%s: %w", files[i], errs[i])
+		}
+		snap.Reloaded = append(snap.Reloaded, files[i])
+	}
+	for i, name := range files {
+		cache[name] = loaded[i]
+		snap.Records = append(snap.Records, loaded[i].records...)
+		snap.Skipped = append(snap.Skipped, loaded[i].skipped...)
+	}
+	in.cache, in.loaded = cache, version
+	return snap, nil
+}
+
+// read 读取一个文件，读取的字节和行数计入 Progress
+func (in *Inputs) read(name string, info os.FileInfo) (*file, error) {
+	bar := in.Progress.Add(filepath.Base(name), info.Size())
+	defer bar.Done()
+
+	r, err := os.Open(name)
+	if err != nil {
+		return nil, i18n.Errorf("无法打开文件: %w", err)
+	}
+	defer r.Close()
+
+	f := &file{size: info.Size(), modTime: info.ModTime()}
+	f.skipped, err = in.Schema.Scan(bar.Reader(r), func(line int, record sales.Record) {
+		f.records = append(f.records, record)
+		bar.AddRows(1)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range f.skipped {
+		f.skipped[i].File = name
+	}
+	return f, nil
+}
+
+// Watch 每隔 interval 检查一次输入，与上次 Load 相比发现变化后，等到连续 debounce 时间内
+// 不再变化再调用 onChange；批量复制文件时只触发一次。输入暂时不可读 (如文件正在替换) 也视为变化，
+// 由 onChange 中的 Load 报告错误。ctx 取消时返回。
+func (in *Inputs) Watch(ctx context.Context, interval, debounce time.Duration, onChange func()) {
+	last := in.loaded
+	if last == "" {
+		version, err := in.Version()
+		if err != nil {
+			version = err.Error()
+		}
+		last = version
+	}
+	pending, since := last, time.Now()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		version, err := in.Version()
+		if err != nil {
+			version = err.Error()
+		}
+		now := time.Now()
+		if version != pending {
+			// 仍在变化，重新计时
+			pending, since = version, now
+			continue
+		}
+		if pending != last && now.Sub(since) >= debounce {
+			last = pending
+			onChange()
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const header = "日期,产品,销量,销售额,地区\n"
+
+// write 写入 dir 中的文件 name，内容为表头和 rows
+func write(t *testing.T, dir, name string, rows ...string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	content := header + strings.Join(rows, "\n")
+	if len(rows) == 0 {
+		content = ""
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func products(snap *Snapshot) []string {
+	var names []string
+	for _, record := range snap.Records {
+		names = append(names, record.Product)
+	}
+	return names
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	a := write(t, dir, "a.csv", "2025-01-01,手机,1,2999,华东")
+	b := write(t, dir, "b.csv", "2025-01-02,电脑,1,6999,华南", "2025-01-02,平板,x,1999,华南")
+	in := &Inputs{Paths: []string{dir, a}}
+
+	load := func(wantReloaded []string, wantProducts ...string) *Snapshot {
+		t.Helper()
+		snap, err := in.Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(snap.Reloaded, wantReloaded) {
+			t.Errorf("Reloaded = %v，应为 %v", snap.Reloaded, wantReloaded)
+		}
+		if got := products(snap); !slices.Equal(got, wantProducts) {
+			t.Errorf("记录 = %v，应为 %v", got, wantProducts)
+		}
+		return snap
+	}
+
+	first := load([]string{a, b}, "手机", "电脑")
+	if !slices.Equal(first.Files, []string{a, b}) {
+		t.Errorf("Files = %v，应为 %v (同一文件只读一次)", first.Files, []string{a, b})
+	}
+	if len(first.Skipped) != 1 || first.Skipped[0].File != b || first.Skipped[0].Line != 3 {
+		t.Errorf("Skipped = %+v，应为 %s 的第3行", first.Skipped, b)
+	}
+
+	t.Run("没有变化", func(t *testing.T) {
+		snap := load(nil, "手机", "电脑")
+		if snap.Version != first.Version {
+			t.Errorf("Version = %s，应为 %s", snap.Version, first.Version)
+		}
+		if len(snap.Skipped) != 1 {
+			t.Errorf("缓存的文件也应报告跳过的行: %+v", snap.Skipped)
+		}
+	})
+
+	t.Run("只重新读取变化的文件", func(t *testing.T) {
+		write(t, dir, "b.csv", "2025-01-02,电脑,2,13998,华南")
+		snap := load([]string{b}, "手机", "电脑")
+		if snap.Version == first.Version {
+			t.Error("文件变化后 Version 应改变")
+		}
+		if snap.Records[1].Quantity != 2 || len(snap.Skipped) != 0 {
+			t.Errorf("应读取修改后的 b.csv: %+v", snap)
+		}
+	})
+
+	t.Run("大小和修改时间不变时使用缓存", func(t *testing.T) {
+		info, err := os.Stat(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		write(t, dir, "a.csv", "2025-01-01,耳机,1,2999,华东") // 长度与原内容相同
+		if err := os.Chtimes(a, info.ModTime(), info.ModTime()); err != nil {
+			t.Fatal(err)
+		}
+		load(nil, "手机", "电脑")
+	})
+
+	t.Run("新增和删除文件", func(t *testing.T) {
+		c := write(t, dir, "c.csv", "2025-01-03,耳机,3,897,华北")
+		load([]string{c}, "手机", "电脑", "耳机")
+		if err := os.Remove(c); err != nil {
+			t.Fatal(err)
+		}
+		load(nil, "手机", "电脑")
+		if _, ok := in.cache[c]; ok {
+			t.Error("已删除的文件应从缓存中移除")
+		}
+	})
+
+	t.Run("读取失败时缓存不变", func(t *testing.T) {
+		cache, loaded := in.cache, in.loaded
+		write(t, dir, "b.csv") // 空文件
+		if _, err := in.Load(); err == nil || !strings.Contains(err.Error(), b) {
+			t.Fatalf("Load() 错误 = %v，应为 %s 的错误", err, b)
+		}
+		if in.loaded != loaded || len(in.cache) != len(cache) || in.cache[a] != cache[a] || in.cache[b] != cache[b] {
+			t.Error("读取失败后缓存被修改")
+		}
+
+		write(t, dir, "b.csv", "2025-01-02,平板,1,1999,华南")
+		load([]string{b}, "手机", "平板")
+	})
+
+	t.Run("输入不存在", func(t *testing.T) {
+		missing := &Inputs{Paths: []string{filepath.Join(dir, "missing.csv")}}
+		if _, err := missing.Load(); err == nil {
+			t.Error("文件不存在时应返回错误")
+		}
+		if _, err := (&Inputs{Paths: []string{t.TempDir()}}).Load(); err == nil {
+			t.Error("目录中没有CSV文件时应返回错误")
+		}
+	})
+}
+
+// TestWatch 一连串修改在停止变化 debounce 之后只通知一次
+func TestWatch(t *testing.T) {
+	const (
+		interval = 10 * time.Millisecond
+		debounce = 100 * time.Millisecond
+	)
+	dir := t.TempDir()
+	write(t, dir, "a.csv", "2025-01-01,手机,1,2999,华东")
+	in := &Inputs{Paths: []string{dir}}
+	if _, err := in.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := make(chan struct{}, 10)
+	done := make(chan struct{})
+	go func() {
+		in.Watch(ctx, interval, debounce, func() { changes <- struct{}{} })
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	select {
+	case <-changes:
+		t.Fatal("文件没有变化时不应通知")
+	case <-time.After(3 * debounce):
+	}
+
+	rows := []string{"2025-01-01,手机,1,2999,华东"}
+	for i := range 5 {
+		rows = append(rows, "2025-01-02,电脑,1,6999,华南")
+		write(t, dir, "a.csv", rows...)
+		if i == 2 {
+			write(t, dir, "b.csv", "2025-01-03,耳机,3,897,华北")
+		}
+		time.Sleep(2 * interval)
+	}
+
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("文件变化后没有通知")
+	}
+	select {
+	case <-changes:
+		t.Fatal("一连串的修改应只通知一次")
+	case <-time.After(3 * debounce):
+	}
+
+	write(t, dir, "b.csv", "2025-01-03,耳机,4,1196,华北")
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("再次变化后没有通知")
+	}
+}